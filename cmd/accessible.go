@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/ui"
+	"github.com/chzyer/readline"
+	"github.com/manifoldco/promptui"
+)
+
+// accessibleMode reports whether prompts should be plain, numbered and
+// sequential for screen readers, from --accessible or accessible in the
+// config.
+func accessibleMode() bool {
+	return accessible || cfg.Accessible
+}
+
+// runSelect asks the prompter to pick from a list. It returns the chosen
+// index and label like promptui.Select.Run.
+func runSelect(prompt promptui.Select) (int, string, error) {
+	return prompter.Select(prompt)
+}
+
+// runPrompt asks the prompter for text or a confirmation. It returns like
+// promptui.Prompt.Run, including promptui.ErrAbort for a declined
+// confirmation.
+func runPrompt(prompt promptui.Prompt) (string, error) {
+	return prompter.Prompt(prompt)
+}
+
+// Select shows a selector, or a numbered list read as plain text in
+// accessible mode. ctrl-c stops commitz, while Esc only closes the
+// selector or, with keys.escape: default, keeps the pre-selected item.
+func (terminalPrompter) Select(prompt promptui.Select) (int, string, error) {
+	if !accessibleMode() {
+		applyKeys(&prompt)
+		esc := ui.NewEscReader(os.Stdin)
+		prompt.Stdin = readline.NewCancelableStdin(esc)
+		idx, label, err := prompt.Run()
+		switch {
+		case !errors.Is(err, promptui.ErrInterrupt):
+		case !esc.Pressed():
+			// ctrl-c stops commitz in every prompt
+			interrupt()
+		case escapeKeepsDefault():
+			idx = prompt.CursorPos
+			return idx, fmt.Sprint(reflect.ValueOf(prompt.Items).Index(idx).Interface()), nil
+		}
+		return idx, label, err
+	}
+
+	items := reflect.ValueOf(prompt.Items)
+	labels := make([]string, items.Len())
+	for i := range labels {
+		labels[i] = plainItem(items.Index(i).Interface())
+	}
+
+	fmt.Println()
+	fmt.Println(ui.PlainLabel(prompt.Label))
+	for i, label := range labels {
+		fmt.Printf("  %d) %s\n", i+1, label)
+	}
+	for {
+		fmt.Print(tr("accessible.choice", len(labels), prompt.CursorPos+1))
+		answer, err := stdinLine()
+		if err != nil {
+			return 0, "", promptui.ErrEOF
+		}
+		answer = strings.TrimSpace(answer)
+		if answer == "" {
+			return prompt.CursorPos, labels[prompt.CursorPos], nil
+		}
+		n, err := strconv.Atoi(answer)
+		if err == nil && n >= 1 && n <= len(labels) {
+			return n - 1, labels[n-1], nil
+		}
+		fmt.Println(tr("deselect.bad_number", answer))
+	}
+}
+
+// Prompt asks for text or a confirmation, with a plain line of input in
+// accessible mode.
+func (terminalPrompter) Prompt(prompt promptui.Prompt) (string, error) {
+	if !accessibleMode() {
+		result, err := prompt.Run()
+		if errors.Is(err, promptui.ErrInterrupt) {
+			interrupt()
+		}
+		return result, err
+	}
+
+	label := ui.PlainLabel(prompt.Label)
+	for {
+		switch {
+		case prompt.IsConfirm:
+			fmt.Print(tr("accessible.confirm", label))
+		case prompt.Default != "":
+			fmt.Print(tr("accessible.default", label, prompt.Default))
+		default:
+			fmt.Print(tr("accessible.input", label))
+		}
+
+		answer, err := stdinLine()
+		if err != nil {
+			return "", promptui.ErrEOF
+		}
+		answer = strings.TrimSpace(answer)
+		if prompt.IsConfirm {
+			if isYes(answer) || (answer == "" && isYes(prompt.Default)) {
+				return answer, nil
+			}
+			return answer, promptui.ErrAbort
+		}
+		if answer == "" {
+			answer = prompt.Default
+		}
+		if prompt.Validate != nil {
+			if err := prompt.Validate(answer); err != nil {
+				fmt.Println(err)
+				continue
+			}
+		}
+		return answer, nil
+	}
+}
+
+// plainItem describes a selector item as one line of text.
+func plainItem(item any) string {
+	if ct, ok := item.(CommitType); ok {
+		return ct.Type + " - " + ct.Description
+	}
+	return fmt.Sprint(item)
+}
@@ -0,0 +1,283 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Where audit.store keeps the bypass records.
+const (
+	auditFile  = "file"
+	auditNotes = "notes"
+)
+
+// auditLogFile is kept in the git directory, next to the session log.
+const auditLogFile = "commitz-audit.jsonl"
+
+// auditNotesRef holds the records for audit.store: notes. Unlike the log
+// file it can be pushed for review.
+const auditNotesRef = "commitz-audit"
+
+// maxHookOutputLines bounds the hook output kept per record.
+const maxHookOutputLines = 20
+
+// bypassEntry records a commit made with --no-verify after its hooks
+// rejected it.
+type bypassEntry struct {
+	Time   time.Time `json:"time"`
+	Commit string    `json:"commit"`
+	Branch string    `json:"branch,omitempty"`
+	Author string    `json:"author"`
+	Header string    `json:"header"`
+	// Via names the command that skipped the hooks, e.g. rebase-msgs for
+	// its amends; commits with "Retry with --no-verify" leave it empty.
+	Via string `json:"via,omitempty"`
+	// Rules are the lint problems of the message, as "rule: problem".
+	Rules []string `json:"rules,omitempty"`
+	// HookOutput is the end of what the rejecting hooks printed.
+	HookOutput string `json:"hook_output,omitempty"`
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "List commits that were made with the hooks skipped",
+	Long: `With audit.enabled, every commit made through commitz with --no-verify
+is recorded: who made it, when, the lint rules the message breaks and what
+the rejecting hooks printed. The amends of rebase-msgs, which skip the
+hooks, are recorded too. audit.store keeps the records in
+.git/commitz-audit.jsonl (file, the default) or as git notes under
+refs/notes/commitz-audit (notes), which can be pushed for review.`,
+	Example: `  commitz audit
+  commitz audit --json
+  git push origin refs/notes/commitz-audit`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !cfg.Audit.Enabled {
+			color.Yellow("%s", tr("audit.off"))
+		}
+		entries, err := loadBypasses()
+		if err != nil {
+			color.Red("%s", tr("audit.read_error", err))
+			os.Exit(1)
+		}
+
+		if auditJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetEscapeHTML(false)
+			for _, e := range entries {
+				if err := enc.Encode(e); err != nil {
+					color.Red("%s", tr("audit.read_error", err))
+					os.Exit(1)
+				}
+			}
+			return
+		}
+		if len(entries) == 0 {
+			fmt.Println(tr("audit.empty"))
+			return
+		}
+		for _, e := range entries {
+			displayBypass(e)
+		}
+	},
+}
+
+// auditRecordCmd records the amends rebase-msgs makes with --no-verify.
+// It runs from the rebase todo list, so amends are recorded even when the
+// rebase stops and is continued by hand.
+var auditRecordCmd = &cobra.Command{
+	Use:    "record <via>",
+	Short:  "Record HEAD as committed with the hooks skipped",
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		out, err := gitOutput("log", "-1", "--format=%B")
+		if err != nil {
+			color.Red("%s", tr("audit.store_error", err))
+			os.Exit(1)
+		}
+		recordBypass(strings.TrimSpace(string(out)), "", args[0])
+	},
+}
+
+var auditJSON bool
+
+func init() {
+	auditCmd.Flags().BoolVar(&auditJSON, "json", false, "Print one JSON record per line")
+	auditCmd.AddCommand(auditRecordCmd)
+	rootCmd.AddCommand(auditCmd)
+}
+
+// auditExec is the rebase todo line that records the commit just made
+// with the hooks skipped, or "" when audit.enabled is off.
+func auditExec(via string) (string, error) {
+	if !cfg.Audit.Enabled {
+		return "", nil
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	line := "exec " + shellQuote(exe)
+	if cfgFile != "" {
+		line += " --config " + shellQuote(cfgFile)
+	}
+	return line + " audit record " + via + "\n", nil
+}
+
+// newBypassEntry describes HEAD, just committed with message by via after
+// hooks printed hookOutput and failed.
+func newBypassEntry(message, hookOutput, via string) bypassEntry {
+	header, _, _ := strings.Cut(message, "\n")
+	entry := bypassEntry{
+		Time:       time.Now().UTC(),
+		Header:     header,
+		Via:        via,
+		HookOutput: lastLines(strings.TrimSpace(hookOutput), maxHookOutputLines),
+	}
+	if out, err := gitOutput("log", "-1", "--format=%H%n%an <%ae>"); err == nil {
+		entry.Commit, entry.Author, _ = strings.Cut(strings.TrimSpace(string(out)), "\n")
+	}
+	if out, err := gitOutput("branch", "--show-current"); err == nil {
+		entry.Branch = strings.TrimSpace(string(out))
+	}
+	for _, issue := range lintMessage(message) {
+		entry.Rules = append(entry.Rules, issue.Rule+": "+issue.Message)
+	}
+	return entry
+}
+
+// recordBypass records HEAD as committed with the hooks skipped when
+// audit.enabled is set. Failures are reported but do not undo the commit.
+func recordBypass(message, hookOutput, via string) {
+	if !cfg.Audit.Enabled {
+		return
+	}
+
+	// Keep "<" and ">" of the author readable in the notes and the log
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	err := enc.Encode(newBypassEntry(message, hookOutput, via))
+	if err == nil {
+		err = storeBypass(buf.Bytes())
+	}
+	if err != nil {
+		color.Red("%s", tr("audit.store_error", err))
+		return
+	}
+	fmt.Println(tr("audit.recorded"))
+}
+
+func storeBypass(data []byte) error {
+	switch strings.ToLower(cfg.Audit.Store) {
+	case "", auditFile:
+		path, err := gitDirPath(auditLogFile)
+		if err != nil {
+			return err
+		}
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(data); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	case auditNotes:
+		notes := gitCommand("notes", "--ref="+auditNotesRef, "add", "-f", "-F", "-", "HEAD")
+		notes.Stdin = bytes.NewReader(data)
+		if out, err := notes.CombinedOutput(); err != nil {
+			return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+	return errors.New(tr("audit.unknown_store", cfg.Audit.Store))
+}
+
+// loadBypasses reads the records from audit.store, oldest first for the
+// log file and in commit order for notes.
+func loadBypasses() ([]bypassEntry, error) {
+	var entries []bypassEntry
+	add := func(line []byte) {
+		var e bypassEntry
+		if err := json.Unmarshal(line, &e); err != nil {
+			logger.Info("skipping unreadable audit record", "error", err)
+			return
+		}
+		entries = append(entries, e)
+	}
+
+	switch strings.ToLower(cfg.Audit.Store) {
+	case "", auditFile:
+		path, err := gitDirPath(auditLogFile)
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+		for scanner.Scan() {
+			add(scanner.Bytes())
+		}
+		return entries, scanner.Err()
+	case auditNotes:
+		// Notes on commits that are no longer reachable are left out
+		out, err := gitOutput("log", "--reverse", "--notes="+auditNotesRef, "--format=%N%x00")
+		if err != nil {
+			return nil, nil
+		}
+		for _, note := range bytes.Split(out, []byte{0}) {
+			if note = bytes.TrimSpace(note); len(note) > 0 {
+				add(note)
+			}
+		}
+		return entries, nil
+	}
+	return nil, errors.New(tr("audit.unknown_store", cfg.Audit.Store))
+}
+
+func displayBypass(e bypassEntry) {
+	commit := e.Commit
+	if len(commit) > 7 {
+		commit = commit[:7]
+	}
+	fmt.Printf("%s  %s  %s  %s\n", color.YellowString("%s", commit), e.Time.Local().Format("2006-01-02 15:04"), e.Author, e.Header)
+	if e.Branch != "" {
+		fmt.Println("  " + tr("audit.branch", e.Branch))
+	}
+	if e.Via != "" {
+		fmt.Println("  " + tr("audit.via", e.Via))
+	}
+	for _, rule := range e.Rules {
+		fmt.Println("  - " + rule)
+	}
+	if e.HookOutput != "" {
+		fmt.Println("  " + tr("audit.hook_output"))
+		for _, line := range strings.Split(e.HookOutput, "\n") {
+			fmt.Println(color.New(color.Faint).Sprint("    " + line))
+		}
+	}
+	fmt.Println()
+}
+
+// lastLines keeps the last n lines of s.
+func lastLines(s string, n int) string {
+	lines := strings.Split(s, "\n")
+	return strings.Join(lines[max(0, len(lines)-n):], "\n")
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/barisdilekci/commitz/internal/config"
+)
+
+func TestRecordBypass(t *testing.T) {
+	testRepo(t, []string{"commit", "-q", "--allow-empty", "-m", "Fixed the thing."})
+
+	defer func(c config.AuditConfig) { cfg.Audit = c }(cfg.Audit)
+	for _, store := range []string{auditFile, auditNotes} {
+		cfg.Audit = config.AuditConfig{Enabled: true, Store: store}
+		recordBypass("Fixed the thing.", "running tests\nFAIL: TestParse\n", "")
+
+		entries, err := loadBypasses()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 {
+			t.Fatalf("%s: got %d records, want 1", store, len(entries))
+		}
+		e := entries[0]
+		if len(e.Commit) != 40 || e.Branch != "main" || e.Header != "Fixed the thing." || !strings.Contains(e.HookOutput, "FAIL: TestParse") {
+			t.Errorf("%s: record = %+v", store, e)
+		}
+		if len(e.Rules) == 0 || !strings.HasPrefix(e.Rules[0], "header-format: ") {
+			t.Errorf("%s: rules = %q, want the header-format problem", store, e.Rules)
+		}
+	}
+
+	cfg.Audit = config.AuditConfig{Store: auditFile}
+	recordBypass("Fixed the thing.", "", "")
+	if entries, _ := loadBypasses(); len(entries) != 1 {
+		t.Errorf("disabled audit recorded a bypass: %d records", len(entries))
+	}
+
+	// The amends of rebase-msgs skip the hooks too
+	commits := []rangeCommit{{Hash: "abc1234", Message: "Fixed the thing."}}
+	rewritten := map[string]string{"abc1234": "fix: handle the thing"}
+	for _, enabled := range []bool{false, true} {
+		cfg.Audit.Enabled = enabled
+		todo, err := rebaseTodo(commits, rewritten, t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Contains(todo, " audit record rebase-msgs\n"); got != enabled {
+			t.Errorf("audit.enabled %v: todo records the amend = %v:\n%s", enabled, got, todo)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"github.com/zalando/go-keyring"
+)
+
+// keychainService names the commitz entries in the OS keychain.
+const keychainService = "commitz"
+
+// authProvider is a service commitz can log in to. Environment variables
+// win over the keychain, so CI keeps working without one.
+type authProvider struct {
+	Name string
+	Env  []string
+	// UserEnv is set for services that pair the token with a user name.
+	UserEnv string
+}
+
+var authProviders = []authProvider{
+	{Name: "github", Env: []string{"GITHUB_TOKEN", "GH_TOKEN"}},
+	{Name: "gitlab", Env: []string{"GITLAB_TOKEN"}},
+	{Name: "jira", Env: []string{"JIRA_TOKEN"}, UserEnv: "JIRA_USER"},
+}
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Keep GitHub, GitLab and Jira tokens in the OS keychain",
+	Long: `Tokens for releases and issue lookups are read from environment
+variables, then from the OS keychain (macOS Keychain, Windows Credential
+Manager or the Secret Service on Linux). login stores a token there so it
+does not have to live in a shell profile.`,
+	Example: `  commitz auth login github
+  echo "$TOKEN" | commitz auth login gitlab
+  commitz auth status
+  commitz auth logout jira`,
+}
+
+var authLoginCmd = &cobra.Command{
+	Use:       "login <provider>",
+	Short:     "Store a token in the OS keychain",
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs: authProviderNames(),
+	Run: func(cmd *cobra.Command, args []string) {
+		provider, _ := findAuthProvider(args[0])
+		if provider.UserEnv != "" {
+			user, ok := askSecret(tr("auth.user_prompt", provider.Name), false)
+			if !ok {
+				color.Yellow("%s", tr("commit.cancelled"))
+				os.Exit(1)
+			}
+			if err := storeKeychain(provider.Name+"-user", user); err != nil {
+				color.Red("%s", tr("auth.store_error", err))
+				os.Exit(1)
+			}
+		}
+
+		token, ok := askSecret(tr("auth.token_prompt", provider.Name), true)
+		if !ok || token == "" {
+			color.Yellow("%s", tr("commit.cancelled"))
+			os.Exit(1)
+		}
+		if err := keyring.Set(keychainService, provider.Name, token); err != nil {
+			color.Red("%s", tr("auth.store_error", err))
+			os.Exit(1)
+		}
+		color.Green("%s", tr("auth.stored", provider.Name))
+		if env := setEnv(provider.Env); env != "" {
+			color.Yellow("%s", tr("auth.env_wins", env))
+		}
+	},
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show where each token comes from",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, provider := range authProviders {
+			_, source := authToken(provider)
+			if source == "" {
+				source = color.New(color.Faint).Sprint(tr("auth.none"))
+			}
+			fmt.Printf("  %-8s %s\n", provider.Name, source)
+		}
+	},
+}
+
+var authLogoutCmd = &cobra.Command{
+	Use:       "logout <provider>",
+	Short:     "Remove a token from the OS keychain",
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs: authProviderNames(),
+	Run: func(cmd *cobra.Command, args []string) {
+		provider, _ := findAuthProvider(args[0])
+		err := keyring.Delete(keychainService, provider.Name)
+		if provider.UserEnv != "" {
+			_ = keyring.Delete(keychainService, provider.Name+"-user")
+		}
+		switch {
+		case errors.Is(err, keyring.ErrNotFound):
+			fmt.Println(tr("auth.not_stored", provider.Name))
+		case err != nil:
+			color.Red("%s", tr("auth.delete_error", err))
+			os.Exit(1)
+		default:
+			color.Green("%s", tr("auth.removed", provider.Name))
+		}
+	},
+}
+
+func init() {
+	authCmd.AddCommand(authLoginCmd, authStatusCmd, authLogoutCmd)
+	rootCmd.AddCommand(authCmd)
+}
+
+func authProviderNames() []string {
+	var names []string
+	for _, p := range authProviders {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func findAuthProvider(name string) (authProvider, bool) {
+	for _, p := range authProviders {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+	return authProvider{}, false
+}
+
+// authToken returns a provider's token and where it was found, or "" when
+// it is not set anywhere commitz looks.
+func authToken(provider authProvider) (token, source string) {
+	if env := setEnv(provider.Env); env != "" {
+		return os.Getenv(env), "$" + env
+	}
+	if token := readKeychain(provider.Name); token != "" {
+		return token, tr("auth.keychain")
+	}
+	return "", ""
+}
+
+// authUser returns the user name stored with a token, if the provider
+// uses one.
+func authUser(provider authProvider) string {
+	if provider.UserEnv == "" {
+		return ""
+	}
+	if user := os.Getenv(provider.UserEnv); user != "" {
+		return user
+	}
+	return readKeychain(provider.Name + "-user")
+}
+
+// providerToken looks up a token by provider name.
+func providerToken(name string) string {
+	provider, _ := findAuthProvider(name)
+	token, _ := authToken(provider)
+	return token
+}
+
+// setEnv returns the first of the variables that is set.
+func setEnv(names []string) string {
+	for _, name := range names {
+		if os.Getenv(name) != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// readKeychain returns "" when the entry is missing or there is no
+// keychain, as on headless Linux without a Secret Service.
+func readKeychain(key string) string {
+	secret, err := keyring.Get(keychainService, key)
+	if err != nil {
+		if !errors.Is(err, keyring.ErrNotFound) {
+			logger.Debug("keychain not readable", "key", key, "error", err)
+		}
+		return ""
+	}
+	return secret
+}
+
+// storeKeychain stores value, or removes the entry when value is empty.
+func storeKeychain(key, value string) error {
+	if value == "" {
+		if err := keyring.Delete(keychainService, key); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+			return err
+		}
+		return nil
+	}
+	return keyring.Set(keychainService, key, value)
+}
+
+// askSecret reads a value, masked on a terminal. Piped input is read as a
+// single line, so tokens never have to appear in the shell history.
+func askSecret(label string, mask bool) (string, bool) {
+	if isatty.IsTerminal(os.Stdin.Fd()) {
+		prompt := promptui.Prompt{Label: label}
+		if mask {
+			prompt.Mask = '*'
+		}
+		result, err := runPrompt(prompt)
+		return strings.TrimSpace(result), err == nil
+	}
+
+	answer, err := readLine()
+	return strings.TrimSpace(answer), err == nil
+}
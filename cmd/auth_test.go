@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestAuthToken(t *testing.T) {
+	keyring.MockInit()
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("JIRA_USER", "")
+
+	github, _ := findAuthProvider("github")
+	if token, source := authToken(github); token != "" || source != "" {
+		t.Errorf("authToken() = %q, %q with nothing set", token, source)
+	}
+
+	if err := keyring.Set(keychainService, "github", "from-keychain"); err != nil {
+		t.Fatal(err)
+	}
+	if token, source := authToken(github); token != "from-keychain" || source != tr("auth.keychain") {
+		t.Errorf("authToken() = %q, %q; want the keychain token", token, source)
+	}
+
+	t.Setenv("GH_TOKEN", "from-env")
+	if token, source := authToken(github); token != "from-env" || source != "$GH_TOKEN" {
+		t.Errorf("authToken() = %q, %q; want $GH_TOKEN", token, source)
+	}
+
+	jira, _ := findAuthProvider("jira")
+	if err := storeKeychain("jira-user", "ada@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if user := authUser(jira); user != "ada@example.com" {
+		t.Errorf("authUser() = %q", user)
+	}
+	if err := storeKeychain("jira-user", ""); err != nil {
+		t.Fatal(err)
+	}
+	if user := authUser(jira); user != "" {
+		t.Errorf("authUser() = %q after removing it", user)
+	}
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// baselineDateLayouts are the date formats accepted for --baseline.
+var baselineDateLayouts = []string{"2006-01-02", time.RFC3339}
+
+// lintBaseline grandfathers commits that existed before lint was adopted:
+// those reachable from Ref, or authored before Since.
+type lintBaseline struct {
+	Ref   string
+	Since time.Time
+}
+
+// resolveBaseline reads --baseline, falling back to lint.baseline in the
+// config. The value is a revision or a date.
+func resolveBaseline() (lintBaseline, bool, error) {
+	value := lintBaselineFlag
+	if value == "" {
+		value = cfg.Lint.Baseline
+	}
+	if value == "" {
+		return lintBaseline{}, false, nil
+	}
+
+	if out, err := gitOutput("rev-parse", "--verify", "-q", value+"^{commit}"); err == nil {
+		return lintBaseline{Ref: strings.TrimSpace(string(out))}, true, nil
+	}
+	for _, layout := range baselineDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return lintBaseline{Since: t}, true, nil
+		}
+	}
+	return lintBaseline{}, false, errors.New(tr("lint.bad_baseline", value))
+}
+
+// covers reports whether a commit is grandfathered. Author dates are used
+// because rebasing keeps them while changing the committer date.
+func (b lintBaseline) covers(hash string, authored time.Time) bool {
+	if b.Ref != "" {
+		return gitRun("merge-base", "--is-ancestor", hash, b.Ref) == nil
+	}
+	return authored.Before(b.Since)
+}
+
+// rebasedCommit returns the original commit when a rebase is rewriting one,
+// so the commit-msg hook can tell an old message from a new one.
+func rebasedCommit() (string, time.Time, bool) {
+	out, err := gitOutput("rev-parse", "--git-path", "rebase-merge/done")
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	f, err := os.Open(strings.TrimSpace(string(out)))
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	defer f.Close()
+
+	// The last done line is the commit being picked, e.g. "reword 1a2b3c4 ..."
+	var hash string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) >= 2 && !strings.HasPrefix(fields[0], "#") {
+			hash = fields[1]
+		}
+	}
+	if hash == "" {
+		return "", time.Time{}, false
+	}
+
+	out, err = gitOutput("log", "-1", "--format=%H %at", hash)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	full, unix, _ := strings.Cut(strings.TrimSpace(string(out)), " ")
+	seconds, _ := strconv.ParseInt(unix, 10, 64)
+	return full, time.Unix(seconds, 0), true
+}
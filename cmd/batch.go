@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Per-repository results of a batch run.
+const (
+	batchCommitted = "committed"
+	batchPreviewed = "dry_run"
+	batchClean     = "clean"
+	batchFailed    = "failed"
+)
+
+var (
+	batchRepos    []string
+	batchStageAll bool
+)
+
+type batchResult struct {
+	Repo    string
+	Status  string
+	Subject string
+	Output  string
+}
+
+var batchCmd = &cobra.Command{
+	Use:   "batch",
+	Short: "Generate and create commits in many repositories at once",
+	Long: `Runs commitz without prompts in every repository matched by --repos and
+prints a summary table at the end. Repositories without staged changes are
+skipped. Useful for the same change across many services, such as a
+dependency bump.`,
+	Example: `  commitz batch --repos "~/work/*" -a -t build -s deps
+  commitz batch --repos "~/work/api,~/work/web" --dry-run`,
+	Run: func(cmd *cobra.Command, args []string) {
+		repos := expandRepos(batchRepos)
+		if len(repos) == 0 {
+			color.Yellow("%s", tr("batch.no_repos"))
+			os.Exit(1)
+		}
+
+		var results []batchResult
+		for _, repo := range repos {
+			fmt.Println(color.CyanString("%s", tr("batch.repo", repo)))
+			results = append(results, commitInRepo(repo))
+		}
+
+		if displayBatchResults(results) {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(batchCmd)
+
+	batchCmd.Flags().StringSliceVar(
+		&batchRepos,
+		"repos",
+		nil,
+		"Repository directories or glob patterns, comma separated (required)",
+	)
+	_ = batchCmd.MarkFlagRequired("repos")
+
+	batchCmd.Flags().BoolVarP(
+		&batchStageAll,
+		"all",
+		"a",
+		false,
+		"Stage all changes in each repository first",
+	)
+}
+
+// expandRepos resolves ~ and glob patterns to git repositories.
+func expandRepos(patterns []string) []string {
+	home, _ := os.UserHomeDir()
+
+	var repos []string
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if rest, ok := strings.CutPrefix(pattern, "~/"); ok && home != "" {
+			pattern = filepath.Join(home, rest)
+		}
+
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			color.Red("%s", tr("batch.bad_pattern", pattern, err))
+			continue
+		}
+		for _, dir := range matches {
+			if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil && !contains(repos, dir) {
+				repos = append(repos, dir)
+			}
+		}
+	}
+	return repos
+}
+
+// commitInRepo runs commitz in a repository as a child process, so each
+// repository gets its own config, caches and learning data.
+func commitInRepo(repo string) batchResult {
+	result := batchResult{Repo: repo}
+
+	if batchStageAll {
+		if err := gitRun("-C", repo, "add", "-A"); err != nil {
+			result.Status, result.Output = batchFailed, err.Error()
+			return result
+		}
+	}
+	if err := gitRun("-C", repo, "diff", "--cached", "--quiet"); err == nil {
+		result.Status = batchClean
+		return result
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		result.Status, result.Output = batchFailed, err.Error()
+		return result
+	}
+
+	child := exec.CommandContext(rootCtx, exe, childArgs()...)
+	child.Dir = repo
+	var output bytes.Buffer
+	child.Stdout = &output
+	child.Stderr = &output
+	err = child.Run()
+	result.Output = output.String()
+
+	switch {
+	case err != nil:
+		result.Status = batchFailed
+	case dryRun:
+		result.Status = batchPreviewed
+		result.Subject = proposedSubject(result.Output)
+	default:
+		result.Status = batchCommitted
+		if out, err := gitOutput("-C", repo, "log", "-1", "--format=%s"); err == nil {
+			result.Subject = strings.TrimSpace(string(out))
+		}
+	}
+	return result
+}
+
+// childArgs passes the message options of this run on to each repository.
+func childArgs() []string {
+	args := []string{"--yes"}
+	if commitType != "" {
+		args = append(args, "--type", commitType)
+	}
+	if commitScope != "" {
+		args = append(args, "--scope", commitScope)
+	}
+	if useEmoji {
+		args = append(args, "--emoji")
+	}
+	if useGitmoji {
+		args = append(args, "--gitmoji")
+	}
+	if dryRun {
+		args = append(args, "--dry-run")
+	}
+	if cfgFile != "" {
+		if abs, err := filepath.Abs(cfgFile); err == nil {
+			args = append(args, "--config", abs)
+		}
+	}
+	return args
+}
+
+// proposedSubject picks the subject from a dry run's output, which ends
+// with the proposed message.
+func proposedSubject(output string) string {
+	_, message, ok := strings.Cut(output, tr("message.proposed"))
+	if !ok {
+		return ""
+	}
+	subject, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	return subject
+}
+
+// displayBatchResults prints the summary table and reports whether any
+// repository failed.
+func displayBatchResults(results []batchResult) bool {
+	failed := false
+	for _, r := range results {
+		if r.Status == batchFailed {
+			failed = true
+			fmt.Println()
+			color.Red("%s", tr("batch.failed", r.Repo))
+			for _, line := range strings.Split(strings.TrimSpace(r.Output), "\n") {
+				fmt.Println("  " + line)
+			}
+		}
+	}
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, tr("batch.table_header"))
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", r.Repo, tr("batch.status_"+r.Status), r.Subject)
+	}
+	w.Flush()
+	return failed
+}
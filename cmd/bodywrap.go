@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/message"
+)
+
+// bodyWidth is the column bodies are wrapped at. It is negative when
+// body.wrap turns wrapping off.
+func bodyWidth() int {
+	if cfg.Body.Wrap == 0 {
+		return bodyWrapWidth
+	}
+	return cfg.Body.Wrap
+}
+
+// wrapBody hard-wraps the body of a message at body.wrap. List items keep
+// their indentation, and code blocks, URLs and footers stay as they are.
+func wrapBody(msg string) string {
+	width := bodyWidth()
+	header, rest, ok := strings.Cut(msg, "\n")
+	if width <= 0 || !ok {
+		return msg
+	}
+
+	lines := strings.Split(strings.TrimRight(rest, "\n"), "\n")
+	// Footers are parsed by git and tools line by line
+	footers := len(message.ParseFooters(msg))
+	body, _ := message.WrapLines(lines[:len(lines)-footers], width, width)
+	return header + "\n" + strings.Join(append(body, lines[len(lines)-footers:]...), "\n")
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// defaultBranchPattern takes the scope from the part before the first
+// "/", as in feature/login.
+var defaultBranchPattern = regexp.MustCompile(`^(?P<scope>[^/]+)/`)
+
+// warnedBranchPatterns keeps an invalid branch.patterns entry from being
+// reported every time the branch is parsed.
+var warnedBranchPatterns = map[string]bool{}
+
+// branchParts is what the branch name says about the change.
+type branchParts struct {
+	Scope  string
+	Ticket string
+	// Pattern is the branch.patterns entry that matched, "" for the
+	// default pattern.
+	Pattern string
+}
+
+// parseBranch matches a branch name against branch.patterns in order and
+// then the default pattern. The named groups "scope" and "ticket" give
+// the values.
+func parseBranch(branch string) (branchParts, bool) {
+	for _, pattern := range cfg.Branch.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			if !warnedBranchPatterns[pattern] {
+				color.Yellow("%s", tr("branch.invalid_pattern", pattern, err))
+				warnedBranchPatterns[pattern] = true
+			}
+			continue
+		}
+		if parts, ok := matchBranch(re, branch); ok {
+			parts.Pattern = pattern
+			return parts, true
+		}
+	}
+	return matchBranch(defaultBranchPattern, branch)
+}
+
+func matchBranch(re *regexp.Regexp, branch string) (branchParts, bool) {
+	m := re.FindStringSubmatch(branch)
+	if m == nil {
+		return branchParts{}, false
+	}
+	var parts branchParts
+	if i := re.SubexpIndex("scope"); i >= 0 {
+		parts.Scope = strings.TrimSpace(m[i])
+	}
+	if i := re.SubexpIndex("ticket"); i >= 0 {
+		parts.Ticket = strings.TrimSpace(m[i])
+	}
+	return parts, true
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import "testing"
+
+func TestParseBranch(t *testing.T) {
+	defer func(patterns []string) { cfg.Branch.Patterns = patterns }(cfg.Branch.Patterns)
+	cfg.Branch.Patterns = []string{
+		`^users/[^/]+/\w+-(?P<scope>.+)$`,
+		`^(?P<ticket>[A-Z]+-\d+)-`,
+		`^release/`,
+	}
+
+	tests := []struct {
+		branch        string
+		scope, ticket string
+		pattern       int
+		ok            bool
+	}{
+		{"users/baris/feat-login", "login", "", 0, true},
+		{"JIRA-123-fix-crash", "", "JIRA-123", 1, true},
+		{"release/2.0", "", "", 2, true},
+		// The default takes the part before the first "/"
+		{"feature/auth", "feature", "", -1, true},
+		{"main", "", "", -1, false},
+	}
+	for _, tt := range tests {
+		parts, ok := parseBranch(tt.branch)
+		want := ""
+		if tt.pattern >= 0 {
+			want = cfg.Branch.Patterns[tt.pattern]
+		}
+		if ok != tt.ok || parts.Scope != tt.scope || parts.Ticket != tt.ticket || parts.Pattern != want {
+			t.Errorf("parseBranch(%q) = %+v, %v; want scope %q, ticket %q, pattern %q",
+				tt.branch, parts, ok, tt.scope, tt.ticket, want)
+		}
+	}
+}
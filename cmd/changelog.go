@@ -0,0 +1,417 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/barisdilekci/commitz/internal/config"
+	"github.com/barisdilekci/commitz/internal/message"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// defaultChangelogTemplate renders markdown in the style of
+// conventional-changelog.
+const defaultChangelogTemplate = `## {{ with compareURL .From .To }}[{{ $.Version }}]({{ . }}){{ else }}{{ .Version }}{{ end }} ({{ .Date }})
+{{- if .Breaking }}
+
+### ⚠ Breaking Changes
+{{ range .Breaking }}
+- {{ if .Scope }}**{{ .Scope }}:** {{ end }}{{ linkIssues .BreakingNote }}
+{{- end }}
+{{- end }}
+{{- range .Sections }}
+
+### {{ .Title }}
+{{ if $.GroupByScope }}
+{{- range .Groups }}
+{{- if .Scope }}
+- **{{ .Scope }}**
+{{- range .Commits }}
+  - {{ template "entry" (entry $ .) }}
+{{- end }}
+{{- else }}
+{{- range .Commits }}
+- {{ template "entry" (entry $ .) }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- else }}
+{{- range .Commits }}
+- {{ if .Scope }}**{{ .Scope }}:** {{ end }}{{ template "entry" (entry $ .) }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{ define "entry" }}{{ linkIssues .Commit.Subject }} ({{ commitLink .Commit }})
+{{- if and .Changelog.Authors .Commit.Author }} by {{ .Commit.Author }}{{ end }}
+{{- if and .Changelog.PRLinks .Commit.PR }} ({{ prLink .Commit.PR }}){{ end }}{{ end }}
+`
+
+// pullRequestPattern finds PR numbers in squash ("subject (#12)") and
+// merge ("Merge pull request #12") subjects.
+var (
+	pullRequestSuffix = regexp.MustCompile(`\s*\(#(\d+)\)$`)
+	pullRequestMerge  = regexp.MustCompile(`^Merge pull request #(\d+)`)
+	breakingFooter    = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: (.+)$`)
+)
+
+var (
+	changelogFrom     string
+	changelogTo       string
+	changelogTemplate string
+	changelogOutput   string
+	changelogScope    string
+)
+
+// historyCommit is a commit with its conventional fields parsed.
+type historyCommit struct {
+	Hash         string
+	Short        string
+	Author       string
+	Date         time.Time
+	Type         string
+	Scope        string
+	Subject      string
+	Body         string
+	Breaking     bool
+	BreakingNote string
+	PR           int
+}
+
+type changelogGroup struct {
+	Scope   string
+	Commits []historyCommit
+}
+
+type changelogSection struct {
+	Title   string
+	Commits []historyCommit
+	// Groups holds the commits by scope, unscoped commits last.
+	Groups []changelogGroup
+}
+
+// changelogData is what changelog templates can refer to.
+type changelogData struct {
+	Version      string
+	Date         string
+	From         string
+	To           string
+	Breaking     []historyCommit
+	Sections     []changelogSection
+	Authors      bool
+	PRLinks      bool
+	GroupByScope bool
+}
+
+// defaultChangelogSections is used when changelog.sections is not set.
+var defaultChangelogSections = []config.ChangelogSection{
+	{Title: "Features", Types: []string{"feat"}},
+	{Title: "Bug Fixes", Types: []string{"fix"}},
+	{Title: "Performance Improvements", Types: []string{"perf"}},
+	{Title: "Reverts", Types: []string{"revert"}},
+}
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog",
+	Short: "Generate a changelog from conventional commits",
+	Long: `Generates release notes from the conventional commits between two
+revisions, by default from the latest tag to HEAD. Commit types map to
+sections through changelog.sections in the config, and the layout can be
+replaced with a Go template.
+
+In a monorepo, --scope limits the changelog to one package, starting from
+that package's latest tag, such as auth/v1.2.0.`,
+	Example: `  commitz changelog
+  commitz changelog --from v1.2.0 --to v1.3.0 -o CHANGELOG.md
+  commitz changelog --template .github/changelog.tmpl
+  commitz changelog --scope pkg/auth`,
+	Run: func(cmd *cobra.Command, args []string) {
+		data, err := buildChangelog(changelogFrom, changelogTo, changelogScope)
+		if err != nil {
+			color.Red("%s", tr("changelog.error", err))
+			os.Exit(1)
+		}
+
+		text, err := renderChangelog(data)
+		if err != nil {
+			color.Red("%s", tr("changelog.template_error", err))
+			os.Exit(1)
+		}
+
+		if changelogOutput == "" || changelogOutput == "-" {
+			fmt.Print(text)
+			return
+		}
+		if err := os.WriteFile(changelogOutput, []byte(text), 0o644); err != nil {
+			color.Red("%s", tr("changelog.write_error", changelogOutput, err))
+			os.Exit(1)
+		}
+		color.Green("%s", tr("changelog.written", changelogOutput))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(changelogCmd)
+
+	changelogCmd.Flags().StringVar(
+		&changelogFrom,
+		"from",
+		"",
+		"Start after this revision (default: the latest tag)",
+	)
+
+	changelogCmd.Flags().StringVar(
+		&changelogTo,
+		"to",
+		"HEAD",
+		"End at this revision",
+	)
+
+	changelogCmd.Flags().StringVar(
+		&changelogTemplate,
+		"template",
+		"",
+		"Go template file to render with (overrides changelog.template)",
+	)
+
+	changelogCmd.Flags().StringVar(
+		&changelogScope,
+		"scope",
+		"",
+		"Only include commits touching this path or using its name as scope",
+	)
+
+	addHistoryFilterFlags(changelogCmd)
+
+	changelogCmd.Flags().StringVarP(
+		&changelogOutput,
+		"output",
+		"o",
+		"",
+		"Write to this file instead of stdout",
+	)
+}
+
+// buildChangelog collects and sorts the commits between from and to,
+// limited to a monorepo package when scope is set.
+func buildChangelog(from, to, scope string) (changelogData, error) {
+	checkHistoryDepth()
+	if from == "" {
+		from = previousTag(to, tagPrefix(scope), false)
+	}
+	commits, err := scopedCommits(from, to, scope)
+	if err != nil {
+		return changelogData{}, err
+	}
+
+	settings := cfg.Changelog
+	data := changelogData{
+		Version:      releaseLabel(to),
+		Date:         releaseDate(to),
+		From:         from,
+		To:           to,
+		Authors:      settings.Authors,
+		PRLinks:      settings.PRLinks,
+		GroupByScope: settings.GroupByScope,
+	}
+
+	sections := settings.Sections
+	if len(sections) == 0 {
+		sections = defaultChangelogSections
+	}
+	for _, s := range sections {
+		section := changelogSection{Title: s.Title}
+		for _, c := range commits {
+			if contains(s.Types, c.Type) {
+				section.Commits = append(section.Commits, c)
+			}
+		}
+		if len(section.Commits) > 0 {
+			section.Groups = groupCommitsByScope(section.Commits)
+			data.Sections = append(data.Sections, section)
+		}
+	}
+	for _, c := range commits {
+		if c.Breaking {
+			data.Breaking = append(data.Breaking, c)
+		}
+	}
+	return data, nil
+}
+
+func groupCommitsByScope(commits []historyCommit) []changelogGroup {
+	index := map[string]int{}
+	var groups []changelogGroup
+	for _, c := range commits {
+		// "API", "api" and " api" are one group, named as scopes.case
+		// spells it or as it first appears
+		scope := strings.TrimSpace(normalizeScope(strings.TrimSpace(c.Scope)))
+		key := strings.ToLower(scope)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, changelogGroup{Scope: scope})
+		}
+		groups[i].Commits = append(groups[i].Commits, c)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Scope == "" || groups[j].Scope == "" {
+			return groups[j].Scope == ""
+		}
+		return strings.ToLower(groups[i].Scope) < strings.ToLower(groups[j].Scope)
+	})
+	return groups
+}
+
+// renderChangelog executes the --template file, changelog.template or the
+// default template.
+func renderChangelog(data changelogData) (string, error) {
+	text := defaultChangelogTemplate
+	path := changelogTemplate
+	if path == "" {
+		path = cfg.Changelog.Template
+	}
+	if path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		text = string(content)
+	}
+
+	funcs := template.FuncMap(hostingFuncs())
+	// entry passes the commit and the options to a nested template
+	funcs["entry"] = func(d changelogData, c historyCommit) map[string]any {
+		return map[string]any{"Changelog": d, "Commit": c}
+	}
+	tmpl, err := template.New("changelog").Funcs(funcs).Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// previousTag returns the latest tag before rev, or "" when there is none.
+// When rev itself is tagged, the tag before it is used. With a package
+// prefix only that package's tags count; without one, package tags like
+// auth/v1.0.0 are ignored. With final, pre-release tags like v1.1.0-rc.1
+// are skipped too.
+func previousTag(rev, prefix string, final bool) string {
+	args := []string{"describe", "--tags", "--abbrev=0", "--exclude", "*/*"}
+	if prefix != "" {
+		args = []string{"describe", "--tags", "--abbrev=0", "--match", prefix + "v*"}
+	}
+	if final {
+		args = append(args, "--exclude", prefix+"*.*.*-*")
+	}
+
+	out, err := gitOutput(append(args, rev)...)
+	if err != nil {
+		return ""
+	}
+	tag := strings.TrimSpace(string(out))
+
+	tagged, err1 := gitOutput("rev-parse", tag+"^{commit}")
+	head, err2 := gitOutput("rev-parse", rev+"^{commit}")
+	if err1 == nil && err2 == nil && string(tagged) == string(head) {
+		out, err = gitOutput(append(args, rev+"^")...)
+		if err != nil {
+			return ""
+		}
+		tag = strings.TrimSpace(string(out))
+	}
+	return tag
+}
+
+// releaseLabel names the release: the tag when rev is one, else
+// "Unreleased".
+func releaseLabel(rev string) string {
+	if err := gitRun("show-ref", "--verify", "-q", "refs/tags/"+rev); err == nil {
+		return rev
+	}
+	return tr("changelog.unreleased")
+}
+
+// releaseDate is the day rev was committed, or today.
+func releaseDate(rev string) string {
+	if out, err := gitOutput("log", "-1", "--format=%cs", rev); err == nil {
+		return strings.TrimSpace(string(out))
+	}
+	return time.Now().Format("2006-01-02")
+}
+
+// historyCommits lists the commits in from..to, oldest first, with their
+// conventional fields. Commits that are not conventional keep an empty
+// Type.
+func historyCommits(from, to string) ([]historyCommit, error) {
+	revRange := to
+	if from != "" {
+		revRange = from + ".." + to
+	}
+
+	out, err := gitOutput(historyFilters.logArgs("log", "--reverse", "--format=%x1e%H%x00%an%x00%at%x00%B", revRange)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []historyCommit
+	for _, record := range strings.Split(string(out), "\x1e") {
+		fields := strings.SplitN(record, "\x00", 4)
+		if len(fields) < 4 {
+			continue
+		}
+		seconds, _ := strconv.ParseInt(fields[2], 10, 64)
+		commits = append(commits, parseHistoryCommit(fields[0], fields[1], time.Unix(seconds, 0), fields[3]))
+	}
+	return commits, nil
+}
+
+func parseHistoryCommit(hash, author string, date time.Time, msg string) historyCommit {
+	msg = strings.TrimSpace(msg)
+	header, body, _ := strings.Cut(msg, "\n")
+
+	c := historyCommit{
+		Hash:    hash,
+		Short:   hash[:min(7, len(hash))],
+		Author:  author,
+		Date:    date,
+		Subject: header,
+		Body:    strings.TrimSpace(body),
+	}
+
+	if m := pullRequestMerge.FindStringSubmatch(header); m != nil {
+		c.PR, _ = strconv.Atoi(m[1])
+	}
+	if m := pullRequestSuffix.FindStringSubmatch(header); m != nil {
+		c.PR, _ = strconv.Atoi(m[1])
+		header = strings.TrimSuffix(header, m[0])
+		c.Subject = header
+	}
+
+	if h, ok := message.ParseHeader(header); ok {
+		c.Type = strings.ToLower(h.Type)
+		c.Scope = h.Scope
+		c.Subject = h.Subject
+		c.Breaking = h.Breaking
+	}
+	if m := breakingFooter.FindStringSubmatch(body); m != nil {
+		c.Breaking = true
+		c.BreakingNote = m[1]
+	}
+	if c.Breaking && c.BreakingNote == "" {
+		c.BreakingNote = c.Subject
+	}
+	return c
+}
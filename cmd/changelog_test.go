@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/barisdilekci/commitz/internal/config"
+)
+
+func TestGroupCommitsByScope(t *testing.T) {
+	defer func(c config.ScopesConfig) { cfg.Scopes = c }(cfg.Scopes)
+
+	commits := []historyCommit{
+		{Subject: "add login", Scope: "API"},
+		{Subject: "fix typo"},
+		{Subject: "add logout", Scope: "api"},
+		{Subject: "cache tokens", Scope: " Auth "},
+		{Subject: "rotate keys", Scope: "auth"},
+	}
+
+	tests := []struct {
+		mode   string
+		scopes []string
+	}{
+		{"", []string{"API", "Auth", ""}},
+		{scopeCaseLower, []string{"api", "auth", ""}},
+	}
+	for _, tt := range tests {
+		cfg.Scopes.Case = tt.mode
+		groups := groupCommitsByScope(commits)
+		if len(groups) != len(tt.scopes) {
+			t.Fatalf("scopes.case %q: got %d groups, want %d: %+v", tt.mode, len(groups), len(tt.scopes), groups)
+		}
+		for i, g := range groups {
+			if g.Scope != tt.scopes[i] {
+				t.Errorf("scopes.case %q: group %d = %q, want %q", tt.mode, i, g.Scope, tt.scopes[i])
+			}
+		}
+		if len(groups[0].Commits) != 2 || len(groups[1].Commits) != 2 {
+			t.Errorf("scopes.case %q: groups = %+v", tt.mode, groups)
+		}
+	}
+}
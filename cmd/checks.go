@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+var skipChecks bool
+
+// runChecks runs the configured checks commands from the repository root
+// and reports whether all of them passed. Like template commands, each
+// check has to be trusted first; a refused one counts as failed.
+func runChecks() bool {
+	if len(cfg.Checks) == 0 || skipChecks {
+		return true
+	}
+
+	dir, err := repoRoot()
+	if err != nil {
+		dir = "."
+	}
+
+	fmt.Println()
+	for _, command := range cfg.Checks {
+		if !trustCommand(command) {
+			color.Red("%s", tr("template.untrusted", command))
+			fmt.Println(tr("checks.failed_hint"))
+			return false
+		}
+		if !runCheck(command, dir) {
+			color.Red("%s", tr("checks.failed", command))
+			fmt.Println(tr("checks.failed_hint"))
+			return false
+		}
+	}
+	return true
+}
+
+func runCheck(command, dir string) bool {
+	cmd := shellCommand(rootCtx, command)
+	cmd.Dir = dir
+
+	spinner := startSpinner(command)
+	out := &checkOutput{spinner: spinner}
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	runningCommands.Add(1)
+	start := time.Now()
+	err := cmd.Run()
+	runningCommands.Done()
+	spinner.stop()
+	if out.partial {
+		fmt.Println()
+	}
+
+	elapsed := time.Since(start).Round(100 * time.Millisecond)
+	if err != nil {
+		fmt.Printf("%s %s %s\n", color.RedString("%s", displayText("✗")), command, color.New(color.Faint).Sprint(elapsed))
+		return false
+	}
+	fmt.Printf("%s %s %s\n", color.GreenString("%s", displayText("✓")), command, color.New(color.Faint).Sprint(elapsed))
+	return true
+}
+
+// spinner animates a status line on terminals until the command prints
+// something or finishes.
+type spinner struct {
+	label    string
+	animated bool
+	done     chan struct{}
+	stopped  sync.Once
+	wg       sync.WaitGroup
+}
+
+func startSpinner(label string) *spinner {
+	s := &spinner{label: label, done: make(chan struct{})}
+	if !isatty.IsTerminal(os.Stdout.Fd()) {
+		fmt.Printf("%s %s\n", displayText("…"), label)
+		return s
+	}
+
+	frames := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	if !emojiSupported() {
+		frames = []string{"|", "/", "-", "\\"}
+	}
+
+	s.animated = true
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			fmt.Printf("\r%s %s", color.CyanString("%s", frames[i%len(frames)]), s.label)
+			select {
+			case <-s.done:
+				fmt.Print("\r\033[K")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return s
+}
+
+func (s *spinner) stop() {
+	s.stopped.Do(func() {
+		close(s.done)
+		s.wg.Wait()
+	})
+}
+
+// checkOutput streams a check's output indented below its label, stopping
+// the spinner on the first write.
+type checkOutput struct {
+	spinner *spinner
+	mu      sync.Mutex
+	started bool
+	partial bool
+}
+
+func (o *checkOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if !o.started {
+		o.spinner.stop()
+		// The spinner line is cleared, so keep the label above the output
+		if o.spinner.animated {
+			fmt.Printf("%s %s\n", displayText("…"), o.spinner.label)
+		}
+		o.started = true
+	}
+
+	for _, line := range strings.SplitAfter(string(p), "\n") {
+		if line == "" {
+			continue
+		}
+		if !o.partial {
+			io.WriteString(os.Stdout, "  │ ")
+		}
+		io.WriteString(os.Stdout, line)
+		o.partial = !strings.HasSuffix(line, "\n")
+	}
+	return len(p), nil
+}
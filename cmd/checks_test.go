@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChecksNeedTrust(t *testing.T) {
+	repo := testRepo(t)
+	saved := cfg.Checks
+	t.Cleanup(func() { cfg.Checks = saved })
+	cfg.Checks = []string{"touch ran"}
+
+	// A check from a cloned repository's config is not run until trusted
+	useScript(t, promptStep{Answer: "n"})
+	if runChecks() {
+		t.Error("runChecks() passed with an untrusted check")
+	}
+	if _, err := os.Stat(filepath.Join(repo, "ran")); err == nil {
+		t.Fatal("untrusted check was run")
+	}
+
+	useScript(t, promptStep{Answer: "y"})
+	if !runChecks() {
+		t.Error("runChecks() failed with a trusted check")
+	}
+	if _, err := os.Stat(filepath.Join(repo, "ran")); err != nil {
+		t.Error("trusted check was not run")
+	}
+
+	// Once trusted, it runs without asking
+	useScript(t)
+	if !runChecks() {
+		t.Error("runChecks() asked again for a trusted check")
+	}
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+	"github.com/mattn/go-isatty"
+)
+
+// cloneInfo tells how much of the history is in this clone. A shallow
+// clone stops at a cut-off commit; a partial clone leaves objects on the
+// remote and downloads them when a command reads them.
+type cloneInfo struct {
+	Shallow bool
+	Partial bool
+	Filter  string
+	Remote  string
+}
+
+var repoClone = sync.OnceValue(loadCloneInfo)
+
+// historyChecked makes the clone warning appear once per run, even when a
+// command walks history several times.
+var historyChecked bool
+
+func loadCloneInfo() cloneInfo {
+	var info cloneInfo
+	if out, err := gitOutput("rev-parse", "--is-shallow-repository"); err == nil {
+		info.Shallow = strings.TrimSpace(string(out)) == "true"
+	}
+
+	out, err := gitOutput("config", "-z", "--get-regexp", `^(extensions\.partialclone|remote\..*\.partialclonefilter)$`)
+	if err == nil {
+		// With -z every entry is "key\nvalue" terminated by NUL
+		for _, entry := range bytes.Split(out, []byte{0}) {
+			key, value, _ := strings.Cut(string(entry), "\n")
+			switch {
+			case key == "extensions.partialclone":
+				info.Partial, info.Remote = true, value
+			case strings.HasSuffix(key, ".partialclonefilter"):
+				info.Partial, info.Filter = true, value
+				if info.Remote == "" {
+					info.Remote = strings.TrimSuffix(strings.TrimPrefix(key, "remote."), ".partialclonefilter")
+				}
+			}
+		}
+	}
+
+	logger.Debug("clone", "info", info)
+	return info
+}
+
+// unshallowArgs is the fetch that completes the history of a shallow
+// clone. A partial clone keeps its filter, so only commits and trees are
+// downloaded, not every file ever committed.
+func unshallowArgs(info cloneInfo) []string {
+	args := []string{"fetch", "--unshallow"}
+	if info.Filter != "" {
+		args = append(args, "--filter="+info.Filter)
+	}
+	if remote := info.Remote; remote != "" {
+		args = append(args, remote)
+	} else if remote = canonicalRemote(); remote != "" {
+		args = append(args, remote)
+	}
+	return args
+}
+
+// checkHistoryDepth warns before walking history in a shallow or partial
+// clone, since results stop at the cut-off commit or may trigger
+// downloads. Fetching the rest of a shallow history is offered, but never
+// done without an explicit yes. Messages go to stderr so output such as a
+// piped changelog stays clean.
+func checkHistoryDepth() {
+	info := repoClone()
+	if historyChecked || (!info.Shallow && !info.Partial) {
+		return
+	}
+	historyChecked = true
+
+	if info.Partial {
+		fmt.Fprintln(os.Stderr, color.YellowString("%s", tr("clone.partial")))
+	}
+	if !info.Shallow {
+		return
+	}
+	fmt.Fprintln(os.Stderr, color.YellowString("%s", tr("clone.shallow")))
+
+	args := unshallowArgs(info)
+	command := "git " + strings.Join(args, " ")
+	if assumeYes || !isatty.IsTerminal(os.Stdin.Fd()) || skipOffline("offline.unshallow") || !confirmUnshallow(command) {
+		fmt.Fprintln(os.Stderr, tr("clone.unshallow_hint", command))
+		return
+	}
+
+	fetch := gitCommand(args...)
+	fetch.Stdout, fetch.Stderr = os.Stderr, os.Stderr
+	if err := fetch.Run(); err != nil {
+		fmt.Fprintln(os.Stderr, color.RedString("%s", tr("clone.unshallow_failed", err)))
+		return
+	}
+	resetRepoCaches()
+	fmt.Fprintln(os.Stderr, color.GreenString("%s", tr("clone.unshallowed")))
+}
+
+// confirmUnshallow defaults to no: the fetch can take long on a large
+// repository.
+func confirmUnshallow(command string) bool {
+	if interactive {
+		prompt := promptui.Prompt{
+			Label:     tr("clone.unshallow_confirm", command),
+			IsConfirm: true,
+			Stdout:    os.Stderr,
+		}
+		result, err := runPrompt(prompt)
+		return err == nil && isYes(result)
+	}
+
+	fmt.Fprint(os.Stderr, tr("clone.unshallow_confirm_plain", command))
+	answer, _ := readLine()
+	return isYes(answer)
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"os/exec"
+	"slices"
+	"testing"
+)
+
+func TestLoadCloneInfo(t *testing.T) {
+	origin := testRepo(t,
+		[]string{"commit", "-q", "--allow-empty", "-m", "feat: first"},
+		[]string{"commit", "-q", "--allow-empty", "-m", "fix: second"},
+	)
+	clone := t.TempDir()
+	if out, err := exec.Command("git", "clone", "-q", "--depth=1", "file://"+origin, clone).CombinedOutput(); err != nil {
+		t.Fatalf("git clone: %v\n%s", err, out)
+	}
+	t.Chdir(clone)
+	resetRepoCaches()
+
+	info := repoClone()
+	if !info.Shallow || info.Partial {
+		t.Fatalf("repoClone() = %+v, want a shallow clone", info)
+	}
+	if got := unshallowArgs(info); !slices.Equal(got, []string{"fetch", "--unshallow", "origin"}) {
+		t.Errorf("unshallowArgs() = %v", got)
+	}
+
+	info = cloneInfo{Shallow: true, Partial: true, Filter: "blob:none", Remote: "upstream"}
+	if got := unshallowArgs(info); !slices.Equal(got, []string{"fetch", "--unshallow", "--filter=blob:none", "upstream"}) {
+		t.Errorf("unshallowArgs() = %v", got)
+	}
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+)
+
+// executeCommit runs git commit with the message. Hook output is shown as it
+// is produced and copied to hookOutput.
+func executeCommit(message string, noVerify bool, hookOutput io.Writer) error {
+	args := []string{"commit", "-F", "-"}
+	if noVerify {
+		args = append(args, "--no-verify")
+	}
+	// commitz prints its own summary of the commit
+	args = append(args, "--quiet")
+
+	commitCmd := gitCommand(args...)
+	commitCmd.Stdin = strings.NewReader(message)
+	commitCmd.Stdout = os.Stdout
+	commitCmd.Stderr = io.MultiWriter(os.Stderr, hookOutput)
+	runningCommands.Add(1)
+	defer runningCommands.Done()
+	return commitCmd.Run()
+}
+
+// Choices offered after a failed commit.
+const (
+	retryCommit = iota
+	retryNoVerify
+	keepDraft
+	abortCommit
+)
+
+// commitWithRetry commits the message and, when git or a hook rejects it,
+// lets the user fix the problem and retry instead of losing the message. It
+// reports whether the commit was made. Commits that skip the hooks are
+// recorded when audit.enabled is set.
+func commitWithRetry(message string, interactive bool) bool {
+	noVerify := false
+	var hookOutput string
+	for {
+		var output bytes.Buffer
+		err := executeCommit(message, noVerify, &output)
+		if err == nil {
+			if quiet {
+				printCommitResult()
+			} else {
+				displayCommitResult()
+			}
+			if noVerify {
+				recordBypass(message, hookOutput, "")
+			}
+			return true
+		}
+
+		awaitInterrupt()
+		hookOutput = output.String()
+		color.Red("%s", tr("commit.failed", err))
+		switch selectCommitRecovery(interactive) {
+		case retryCommit:
+			noVerify = false
+		case retryNoVerify:
+			noVerify = true
+		case keepDraft:
+			fmt.Println(tr("commit.draft_kept"))
+			return false
+		default:
+			clearDraft()
+			return false
+		}
+	}
+}
+
+func selectCommitRecovery(interactive bool) int {
+	if assumeYes {
+		return keepDraft
+	}
+
+	options := []string{
+		tr("commit.retry"),
+		tr("commit.retry_no_verify"),
+		tr("commit.keep_draft"),
+		tr("commit.abort"),
+	}
+
+	if interactive {
+		prompt := promptui.Select{
+			Label: tr("commit.recover"),
+			Items: options,
+		}
+		idx, _, err := runSelect(prompt)
+		if err != nil {
+			return keepDraft
+		}
+		return idx
+	}
+
+	fmt.Println()
+	for i, option := range options {
+		fmt.Printf("  %d) %s\n", i+1, option)
+	}
+	fmt.Print(tr("commit.recover_plain"))
+	answer, err := readLine()
+	if err != nil {
+		return keepDraft
+	}
+	choice, err := strconv.Atoi(strings.TrimSpace(answer))
+	if err != nil || choice < 1 || choice > len(options) {
+		return keepDraft
+	}
+	return choice - 1
+}
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/detect"
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/barisdilekci/commitz/internal/message"
+	"github.com/barisdilekci/commitz/internal/summary"
+	"github.com/fatih/color"
+)
+
+// composeMessage detects or asks for the type, scope and summary and
+// returns the full message including the optional description.
+func composeMessage(diffStr string, files []git.File) string {
+	var selectedType string
+	var selectedScope string
+	var selectedEmoji string
+
+	detectedType, typeReason, weak := explainCommitType(diffStr, files)
+	// The ticket says more about the change than a keyword in the diff
+	if weak {
+		if ticketType, reason, ok := ticketCommitType(); ok {
+			detectedType, typeReason = ticketType, reason
+		}
+	}
+	if learned, count, ok := learnedType(files, detectedType); ok {
+		typeReason = tr("why.learned", count, changeArea(files), learned, detectedType)
+		detectedType = learned
+	}
+	detectedScope, scopeReason := explainScopeFromBranch()
+	scopeLabel := tr("prompt.scope_from_branch", detectedScope)
+	// On trunk every commit is on main; the staged paths say more
+	var pathScopes []string
+	if trunkMode() {
+		detectedScope, scopeReason, pathScopes = explainScopeFromPaths(files)
+		scopeLabel = tr("prompt.scope_from_paths", detectedScope)
+	}
+
+	// Security-sensitive changes should be easy to find later
+	sensitive, marker := securitySensitive(files)
+	summaryType := detectedType
+	if sensitive {
+		logger.Info("security-sensitive change", "marker", marker)
+		if t := cfg.Security.Type; t != "" {
+			detectedType, typeReason = t, tr("why.security", marker)
+		}
+		if detectedScope == "" {
+			detectedScope, scopeReason = securityScope(), tr("why.security", marker)
+			scopeLabel = tr("prompt.scope_security", detectedScope)
+		}
+	}
+	// The preset or types may leave out what the diff looks like
+	if allowed := allowedType(detectedType); allowed != detectedType {
+		typeReason = tr("why.type_not_allowed", detectedType, allowed)
+		detectedType = allowed
+	}
+	logger.Info("detected type", "type", detectedType, "reason", typeReason)
+	logger.Info("detected scope", "scope", detectedScope, "reason", scopeReason)
+	markStartup("detect")
+	reportStartupProfile()
+
+	// Interactive mode
+	if interactive {
+		selectedType, selectedEmoji = selectCommitTypeInteractive(detectedType)
+		selectedScope = selectScopeInteractive(detectedScope, scopeLabel, pathScopes)
+		if selectedType != detectedType {
+			typeReason = tr("why.selected_type", detectedType, typeReason)
+		}
+		if selectedScope != detectedScope {
+			scopeReason = tr("why.selected_scope")
+		}
+	} else {
+		// Auto-detect or use provided flags
+		selectedType = detectedType
+		if commitType != "" {
+			selectedType = commitType
+			typeReason = tr("why.type_flag")
+		}
+
+		selectedScope = detectedScope
+		if detectedScope != "" {
+			session.ScopeCandidates = []string{detectedScope}
+		}
+		if pathScopes != nil {
+			session.ScopeCandidates = pathScopes
+		}
+		switch {
+		case commitScope != "":
+			selectedScope = commitScope
+			scopeReason = tr("why.scope_flag")
+		case trunkMode() && !assumeYes:
+			selectedScope = askScopePlain(detectedScope, pathScopes)
+			if selectedScope != detectedScope {
+				scopeReason = tr("why.selected_scope")
+			}
+		}
+
+		selectedEmoji = getEmojiForType(selectedType)
+	}
+
+	selectedScope = normalizeScope(selectedScope)
+	session.DetectedType = detectedType
+	session.SelectedType = selectedType
+	session.DetectedScope = detectedScope
+	session.Scope = selectedScope
+	session.TypeReason = typeReason
+	session.ScopeReason = scopeReason
+
+	// A security type is phrased like the type the change would have had
+	if selectedType != cfg.Security.Type {
+		summaryType = selectedType
+	}
+
+	// Generate summary with smart suggestion
+	subject := generateSummaryInteractive(interactive, diffStr, files, summaryType)
+
+	// An edited summary is the user's call; a suggestion too long for the
+	// header leaves the details to the body
+	if subject == session.SuggestedSummary {
+		subject = fitSummary(selectedEmoji, selectedType, selectedScope, subject, files)
+		session.Summary = subject
+	}
+
+	// Build commit message
+	emoji := messageEmoji(selectedEmoji)
+	msg := message.Build(message.Header{Type: selectedType, Scope: selectedScope, Subject: subject}, emoji)
+	if body := summary.Body(files, selectedType, subject, gitOutput); body != "" {
+		msg = message.MarkBreaking(msg + "\n\n" + body)
+	}
+	msg = message.AddEmoji(msg, emoji)
+
+	// The session log compares what commitz would have committed on its
+	// own with the final message
+	session.SuggestedMessage = msg
+	if interactive {
+		header := message.Header{Type: detectedType, Scope: normalizeScope(detectedScope), Subject: session.SuggestedSummary}
+		machine := message.Build(header, messageEmoji(getEmojiForType(detectedType)))
+		if _, rest, ok := strings.Cut(msg, "\n"); ok {
+			machine += "\n" + rest
+		}
+		session.SuggestedMessage = machine
+	}
+
+	// Display suggested message
+	displaySuggestedMessage(msg)
+	if showWhy {
+		displayExplanation(selectedType, typeReason, selectedScope, scopeReason)
+	}
+
+	saveDraft(msg)
+
+	// Add optional description
+	if sensitive && !assumeYes {
+		color.Yellow("%s", tr("security.body_hint", marker))
+	}
+	msg = wrapBody(addDescriptionInteractive(msg, interactive, sensitive))
+	return applyCommitTemplate(applyMessageTemplate(msg))
+}
+
+// fitSummary replaces a suggested summary that would push the header past
+// the length limit with a shorter one, see summary.Shorten.
+func fitSummary(emoji, commitType, scope, suggested string, files []git.File) string {
+	header := message.Header{Type: commitType, Scope: scope, Subject: suggested}
+	if message.Width(message.Build(header, messageEmoji(emoji))) <= maxHeaderLength {
+		return suggested
+	}
+	return summary.Shorten(suggested, commitType, files)
+}
+
+// loadStagedFiles streams the staged diff through the parser, or reads only
+// per-file statistics in --stat-only mode.
+func loadStagedFiles() ([]git.File, error) {
+	if statOnly {
+		out, err := gitOutput("diff", "--cached", "--numstat", "--summary")
+		if err != nil {
+			return nil, err
+		}
+		return git.ParseNumstat(string(out)), nil
+	}
+
+	var files []git.File
+	err := gitStream(func(r io.Reader) error {
+		var err error
+		files, err = git.ParseDiffReader(r, diffCapture.gitOptions())
+		return err
+	}, diffCapture.args("diff", "--cached")...)
+	if err == nil && diffCapture.IgnoreAllSpace {
+		files, err = addWhitespaceOnlyFiles(files, "diff", "--cached")
+	}
+	if err == nil && !detect.OnlyWhitespace(files) {
+		markWhitespaceOnly(files, "--cached")
+	}
+	return files, err
+}
+
+func displaySuggestedMessage(message string) {
+	if quiet {
+		return
+	}
+	fmt.Println()
+	color.Green("%s", tr("message.suggested"))
+	fmt.Printf("  %s\n", color.GreenString("%s", message))
+}
+
+func displayExplanation(commitType, typeReason, scope, scopeReason string) {
+	if scope == "" {
+		scope = tr("why.none")
+	}
+
+	fmt.Println()
+	color.Cyan("%s", tr("why.title"))
+	fmt.Printf("  type   %-10s %s\n", commitType, typeReason)
+	fmt.Printf("  scope  %-10s %s\n", scope, scopeReason)
+}
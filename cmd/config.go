@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/config"
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/fatih/color"
+)
+
+var (
+	cfgFile string
+	cfg     config.Config
+)
+
+// initConfig loads the user config first and lets the repository config
+// override it. An explicit --config file replaces both.
+func initConfig() {
+	defer markStartup("config")
+
+	var loaded []string
+	if cfgFile != "" {
+		if err := config.Read(cfgFile, &cfg); err != nil {
+			exitConfigError(cfgFile, err)
+		}
+		logger.Info("config loaded", "path", cfgFile)
+		loaded = append(loaded, cfgFile)
+	} else {
+		for _, path := range configPaths() {
+			ignored, err := readConfigLayer(path, &cfg)
+			switch {
+			case errors.Is(err, os.ErrNotExist):
+				logger.Debug("config not found", "path", path)
+			case err != nil:
+				exitConfigError(path, err)
+			default:
+				logger.Info("config loaded", "path", path)
+				loaded = append(loaded, path)
+			}
+			if len(ignored) > 0 {
+				fmt.Fprintln(os.Stderr, color.YellowString("%s", tr("config.user_only", strings.Join(ignored, ", "), path)))
+			}
+		}
+	}
+
+	if err := applyPreset(loaded); err != nil {
+		exitConfigError("preset", err)
+	}
+}
+
+func exitConfigError(path string, err error) {
+	if doctorRun() {
+		return
+	}
+	color.Red("%s", tr("config.read_error", path, err))
+	os.Exit(1)
+}
+
+// configPaths returns candidate config files in increasing precedence.
+func configPaths() []string {
+	var paths []string
+
+	if dir, err := os.UserConfigDir(); err == nil {
+		paths = append(paths, filepath.Join(dir, "commitz", "config.yaml"))
+	}
+
+	if root, err := repoRoot(); err == nil {
+		paths = append(paths, filepath.Join(root, ".commitz.yaml"), filepath.Join(root, ".commitz.yml"))
+	}
+
+	return paths
+}
+
+// readConfigLayer reads a config file found by configPaths on top of into.
+// A repository's .commitz.yaml cannot change which certificates are
+// trusted or which host gets the GitHub or Jira token, so those keys keep
+// their user-level values; the ones it tried to set are returned.
+func readConfigLayer(path string, into *config.Config) ([]string, error) {
+	base := filepath.Base(path)
+	if path == cfgFile || (base != ".commitz.yaml" && base != ".commitz.yml") {
+		return nil, config.Read(path, into)
+	}
+
+	network, hostingURL, issuesURL := into.Network, into.Hosting.URL, into.Issues.URL
+	if err := config.Read(path, into); err != nil {
+		return nil, err
+	}
+	var ignored []string
+	if into.Network.CABundle != network.CABundle {
+		ignored = append(ignored, "network.ca_bundle")
+	}
+	if into.Network.InsecureSkipVerify != network.InsecureSkipVerify {
+		ignored = append(ignored, "network.insecure_skip_verify")
+	}
+	if into.Hosting.URL != hostingURL {
+		ignored = append(ignored, "hosting.url")
+	}
+	if into.Issues.URL != issuesURL {
+		ignored = append(ignored, "issues.url")
+	}
+	into.Network.CABundle = network.CABundle
+	into.Network.InsecureSkipVerify = network.InsecureSkipVerify
+	into.Hosting.URL = hostingURL
+	into.Issues.URL = issuesURL
+	return ignored, nil
+}
+
+// isIgnoredPath reports whether the path matches an analysis.ignore pattern.
+func isIgnoredPath(path string) bool {
+	for _, pattern := range cfg.Analysis.Ignore {
+		if config.MatchGlob(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterIgnoredFiles drops files matching analysis.ignore patterns.
+func filterIgnoredFiles(files []git.File) []git.File {
+	var kept []git.File
+	for _, f := range files {
+		if isIgnoredPath(f.Path) {
+			logger.Info("ignored by analysis.ignore", "path", f.Path)
+			continue
+		}
+		kept = append(kept, f)
+	}
+	return kept
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// defaultGitTimeout bounds quick git queries when timeouts.git is unset.
+const defaultGitTimeout = 30 * time.Second
+
+// interruptGracePeriod is how long an interrupted child process gets to
+// exit before it is killed.
+const interruptGracePeriod = 2 * time.Second
+
+var (
+	rootCtx         = context.Background()
+	cancelRoot      = context.CancelFunc(func() {})
+	runningCommands sync.WaitGroup
+
+	cleanupMu sync.Mutex
+	cleanups  []func()
+)
+
+// onInterrupt registers cleanup to run before an interrupted run exits. It
+// returns the cleanup wrapped to run at most once, for the normal path to
+// call when it is done.
+func onInterrupt(cleanup func()) func() {
+	once := sync.OnceFunc(cleanup)
+	cleanupMu.Lock()
+	defer cleanupMu.Unlock()
+	cleanups = append(cleanups, once)
+	return once
+}
+
+// setupInterrupts stops the run on Ctrl+C or SIGTERM.
+func setupInterrupts() {
+	rootCtx, cancelRoot = context.WithCancel(context.Background())
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-signals
+		interrupt()
+	}()
+}
+
+// interrupt stops the run as Ctrl+C does: running child processes are
+// interrupted, the registered cleanups run, latest first, and commitz
+// exits.
+func interrupt() {
+	cancelRoot()
+
+	done := make(chan struct{})
+	go func() {
+		runningCommands.Wait()
+		close(done)
+	}()
+	// Children are killed after the grace period; this bounds the rest
+	select {
+	case <-done:
+	case <-time.After(2 * interruptGracePeriod):
+	}
+
+	cleanupMu.Lock()
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		cleanups[i]()
+	}
+	cleanupMu.Unlock()
+
+	fmt.Println()
+	color.Yellow("%s", tr("interrupted"))
+	os.Exit(130)
+}
+
+// awaitInterrupt blocks once the run was interrupted, leaving the cleanup
+// and the exit to the interrupt handler.
+func awaitInterrupt() {
+	if rootCtx.Err() != nil {
+		select {}
+	}
+}
+
+// commandTimeout parses a configured duration, falling back on errors.
+func commandTimeout(value string, fallback time.Duration) time.Duration {
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
+
+// gitCommand prepares a long-running git invocation, such as a commit that
+// runs hooks. It is not time-limited but stops on Ctrl+C.
+func gitCommand(args ...string) *exec.Cmd {
+	logger.Debug("git", "args", args)
+	cmd := exec.CommandContext(rootCtx, gitExecutable(), args...)
+	stopGently(cmd)
+	return cmd
+}
+
+// gitOutput runs a quick git query bounded by timeouts.git.
+func gitOutput(args ...string) ([]byte, error) {
+	timeout := commandTimeout(cfg.Timeouts.Git, defaultGitTimeout)
+	ctx, cancel := context.WithTimeout(rootCtx, timeout)
+	defer cancel()
+
+	runningCommands.Add(1)
+	defer runningCommands.Done()
+
+	start := time.Now()
+	out, err := exec.CommandContext(ctx, gitExecutable(), args...).Output()
+	logger.Debug("git", "args", args, "duration", time.Since(start), "error", err)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return out, errors.New(tr("timeout.git", strings.Join(args, " "), timeout))
+	}
+	return out, err
+}
+
+// gitStream runs a git command and hands its output to consume while it is
+// produced, so large outputs never have to be held in memory at once.
+func gitStream(consume func(io.Reader) error, args ...string) error {
+	cmd := gitCommand(args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	runningCommands.Add(1)
+	defer runningCommands.Done()
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	consumeErr := consume(stdout)
+	// Drain whatever the consumer left so git can exit
+	_, _ = io.Copy(io.Discard, stdout)
+	waitErr := cmd.Wait()
+
+	if consumeErr != nil {
+		return consumeErr
+	}
+	return waitErr
+}
+
+// gitRun runs a quick git command bounded by timeouts.git.
+func gitRun(args ...string) error {
+	_, err := gitOutput(args...)
+	return err
+}
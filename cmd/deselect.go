@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+)
+
+// deselect is --deselect, which asks for files to unstage in plain mode
+// too.
+var deselect bool
+
+// deselectFiles lets the user drop accidentally staged files before the
+// message is suggested, so they do not shape it. The dropped files are
+// unstaged, not reverted, and the remaining files are returned. Plain mode
+// only asks with --deselect.
+func deselectFiles(files []git.File, interactive bool) []git.File {
+	if len(files) < 2 || assumeYes || dryRun || !(interactive || deselect) {
+		return files
+	}
+
+	var keep []bool
+	if interactive {
+		keep = toggleFilesInteractive(files)
+	} else {
+		keep = toggleFilesPlain(files)
+	}
+
+	var kept []git.File
+	var paths []string
+	for i, f := range files {
+		if keep[i] {
+			kept = append(kept, f)
+			continue
+		}
+		paths = append(paths, f.Path)
+		if f.OldPath != "" && f.OldPath != f.Path {
+			paths = append(paths, f.OldPath)
+		}
+	}
+	if len(paths) == 0 {
+		return files
+	}
+	if len(kept) == 0 {
+		color.Yellow("%s", tr("deselect.none_left"))
+		return files
+	}
+
+	if err := unstagePaths(paths); err != nil {
+		color.Red("%s", tr("deselect.failed", err))
+		return files
+	}
+	color.Yellow("%s", tr("deselect.unstaged", len(files)-len(kept)))
+	return kept
+}
+
+// toggleFilesInteractive shows the staged files as a checklist. Choosing a
+// file toggles it and choosing the first item finishes.
+func toggleFilesInteractive(files []git.File) []bool {
+	keep := make([]bool, len(files))
+	for i := range keep {
+		keep[i] = true
+	}
+
+	cursor := 0
+	for {
+		count := 0
+		for _, k := range keep {
+			if k {
+				count++
+			}
+		}
+
+		items := []string{tr("deselect.done", count)}
+		for i, f := range files {
+			mark := "[x]"
+			if !keep[i] {
+				mark = "[ ]"
+			}
+			items = append(items, mark+" "+f.Path)
+		}
+
+		prompt := promptui.Select{
+			Label:     tr("deselect.prompt"),
+			Items:     items,
+			Size:      10,
+			CursorPos: cursor,
+		}
+		idx, _, err := runSelect(prompt)
+		if err != nil || idx == 0 {
+			return keep
+		}
+		keep[idx-1] = !keep[idx-1]
+		cursor = idx
+	}
+}
+
+// toggleFilesPlain lists the staged files and reads the numbers of those to
+// unstage.
+func toggleFilesPlain(files []git.File) []bool {
+	keep := make([]bool, len(files))
+	for i := range keep {
+		keep[i] = true
+	}
+
+	fmt.Println()
+	fmt.Println(tr("deselect.title", len(files)))
+	for i, f := range files {
+		fmt.Printf("  %d) %s\n", i+1, f.Path)
+	}
+	fmt.Print(tr("deselect.prompt_plain"))
+	answer, _ := readLine()
+
+	for _, field := range strings.FieldsFunc(answer, func(r rune) bool { return r == ',' || r == ' ' }) {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 1 || n > len(files) {
+			color.Yellow("%s", tr("deselect.bad_number", field))
+			continue
+		}
+		keep[n-1] = false
+	}
+	return keep
+}
+
+// unstagePaths removes paths from the index and leaves the working tree
+// alone.
+func unstagePaths(paths []string) error {
+	root, err := repoRoot()
+	if err != nil {
+		return err
+	}
+
+	args := append([]string{"-C", root, "reset", "-q", "--"}, paths...)
+	if gitRun("rev-parse", "--verify", "-q", "HEAD") != nil {
+		// Nothing to reset to before the first commit
+		args = append([]string{"-C", root, "rm", "--cached", "-q", "--"}, paths...)
+	}
+	return gitRun(args...)
+}
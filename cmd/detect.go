@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"github.com/barisdilekci/commitz/internal/detect"
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/barisdilekci/commitz/internal/summary"
+)
+
+func detectCommitType(diff string, files []git.File) string {
+	commitType, _, _ := explainCommitType(diff, files)
+	return commitType
+}
+
+// explainCommitType detects the commit type and describes the rule that
+// matched. weak reports that only keywords in the diff, or no rule at all,
+// decided it, so a stronger signal such as the ticket should win.
+func explainCommitType(diff string, files []git.File) (commitType, reason string, weak bool) {
+	// Ignore lockfiles, generated and binary files unless nothing else changed
+	if detect.OnlyWhitespace(files) {
+		return "style", tr("why.whitespace_only", len(files)), false
+	}
+
+	relevant, noise := detect.SplitNoise(files)
+	if len(relevant) == 0 && len(noise) > 0 {
+		return detect.NoiseType(noise), tr("why.noise_only", len(noise)), false
+	}
+	if len(noise) > 0 {
+		diff, files = detect.DiffText(relevant), relevant
+	}
+
+	if summary.OnlyMigrations(files) {
+		if changes := summary.ParseMigrations(files); len(changes) > 0 {
+			return summary.MigrationType(changes), tr("why.migration", len(changes), changes[0]), false
+		}
+	}
+	if summary.OnlyConfigFiles(files) {
+		if _, changes, ok := summary.ConfigSummary(files, gitOutput); ok {
+			return "chore", tr("why.config", len(changes)), false
+		}
+	}
+
+	// Removing code is a refactor, removing anything else is housekeeping
+	if detect.OnlyDeletions(files) {
+		for _, f := range files {
+			if git.IsSourceFile(f.Path) {
+				return "refactor", tr("why.deleted_source", len(files), f.Path), false
+			}
+		}
+		return "chore", tr("why.deleted_other", len(files)), false
+	}
+
+	// Only label as test when nothing but test files changed
+	tests, others := detect.SplitTests(files)
+	if len(tests) > 0 && len(others) == 0 {
+		return "test", tr("why.tests_only", len(tests), tests[0].Path), false
+	}
+	if commitType, keyword, ok := detect.Keyword(diff); ok {
+		if commitType == "docs" {
+			return commitType, tr("why.matched", keyword), true
+		}
+		return commitType, tr("why.keyword", keyword), true
+	}
+
+	return "chore", tr("why.no_rule"), true
+}
+
+func extractScopeFromBranch() string {
+	scope, _ := explainScopeFromBranch()
+	return scope
+}
+
+// explainScopeFromBranch extracts the scope from the branch name, using
+// branch.patterns or else the part before the first "/", and describes
+// where it came from.
+func explainScopeFromBranch() (string, string) {
+	branchName, err := currentBranch()
+	if err != nil {
+		return "", tr("why.no_branch")
+	}
+
+	parts, ok := parseBranch(branchName)
+	switch {
+	case ok && parts.Pattern != "":
+		if scope := cleanBranchScope(parts.Scope); scope != "" {
+			return scope, tr("why.branch_pattern", parts.Pattern, branchName)
+		}
+		return "", tr("why.branch_pattern_no_scope", parts.Pattern, branchName)
+	case ok:
+		if scope := cleanBranchScope(parts.Scope); scope != "" {
+			return scope, tr("why.branch_prefix", parts.Scope+"/", branchName)
+		}
+	}
+
+	return "", tr("why.branch_no_prefix", branchName)
+}
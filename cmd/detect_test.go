@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/barisdilekci/commitz/internal/git"
+)
+
+// stagedFile renders a minimal diff adding lines to path.
+func stagedFile(path string, isNew bool, added ...string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "diff --git a/%s b/%s\n", path, path)
+	if isNew {
+		b.WriteString("new file mode 100644\n")
+	}
+	fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n@@ -1 +1,%d @@\n", path, path, len(added))
+	for _, line := range added {
+		b.WriteString("+" + line + "\n")
+	}
+	return b.String()
+}
+
+// deletedFile renders a minimal diff deleting path.
+func deletedFile(path string) string {
+	return fmt.Sprintf("diff --git a/%s b/%s\ndeleted file mode 100644\n--- a/%s\n+++ /dev/null\n@@ -1 +0,0 @@\n-gone\n", path, path, path)
+}
+
+func TestDetectCommitType(t *testing.T) {
+	tests := []struct {
+		name string
+		diff string
+		want string
+	}{
+		{"tests only", stagedFile("pkg/a_test.go", false, "func TestA(t *testing.T) {}"), "test"},
+		{"readme", stagedFile("README.md", false, "Usage"), "docs"},
+		{"bug fix", stagedFile("pkg/a.go", false, "// fix off by one"), "fix"},
+		{"new feature", stagedFile("pkg/a.go", false, "// add paging"), "feat"},
+		{"deleted source", deletedFile("pkg/old.go"), "refactor"},
+		{"deleted other", deletedFile("notes.txt"), "chore"},
+		{"no rule", stagedFile("pkg/a.go", false, "x := 1"), "chore"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := git.ParseDiff(tt.diff, git.Options{})
+			got, reason, _ := explainCommitType(tt.diff, files)
+			if got != tt.want {
+				t.Errorf("explainCommitType() = %q (%s), want %q", got, reason, tt.want)
+			}
+			if reason == "" {
+				t.Error("explainCommitType() gave no reason")
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/spf13/cobra"
+)
+
+// diffOptions control how the staged diff is captured for analysis.
+type diffOptions struct {
+	// Context is the number of context lines, or -1 for git's default.
+	Context        int
+	WordDiff       bool
+	IgnoreAllSpace bool
+}
+
+var diffCapture = diffOptions{Context: -1}
+
+// plainDiffArgs keep the user's git config from changing the diff commitz
+// parses: color.diff=always, diff.noprefix, diff.mnemonicPrefix and
+// external diff drivers would otherwise leave nothing to read.
+var plainDiffArgs = []string{"--no-color", "--no-ext-diff", "--src-prefix=a/", "--dst-prefix=b/"}
+
+// addDiffFlags registers the diff capture flags on a command that analyzes
+// changes.
+func addDiffFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVarP(
+		&diffCapture.Context,
+		"unified",
+		"U",
+		-1,
+		"Lines of context around changes in the analyzed diff, e.g. -U0",
+	)
+
+	cmd.Flags().BoolVar(
+		&diffCapture.WordDiff,
+		"word-diff",
+		false,
+		"Analyze changed words instead of whole changed lines",
+	)
+
+	cmd.Flags().BoolVar(
+		&diffCapture.IgnoreAllSpace,
+		"ignore-all-space",
+		false,
+		"Ignore whitespace and blank lines when analyzing; whitespace-only changes suggest style",
+	)
+}
+
+// args inserts the capture options after the git subcommand.
+func (o diffOptions) args(command string, rest ...string) []string {
+	args := append([]string{command}, plainDiffArgs...)
+	if o.Context >= 0 {
+		args = append(args, "-U"+strconv.Itoa(o.Context))
+	}
+	if o.WordDiff {
+		args = append(args, "--word-diff=porcelain")
+	}
+	if o.IgnoreAllSpace {
+		args = append(args, "--ignore-all-space", "--ignore-blank-lines")
+	}
+	return append(args, rest...)
+}
+
+// gitOptions tells the diff parser how the diff was captured.
+func (o diffOptions) gitOptions() git.Options {
+	return git.Options{WordDiff: o.WordDiff}
+}
+
+// scannedFiles returns the staged files for the secret, content and large
+// file checks. They look at everything that is committed, so they get the
+// files before analysis.ignore drops any, and whole added lines whatever
+// --word-diff or --ignore-all-space did to the analyzed diff. --stat-only
+// analyzes no lines, so the checks read the full diff then too.
+func scannedFiles(files []git.File) ([]git.File, error) {
+	if !statOnly && !diffCapture.WordDiff && !diffCapture.IgnoreAllSpace {
+		return files, nil
+	}
+
+	var full []git.File
+	err := gitStream(func(r io.Reader) error {
+		var err error
+		full, err = git.ParseDiffReader(r, git.Options{})
+		return err
+	}, append(append([]string{"diff"}, plainDiffArgs...), "--cached")...)
+	if err != nil {
+		return nil, err
+	}
+	classifyFiles(full)
+	return full, nil
+}
+
+// addWhitespaceOnlyFiles adds the staged files that --ignore-all-space left
+// out of the diff, marked as whitespace-only and without changed lines.
+func addWhitespaceOnlyFiles(files []git.File, command string, rest ...string) ([]git.File, error) {
+	args := append([]string{command, "--numstat", "--summary"}, rest...)
+	out, err := gitOutput(args...)
+	if err != nil {
+		return files, err
+	}
+
+	seen := map[string]bool{}
+	for _, f := range files {
+		seen[f.Path] = true
+	}
+	for _, f := range git.ParseNumstat(string(out)) {
+		if !seen[f.Path] && !f.Binary {
+			f.WhitespaceOnly = true
+			f.Additions, f.Deletions = 0, 0
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
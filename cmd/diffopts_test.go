@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScannedFiles(t *testing.T) {
+	repo := testRepo(t)
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("config.go", "package config\n\nconst name = \"app\"\n")
+	gitRun("add", ".")
+	gitRun("commit", "-q", "-m", "feat: add config")
+	// Whitespace only, which --ignore-all-space leaves out of the analysis
+	write("config.go", "package config\n\nconst name = \"app\"   \n")
+	gitRun("add", ".")
+
+	saved := diffCapture
+	t.Cleanup(func() { diffCapture = saved })
+	diffCapture = diffOptions{Context: -1, IgnoreAllSpace: true}
+	files, err := loadStagedFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if warnings := scanContentWarnings(files); len(warnings) != 0 {
+		t.Fatalf("analyzed diff already has warnings: %v", warnings)
+	}
+
+	scanned, err := scannedFiles(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if warnings := scanContentWarnings(scanned); len(warnings) != 1 {
+		t.Errorf("scanContentWarnings(scannedFiles()) = %v, want trailing whitespace", warnings)
+	}
+}
+
+func TestStagedFilesIgnoreDiffConfig(t *testing.T) {
+	repo := testRepo(t,
+		[]string{"config", "color.diff", "always"},
+		[]string{"config", "diff.noprefix", "true"},
+		[]string{"config", "diff.external", "false"},
+	)
+	if err := os.WriteFile(filepath.Join(repo, "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun("add", ".")
+
+	saved := diffCapture
+	t.Cleanup(func() { diffCapture = saved })
+	for _, opts := range []diffOptions{{Context: -1}, {Context: -1, WordDiff: true}} {
+		diffCapture = opts
+		files, err := loadStagedFiles()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(files) != 1 || files[0].Path != "main.go" || files[0].Additions != 1 {
+			t.Errorf("loadStagedFiles() with %+v = %+v, want main.go with one added line", opts, files)
+		}
+		scanned, err := scannedFiles(files)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(scanned) != 1 || len(scanned[0].Added) != 1 {
+			t.Errorf("scannedFiles() with %+v = %+v, want main.go with its added line", opts, scanned)
+		}
+	}
+}
+
+func TestScannedFilesStatOnly(t *testing.T) {
+	repo := testRepo(t)
+	if err := os.WriteFile(filepath.Join(repo, "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun("add", ".")
+
+	defer func(saved bool) { statOnly = saved }(statOnly)
+	statOnly = true
+	files, err := loadStagedFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanned, err := scannedFiles(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scanned) != 1 || len(scanned[0].Added) != 1 {
+		t.Errorf("scannedFiles() with --stat-only = %+v, want the added line to scan", scanned)
+	}
+}
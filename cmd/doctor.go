@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/config"
+	"github.com/barisdilekci/commitz/internal/message"
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// minGitVersion is the oldest git that supports every command commitz runs
+// (branch --show-current needs 2.22).
+var minGitVersion = [2]int{2, 22}
+
+// Doctor check results.
+const (
+	checkOK   = "ok"
+	checkWarn = "warn"
+	checkFail = "fail"
+)
+
+type doctorCheck struct {
+	Name   string
+	Status string
+	Detail string
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose the environment and print a report for bug reports",
+	Long: `Checks the git installation, the repository, hooks, configuration and
+terminal capabilities, and prints a plain report that can be pasted into an
+issue.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checks := []doctorCheck{
+			checkGitVersion(),
+			checkRepository(),
+			checkRepositoryState(),
+			checkHooks(),
+			checkIdentity(),
+		}
+		checks = append(checks, checkConfigFiles()...)
+		checks = append(checks, checkNetwork(), checkTerminal(), checkPlatform())
+
+		failed := displayDoctorReport(checks)
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// doctorRun reports whether the doctor command was invoked. Config errors
+// must not stop it, since reporting them is its job.
+func doctorRun() bool {
+	cmd, _, err := rootCmd.Find(os.Args[1:])
+	return err == nil && cmd == doctorCmd
+}
+
+func displayDoctorReport(checks []doctorCheck) bool {
+	symbols := map[string]string{
+		checkOK:   color.GreenString("%s", displayText("✓")),
+		checkWarn: color.YellowString("%s", displayText("⚠")),
+		checkFail: color.RedString("%s", displayText("✗")),
+	}
+
+	failed := false
+	fmt.Println(tr("doctor.title"))
+	for _, c := range checks {
+		fmt.Printf("  %s %-12s %s\n", symbols[c.Status], c.Name, c.Detail)
+		failed = failed || c.Status == checkFail
+	}
+	return failed
+}
+
+func checkGitVersion() doctorCheck {
+	out, err := gitOutput("version")
+	if err != nil {
+		return doctorCheck{"git", checkFail, tr("repo.git_missing")}
+	}
+
+	version := strings.TrimSpace(string(out))
+	fields := strings.Fields(strings.TrimPrefix(version, "git version "))
+	if len(fields) == 0 {
+		return doctorCheck{"git", checkWarn, version}
+	}
+
+	parts := strings.SplitN(fields[0], ".", 3)
+	if len(parts) >= 2 {
+		major, _ := strconv.Atoi(parts[0])
+		minor, _ := strconv.Atoi(parts[1])
+		if major < minGitVersion[0] || (major == minGitVersion[0] && minor < minGitVersion[1]) {
+			return doctorCheck{"git", checkFail, tr("doctor.git_too_old", version, minGitVersion[0], minGitVersion[1])}
+		}
+	}
+	return doctorCheck{"git", checkOK, version}
+}
+
+func checkRepository() doctorCheck {
+	root, err := repoRoot()
+	if err != nil {
+		return doctorCheck{"repository", checkFail, tr("repo.not_found")}
+	}
+
+	branch, _ := currentBranch()
+	if branch == "" {
+		return doctorCheck{"repository", checkWarn, tr("doctor.detached", root)}
+	}
+	return doctorCheck{"repository", checkOK, tr("doctor.repository", root, branch)}
+}
+
+// checkRepositoryState reports operations git has left in progress.
+func checkRepositoryState() doctorCheck {
+	markers := []struct{ path, operation string }{
+		{"MERGE_HEAD", "merge"},
+		{"rebase-merge", "rebase"},
+		{"rebase-apply", "rebase"},
+		{"CHERRY_PICK_HEAD", "cherry-pick"},
+		{"REVERT_HEAD", "revert"},
+		{"BISECT_LOG", "bisect"},
+	}
+
+	for _, m := range markers {
+		out, err := gitOutput("rev-parse", "--git-path", m.path)
+		if err != nil {
+			return doctorCheck{"state", checkWarn, tr("doctor.unknown")}
+		}
+		if _, err := os.Stat(strings.TrimSpace(string(out))); err == nil {
+			return doctorCheck{"state", checkWarn, tr("doctor.in_progress", m.operation)}
+		}
+	}
+
+	staged, _ := gitOutput("diff", "--cached", "--name-only")
+	count := len(strings.Fields(string(staged)))
+	return doctorCheck{"state", checkOK, tr("doctor.staged", count)}
+}
+
+func checkHooks() doctorCheck {
+	dir, hooks, err := installedHooks()
+	if err != nil {
+		return doctorCheck{"hooks", checkWarn, tr("doctor.unknown")}
+	}
+	if len(hooks) == 0 {
+		return doctorCheck{"hooks", checkOK, tr("doctor.none")}
+	}
+	return doctorCheck{"hooks", checkOK, fmt.Sprintf("%s (%s)", strings.Join(hooks, ", "), dir)}
+}
+
+func checkIdentity() doctorCheck {
+	settings := gitConfig()
+	if settings.UserName == "" || settings.UserEmail == "" {
+		return doctorCheck{"identity", checkWarn, tr("doctor.identity_missing")}
+	}
+	return doctorCheck{"identity", checkOK, fmt.Sprintf("%s <%s>", settings.UserName, settings.UserEmail)}
+}
+
+// checkConfigFiles validates every config file that would be loaded,
+// including keys commitz does not know.
+func checkConfigFiles() []doctorCheck {
+	paths := configPaths()
+	if cfgFile != "" {
+		paths = []string{cfgFile}
+	}
+
+	var checks []doctorCheck
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err == nil {
+			decoder := yaml.NewDecoder(bytes.NewReader(data))
+			decoder.KnownFields(true)
+			err = decoder.Decode(&config.Config{})
+		}
+
+		if err != nil && !errors.Is(err, io.EOF) {
+			checks = append(checks, doctorCheck{"config", checkFail, fmt.Sprintf("%s: %v", path, err)})
+		} else {
+			checks = append(checks, doctorCheck{"config", checkOK, path})
+		}
+	}
+
+	if len(checks) == 0 {
+		return []doctorCheck{{"config", checkOK, tr("doctor.config_defaults")}}
+	}
+	return checks
+}
+
+func checkTerminal() doctorCheck {
+	tty := func(f *os.File) string {
+		if isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd()) {
+			return "tty"
+		}
+		return "pipe"
+	}
+	onOff := func(on bool) string {
+		if on {
+			return "on"
+		}
+		return "off"
+	}
+
+	detail := fmt.Sprintf("stdin %s, stdout %s, color %s, emoji %s, TERM=%q",
+		tty(os.Stdin), tty(os.Stdout), onOff(!color.NoColor), onOff(emojiSupported()), os.Getenv("TERM"))
+
+	status := checkOK
+	if tty(os.Stdin) != "tty" {
+		// Interactive mode needs a terminal on stdin
+		status = checkWarn
+	}
+	return doctorCheck{"terminal", status, detail}
+}
+
+func checkPlatform() doctorCheck {
+	detail := fmt.Sprintf("%s/%s, %s, ui %s, messages %s",
+		runtime.GOOS, runtime.GOARCH, runtime.Version(), locale, message.Language())
+	return doctorCheck{"platform", checkOK, detail}
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+)
+
+// draftFile lives in the git directory so every repository and worktree
+// keeps its own draft.
+const draftFile = "commitz-draft.json"
+
+// declinedFile holds the leftover COMMIT_EDITMSG message that was last
+// declined, so it is not offered again. COMMIT_EDITMSG itself belongs to
+// git and is left alone.
+const declinedFile = "commitz-declined-message"
+
+type draft struct {
+	Message string    `json:"message"`
+	Saved   time.Time `json:"saved"`
+}
+
+func draftPath() string {
+	out, err := gitOutput("rev-parse", "--git-path", draftFile)
+	if err != nil {
+		return filepath.Join(os.TempDir(), draftFile)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// saveDraft stores the message composed so far. A dry run never commits,
+// so it leaves any existing draft alone.
+func saveDraft(message string) {
+	if dryRun {
+		return
+	}
+	data, err := json.Marshal(draft{Message: message, Saved: time.Now()})
+	if err != nil {
+		return
+	}
+
+	if err := writeFileAtomic(draftPath(), data); err != nil {
+		logger.Debug("draft not saved", "error", err)
+	}
+}
+
+func loadDraft() (draft, bool) {
+	data, err := os.ReadFile(draftPath())
+	if err != nil {
+		return draft{}, false
+	}
+
+	var d draft
+	if err := json.Unmarshal(data, &d); err != nil || strings.TrimSpace(d.Message) == "" {
+		return draft{}, false
+	}
+	return d, true
+}
+
+// clearDraft removes the draft once the message was used or discarded.
+func clearDraft() {
+	_ = os.Remove(draftPath())
+}
+
+// leftoverCommitMessage returns the message of a commit attempt that git
+// rejected, e.g. because of a hook or a signing error. Git leaves it in
+// COMMIT_EDITMSG; it is only a leftover when it is newer than HEAD and was
+// never committed.
+func leftoverCommitMessage() (draft, bool) {
+	out, err := gitOutput("rev-parse", "--git-path", "COMMIT_EDITMSG")
+	if err != nil {
+		return draft{}, false
+	}
+	path := strings.TrimSpace(string(out))
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return draft{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return draft{}, false
+	}
+
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		if !strings.HasPrefix(line, gitConfig().CommentChar) {
+			lines = append(lines, line)
+		}
+	}
+	message := strings.TrimSpace(strings.Join(lines, "\n"))
+	if !conventionalPrefix.MatchString(message) {
+		return draft{}, false
+	}
+
+	head, err := gitOutput("log", "-1", "--format=%ct")
+	if err == nil {
+		seconds, _ := strconv.ParseInt(strings.TrimSpace(string(head)), 10, 64)
+		if !info.ModTime().After(time.Unix(seconds, 0)) {
+			return draft{}, false
+		}
+	}
+
+	if path, err := gitDirPath(declinedFile); err == nil {
+		if declined, err := os.ReadFile(path); err == nil && string(declined) == message {
+			return draft{}, false
+		}
+	}
+
+	// Messages of commits that were later reset or amended are not leftovers
+	if recent, err := gitOutput("log", "-g", "-n", "50", "--format=%B%x00"); err == nil {
+		for _, body := range strings.Split(string(recent), "\x00") {
+			if strings.TrimSpace(body) == message {
+				return draft{}, false
+			}
+		}
+	}
+
+	return draft{Message: message, Saved: info.ModTime()}, true
+}
+
+// rememberDeclined keeps a declined leftover message from being offered
+// again.
+func rememberDeclined(message string) {
+	path, err := gitDirPath(declinedFile)
+	if err == nil {
+		err = writeFileAtomic(path, []byte(message))
+	}
+	if err != nil {
+		logger.Debug("declined message not remembered", "error", err)
+	}
+}
+
+// restoreDraft offers a message left behind by an interrupted run, or else
+// the message of a failed commit attempt. A declined draft is removed and a
+// declined leftover is remembered, so neither is offered again.
+func restoreDraft(interactive bool) (string, bool) {
+	if assumeYes {
+		return "", false
+	}
+
+	d, ok := loadDraft()
+	leftover := false
+	if ok {
+		color.Yellow("%s", tr("draft.found", d.Saved.Format("2006-01-02 15:04")))
+	} else if d, ok = leftoverCommitMessage(); ok {
+		leftover = true
+		color.Yellow("%s", tr("draft.found_failed", d.Saved.Format("2006-01-02 15:04")))
+	} else {
+		return "", false
+	}
+	fmt.Println(color.CyanString("%s", d.Message))
+
+	var restore bool
+	if interactive {
+		prompt := promptui.Prompt{
+			Label:     tr("draft.restore"),
+			IsConfirm: true,
+		}
+		result, err := runPrompt(prompt)
+		restore = err == nil && (isYes(result) || result == "")
+	} else {
+		fmt.Print(tr("draft.restore_plain"))
+		answer, _ := readLine()
+		restore = isYes(answer) || strings.TrimSpace(answer) == ""
+	}
+
+	if !restore {
+		if leftover {
+			rememberDeclined(d.Message)
+		}
+		clearDraft()
+		return "", false
+	}
+	return d.Message, true
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDeclinedLeftoverMessage(t *testing.T) {
+	repo := testRepo(t, []string{"commit", "-q", "--allow-empty", "-m", "feat: first"})
+
+	// A commit attempt a hook rejected after HEAD was made
+	path := filepath.Join(repo, ".git", "COMMIT_EDITMSG")
+	if err := os.WriteFile(path, []byte("fix: handle empty input\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if d, ok := leftoverCommitMessage(); !ok || d.Message != "fix: handle empty input" {
+		t.Fatalf("leftoverCommitMessage() = %+v, %v", d, ok)
+	}
+
+	useScript(t, promptStep{Answer: "n"})
+	if message, restored := restoreDraft(false); restored {
+		t.Fatalf("declined leftover was restored as %q", message)
+	}
+	if _, ok := leftoverCommitMessage(); ok {
+		t.Error("declined leftover is offered again")
+	}
+
+	// A later failed attempt with another message is offered
+	if err := os.WriteFile(path, []byte("fix: handle nil input\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := leftoverCommitMessage(); !ok {
+		t.Error("new leftover is not offered")
+	}
+}
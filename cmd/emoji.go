@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/message"
+	"github.com/fatih/color"
+)
+
+// emojiShortcodes are the gitmoji shortcodes of the type emojis.
+var emojiShortcodes = map[string]string{
+	"✨":  ":sparkles:",
+	"🐛":  ":bug:",
+	"📝":  ":memo:",
+	"💄":  ":lipstick:",
+	"♻️": ":recycle:",
+	"⚡":  ":zap:",
+	"✅":  ":white_check_mark:",
+	"🔨":  ":hammer:",
+	"👷":  ":construction_worker:",
+	"🧹":  ":broom:",
+	"🔒":  ":lock:",
+}
+
+// emojiPosition returns the configured emoji position, "prefix" when it
+// is unset or unknown.
+func emojiPosition() string {
+	switch position := strings.ToLower(cfg.Emoji.Position); position {
+	case "":
+		return message.EmojiPrefix
+	case message.EmojiPrefix, message.EmojiAfterColon, message.EmojiSuffix, message.EmojiBody, message.EmojiFooter:
+		return position
+	default:
+		color.Yellow("%s", tr("emoji.invalid_position", cfg.Emoji.Position))
+		cfg.Emoji.Position = message.EmojiPrefix
+		return message.EmojiPrefix
+	}
+}
+
+// emojiText writes an emoji as its shortcode with emoji.shortcode, keeping
+// the trailing space.
+func emojiText(emoji string) string {
+	if !cfg.Emoji.Shortcode {
+		return emoji
+	}
+	if code, ok := emojiShortcodes[strings.TrimSpace(emoji)]; ok {
+		return strings.Replace(emoji, strings.TrimSpace(emoji), code, 1)
+	}
+	if g, ok := gitmojiByEmoji(strings.TrimSpace(emoji)); ok {
+		return strings.Replace(emoji, strings.TrimSpace(emoji), g.Code, 1)
+	}
+	return emoji
+}
+
+// messageEmoji is the emoji as emoji.shortcode writes it, placed where
+// emoji.position says.
+func messageEmoji(emoji string) message.Emoji {
+	return message.Emoji{Text: emojiText(emoji), Position: emojiPosition()}
+}
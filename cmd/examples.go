@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// usageExample is a common invocation and what it produces. Title is a
+// locale key.
+type usageExample struct {
+	Title   string
+	Command string
+	Output  []string
+}
+
+var usageExamples = []usageExample{
+	{"examples.auto", "git add internal/cache && commitz -y", []string{
+		"fix: fix issue in cache",
+	}},
+	{"examples.preview", "commitz -d --why", []string{
+		"feat: add users.email column and add index on users.email",
+	}},
+	{"examples.flags", "commitz -t perf -s cache", []string{
+		"perf(cache): improve performance",
+	}},
+	{"examples.interactive", "commitz -i -e", []string{
+		"✨ feat(cache): add Delete method",
+	}},
+	{"examples.body", "commitz -d", []string{
+		"feat: add Delete functionality",
+		"",
+		"- update config.yaml (+1/-1)",
+		"- update internal/cache/cache.go: Get, Delete (+9/-0)",
+		"- add tests in internal/cache/cache_test.go",
+	}},
+	{"examples.noise", "commitz -d", []string{
+		"build: update dependencies",
+	}},
+	{"examples.wip", "commitz wip", []string{
+		"chore: wip",
+	}},
+	{"examples.lint", "commitz lint --fix .git/COMMIT_EDITMSG", []string{
+		"Feat: Added paging to the cache.  →  feat: added paging to the cache",
+	}},
+	{"examples.squash", "commitz squash-msg main..feature/login | git commit -F -", []string{
+		"feat(auth): add login form",
+		"",
+		"- feat(auth): add login form",
+		"- fix(auth): keep the session after a reload",
+	}},
+}
+
+var examplesCmd = &cobra.Command{
+	Use:   "examples",
+	Short: "Show common invocations and the messages they produce",
+	Long: `Shows common ways to run commitz, each with the commit message or output
+it produces for a typical change.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		displayExamples()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(examplesCmd)
+}
+
+func displayExamples() {
+	for i, example := range usageExamples {
+		if i > 0 {
+			fmt.Println()
+		}
+		color.New(color.Bold).Println(tr(example.Title))
+		fmt.Printf("  $ %s\n", color.CyanString("%s", example.Command))
+		for _, line := range example.Output {
+			if line == "" {
+				fmt.Println()
+				continue
+			}
+			fmt.Printf("  %s\n", color.GreenString("%s", line))
+		}
+	}
+}
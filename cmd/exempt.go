@@ -0,0 +1,46 @@
+package cmd
+
+import "strings"
+
+// How lint treats merge, revert and fixup commits.
+const (
+	exemptSkip    = "skip"
+	exemptRelaxed = "relaxed"
+	exemptStrict  = "strict"
+)
+
+// fixupPrefixes mark commits that git rebase --autosquash folds into
+// another commit.
+var fixupPrefixes = []string{"fixup! ", "squash! ", "amend! "}
+
+// exemptionMode returns how lint treats a message: merge commits and
+// fixup!/squash!/amend! commits are skipped and Revert "..." messages get
+// relaxed rules unless lint.merge, lint.revert or lint.fixup say otherwise.
+func exemptionMode(message string) string {
+	header, _, _ := strings.Cut(message, "\n")
+
+	mode, fallback := "", exemptStrict
+	switch {
+	case strings.HasPrefix(header, "Merge "):
+		mode, fallback = cfg.Lint.Merge, exemptSkip
+	case strings.HasPrefix(header, `Revert "`), strings.HasPrefix(header, `Reapply "`):
+		mode, fallback = cfg.Lint.Revert, exemptRelaxed
+	case hasFixupPrefix(header):
+		mode, fallback = cfg.Lint.Fixup, exemptSkip
+	}
+
+	switch mode = strings.ToLower(mode); mode {
+	case exemptSkip, exemptRelaxed, exemptStrict:
+		return mode
+	}
+	return fallback
+}
+
+func hasFixupPrefix(header string) bool {
+	for _, prefix := range fixupPrefixes {
+		if strings.HasPrefix(header, prefix) {
+			return true
+		}
+	}
+	return false
+}
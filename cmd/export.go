@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFrom   string
+	exportTo     string
+	exportFormat string
+	exportOutput string
+)
+
+// exportedCommit is one row of commitz export. Fields keep their names
+// across versions so dashboards do not break.
+type exportedCommit struct {
+	Hash         string `json:"hash"`
+	Author       string `json:"author"`
+	Date         string `json:"date"`
+	Type         string `json:"type"`
+	Scope        string `json:"scope"`
+	Subject      string `json:"subject"`
+	Body         string `json:"body"`
+	Breaking     bool   `json:"breaking"`
+	BreakingNote string `json:"breaking_note"`
+	PR           int    `json:"pr"`
+}
+
+var exportColumns = []string{"hash", "author", "date", "type", "scope", "subject", "body", "breaking", "breaking_note", "pr"}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export parsed conventional commits as JSON or CSV",
+	Long: `Writes the commits between two revisions, oldest first, with their
+conventional fields parsed: type, scope, subject, body, breaking change and
+pull request number. Commits that are not conventional have an empty type.
+Without --from the whole history up to --to is exported.`,
+	Example: `  commitz export --from v1.0.0 --format json
+  commitz export --format csv -o commits.csv
+  commitz export --first-parent --path 'services/api/**'`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if exportFormat != "json" && exportFormat != "csv" {
+			color.Red("%s", tr("export.format", exportFormat))
+			os.Exit(1)
+		}
+
+		commits, err := historyCommits(exportFrom, exportTo)
+		if err != nil {
+			color.Red("%s", tr("changelog.error", err))
+			os.Exit(1)
+		}
+		rows := make([]exportedCommit, 0, len(commits))
+		for _, c := range commits {
+			rows = append(rows, exportedCommit{
+				Hash:         c.Hash,
+				Author:       c.Author,
+				Date:         c.Date.UTC().Format(time.RFC3339),
+				Type:         c.Type,
+				Scope:        c.Scope,
+				Subject:      c.Subject,
+				Body:         c.Body,
+				Breaking:     c.Breaking,
+				BreakingNote: c.BreakingNote,
+				PR:           c.PR,
+			})
+		}
+
+		out := io.Writer(os.Stdout)
+		if exportOutput != "" && exportOutput != "-" {
+			file, err := os.Create(exportOutput)
+			if err != nil {
+				color.Red("%s", tr("changelog.write_error", exportOutput, err))
+				os.Exit(1)
+			}
+			defer file.Close()
+			out = file
+		}
+
+		if exportFormat == "csv" {
+			err = writeExportCSV(out, rows)
+		} else {
+			encoder := json.NewEncoder(out)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(rows)
+		}
+		if err != nil {
+			color.Red("%s", tr("changelog.write_error", exportOutput, err))
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringVar(
+		&exportFrom,
+		"from",
+		"",
+		"Start after this revision (default: the first commit)",
+	)
+
+	exportCmd.Flags().StringVar(
+		&exportTo,
+		"to",
+		"HEAD",
+		"End at this revision",
+	)
+
+	exportCmd.Flags().StringVar(
+		&exportFormat,
+		"format",
+		"json",
+		"Output format: json or csv",
+	)
+
+	addHistoryFilterFlags(exportCmd)
+
+	exportCmd.Flags().StringVarP(
+		&exportOutput,
+		"output",
+		"o",
+		"",
+		"Write to this file instead of stdout",
+	)
+}
+
+func writeExportCSV(out io.Writer, rows []exportedCommit) error {
+	w := csv.NewWriter(out)
+	w.Write(exportColumns)
+	for _, r := range rows {
+		pr := ""
+		if r.PR != 0 {
+			pr = strconv.Itoa(r.PR)
+		}
+		w.Write([]string{
+			r.Hash, r.Author, r.Date, r.Type, r.Scope, r.Subject, r.Body,
+			fmt.Sprint(r.Breaking), r.BreakingNote, pr,
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
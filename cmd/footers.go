@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/config"
+	"github.com/barisdilekci/commitz/internal/message"
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+)
+
+// footerRulePattern compiles a rule's pattern, or returns nil when the rule
+// has none.
+func footerRulePattern(rule config.FooterRule) (*regexp.Regexp, error) {
+	if rule.Pattern == "" {
+		return nil, nil
+	}
+	return regexp.Compile(rule.Pattern)
+}
+
+// footerIssues checks a message against the footers policy.
+func footerIssues(msg string) []lintIssue {
+	var issues []lintIssue
+	footers := message.ParseFooters(msg)
+
+	for _, rule := range cfg.Footers {
+		pattern, err := footerRulePattern(rule)
+		if err != nil {
+			issues = append(issues, lintIssue{Rule: "footer-pattern", Message: tr("footers.invalid_pattern", rule.Key, err)})
+			continue
+		}
+
+		values := message.FooterValues(footers, rule.Key)
+		if len(values) == 0 && rule.Required {
+			issues = append(issues, lintIssue{Rule: "footer-missing", Message: tr("footers.missing", rule.Key)})
+		}
+		for _, value := range values {
+			if pattern != nil && !pattern.MatchString(value) {
+				issues = append(issues, lintIssue{Rule: "footer-format", Message: tr("footers.format", rule.Key, value, rule.Pattern)})
+			}
+		}
+	}
+	return issues
+}
+
+// requireFooters asks for every required footer the message lacks, and for
+// a new value when an existing one has the wrong format. Without prompts the
+// commit is refused instead.
+func requireFooters(msg string, interactive bool) string {
+	for _, rule := range cfg.Footers {
+		pattern, err := footerRulePattern(rule)
+		if err != nil {
+			color.Yellow("%s", tr("footers.invalid_pattern", rule.Key, err))
+			continue
+		}
+
+		values := message.FooterValues(message.ParseFooters(msg), rule.Key)
+		valid := len(values) > 0
+		for _, value := range values {
+			if pattern != nil && !pattern.MatchString(value) {
+				valid = false
+			}
+		}
+		if valid || (len(values) == 0 && !rule.Required) {
+			continue
+		}
+
+		if assumeYes {
+			displayLintIssues(tr("footers.title"), footerIssues(msg))
+			os.Exit(1)
+		}
+
+		value, ok := askFooterValue(rule, pattern, interactive)
+		if !ok {
+			color.Yellow("%s", tr("commit.cancelled"))
+			os.Exit(1)
+		}
+		msg = message.SetFooter(msg, rule.Key, value)
+	}
+	return msg
+}
+
+func askFooterValue(rule config.FooterRule, pattern *regexp.Regexp, interactive bool) (string, bool) {
+	validate := func(input string) error {
+		input = strings.TrimSpace(input)
+		if input == "" {
+			return errors.New(tr("footers.empty", rule.Key))
+		}
+		if pattern != nil && !pattern.MatchString(input) {
+			return errors.New(tr("footers.format_hint", rule.Pattern))
+		}
+		return nil
+	}
+
+	label := rule.Key
+	if rule.Example != "" {
+		label = fmt.Sprintf("%s (%s)", rule.Key, rule.Example)
+	}
+
+	if interactive {
+		prompt := promptui.Prompt{
+			Label:    tr("footers.prompt", label),
+			Validate: validate,
+		}
+		result, err := runPrompt(prompt)
+		return strings.TrimSpace(result), err == nil
+	}
+
+	for {
+		fmt.Print(tr("footers.prompt_plain", label))
+		answer, err := readLine()
+		if err != nil {
+			return "", false
+		}
+		if err := validate(answer); err != nil {
+			color.Yellow("%s", err.Error())
+			continue
+		}
+		return strings.TrimSpace(answer), true
+	}
+}
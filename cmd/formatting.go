@@ -0,0 +1,26 @@
+package cmd
+
+import "github.com/barisdilekci/commitz/internal/git"
+
+// markWhitespaceOnly marks every file as whitespace-only when the change
+// disappears with whitespace and blank lines ignored. Added, deleted and
+// renamed files always count as real changes.
+func markWhitespaceOnly(files []git.File, revs ...string) {
+	if len(files) == 0 {
+		return
+	}
+	for _, f := range files {
+		if f.IsNew || f.Deleted || f.Binary || f.OldPath != f.Path {
+			return
+		}
+	}
+
+	args := append([]string{"diff", "--quiet", "--ignore-all-space", "--ignore-blank-lines"}, revs...)
+	if err := gitRun(args...); err != nil {
+		return
+	}
+	logger.Info("whitespace-only change", "files", len(files))
+	for i := range files {
+		files[i].WhitespaceOnly = true
+	}
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+// gitSettings holds the git config values commitz adapts to.
+type gitSettings struct {
+	Template    string
+	CommentChar string
+	Cleanup     string
+	UserName    string
+	UserEmail   string
+}
+
+// gitConfig reads all relevant git config values in a single git call.
+var gitConfig = sync.OnceValue(loadGitConfig)
+
+func loadGitConfig() gitSettings {
+	settings := gitSettings{CommentChar: "#"}
+
+	out, err := gitOutput("config", "-z", "--get-regexp",
+		`^(commit\.template|commit\.cleanup|core\.commentchar|user\.name|user\.email)$`)
+	if err != nil {
+		return settings
+	}
+
+	// With -z every entry is "key\nvalue" terminated by NUL
+	for _, entry := range bytes.Split(out, []byte{0}) {
+		key, value, _ := strings.Cut(string(entry), "\n")
+		switch key {
+		case "commit.template":
+			settings.Template = value
+		case "commit.cleanup":
+			settings.Cleanup = value
+		case "core.commentchar":
+			if value != "" && value != "auto" {
+				settings.CommentChar = value
+			}
+		case "user.name":
+			settings.UserName = value
+		case "user.email":
+			settings.UserEmail = value
+		}
+	}
+
+	logger.Debug("git config", "settings", settings)
+	return settings
+}
+
+// installedHooks lists the active hooks, honoring core.hooksPath.
+func installedHooks() (string, []string, error) {
+	out, err := gitOutput("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", nil, err
+	}
+
+	dir := strings.TrimSpace(string(out))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return dir, nil, nil
+	}
+
+	var hooks []string
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasSuffix(entry.Name(), ".sample") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || (runtime.GOOS != "windows" && info.Mode()&0o111 == 0) {
+			continue
+		}
+		hooks = append(hooks, entry.Name())
+	}
+	return dir, hooks, nil
+}
+
+// checkGitIdentity warns up front when git will refuse to commit because
+// no author identity is configured.
+func checkGitIdentity() {
+	settings := gitConfig()
+
+	var missing []string
+	if settings.UserName == "" && os.Getenv("GIT_AUTHOR_NAME") == "" {
+		missing = append(missing, "user.name")
+	}
+	if settings.UserEmail == "" && os.Getenv("GIT_AUTHOR_EMAIL") == "" && os.Getenv("EMAIL") == "" {
+		missing = append(missing, "user.email")
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	color.Yellow("%s", tr("gitconfig.identity_missing", strings.Join(missing, ", ")))
+	fmt.Println(tr("gitconfig.identity_hint"))
+}
+
+// applyCommitTemplate appends the non-comment lines of commit.template, such
+// as trailers, which git would otherwise have put in the editor.
+func applyCommitTemplate(message string) string {
+	path := gitConfig().Template
+	if path == "" {
+		return message
+	}
+
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		logger.Info("commit.template not readable", "path", path, "error", err)
+		return message
+	}
+
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(line, gitConfig().CommentChar) {
+			continue
+		}
+		// Skip lines the message already has, e.g. after restoring a draft
+		if strings.TrimSpace(line) != "" && strings.Contains(message, line) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	extra := strings.TrimSpace(strings.Join(lines, "\n"))
+	if extra == "" {
+		return message
+	}
+	return message + "\n\n" + extra
+}
+
+// displayStrippedLines warns about body lines that commit.cleanup=strip
+// would silently remove because they start with the comment character.
+func displayStrippedLines(message string) {
+	settings := gitConfig()
+	if settings.Cleanup != "strip" {
+		return
+	}
+
+	var stripped []string
+	for _, line := range strings.Split(message, "\n") {
+		if strings.HasPrefix(line, settings.CommentChar) {
+			stripped = append(stripped, line)
+		}
+	}
+	if len(stripped) == 0 {
+		return
+	}
+
+	fmt.Println()
+	color.Yellow("%s", tr("gitconfig.stripped_lines", settings.CommentChar))
+	for _, line := range stripped {
+		fmt.Printf("  %s\n", truncate(line, 80))
+	}
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// githubTimeout bounds each GitHub API request.
+const githubTimeout = 30 * time.Second
+
+type githubRelease struct {
+	TagName    string `json:"tag_name"`
+	Name       string `json:"name"`
+	Body       string `json:"body"`
+	Draft      bool   `json:"draft"`
+	Prerelease bool   `json:"prerelease"`
+	MakeLatest string `json:"make_latest,omitempty"`
+}
+
+// githubToken reads GITHUB_TOKEN, GH_TOKEN or the keychain, then falls
+// back to the gh CLI's login.
+func githubToken() (string, error) {
+	if token := providerToken("github"); token != "" {
+		return token, nil
+	}
+
+	ctx, cancel := context.WithTimeout(rootCtx, githubTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "gh", "auth", "token").Output()
+	if token := strings.TrimSpace(string(out)); err == nil && token != "" {
+		return token, nil
+	}
+	return "", errors.New(tr("github.no_token"))
+}
+
+// githubRepo returns the API base URL and owner/repo path of the
+// repository, supporting GitHub Enterprise hosts.
+func githubRepo() (string, string, error) {
+	host, ok := repoHosting()
+	if !ok || host.Kind != hostGitHub {
+		return "", "", errors.New(tr("github.not_github"))
+	}
+	u, err := url.Parse(host.Base)
+	if err != nil {
+		return "", "", err
+	}
+
+	api := "https://api.github.com"
+	if u.Hostname() != "github.com" {
+		api = "https://" + u.Host + "/api/v3"
+	}
+	return api, strings.Trim(u.Path, "/"), nil
+}
+
+// githubTarget is the repository a release is created in and the token
+// to create it with.
+type githubTarget struct {
+	API, Repo, Token string
+}
+
+// githubReleaseTarget checks that releases can be created before anything
+// is tagged or pushed.
+func githubReleaseTarget() (githubTarget, error) {
+	api, repo, err := githubRepo()
+	if err != nil {
+		return githubTarget{}, err
+	}
+	token, err := githubToken()
+	if err != nil {
+		return githubTarget{}, err
+	}
+	return githubTarget{API: api, Repo: repo, Token: token}, nil
+}
+
+// createGitHubRelease creates a release for an already pushed tag and
+// returns its web URL.
+func createGitHubRelease(target githubTarget, release githubRelease) (string, error) {
+	api, repo, token := target.API, target.Repo, target.Token
+	payload, err := json.Marshal(release)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(rootCtx, githubTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, api+"/repos/"+repo+"/releases", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("Content-Type", "application/json")
+
+	client, err := httpClient()
+	if err != nil {
+		return "", err
+	}
+	logger.Info("creating github release", "repo", repo, "tag", release.TagName)
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode != http.StatusCreated {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		json.Unmarshal(body, &apiErr)
+		return "", fmt.Errorf("%s: %s", resp.Status, apiErr.Message)
+	}
+
+	var created struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.Unmarshal(body, &created); err != nil {
+		return "", err
+	}
+	return created.HTMLURL, nil
+}
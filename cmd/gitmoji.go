@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+)
+
+// gitmoji is one entry of the gitmoji catalogue (https://gitmoji.dev)
+// with the conventional type it is committed as, so the header carries
+// both, e.g. "🚑️ fix: restore login".
+type gitmoji struct {
+	Emoji       string
+	Code        string
+	Description string
+	Type        string
+}
+
+// gitmojis is the gitmoji catalogue in its own order.
+var gitmojis = []gitmoji{
+	{"🎨", ":art:", "Improve structure / format of the code", "style"},
+	{"⚡️", ":zap:", "Improve performance", "perf"},
+	{"🔥", ":fire:", "Remove code or files", "refactor"},
+	{"🐛", ":bug:", "Fix a bug", "fix"},
+	{"🚑️", ":ambulance:", "Critical hotfix", "fix"},
+	{"✨", ":sparkles:", "Introduce new features", "feat"},
+	{"📝", ":memo:", "Add or update documentation", "docs"},
+	{"🚀", ":rocket:", "Deploy stuff", "chore"},
+	{"💄", ":lipstick:", "Add or update the UI and style files", "style"},
+	{"🎉", ":tada:", "Begin a project", "chore"},
+	{"✅", ":white_check_mark:", "Add, update, or pass tests", "test"},
+	{"🔒️", ":lock:", "Fix security or privacy issues", "fix"},
+	{"🔐", ":closed_lock_with_key:", "Add or update secrets", "chore"},
+	{"🔖", ":bookmark:", "Release / Version tags", "chore"},
+	{"🚨", ":rotating_light:", "Fix compiler / linter warnings", "style"},
+	{"🚧", ":construction:", "Work in progress", "chore"},
+	{"💚", ":green_heart:", "Fix CI Build", "ci"},
+	{"⬇️", ":arrow_down:", "Downgrade dependencies", "build"},
+	{"⬆️", ":arrow_up:", "Upgrade dependencies", "build"},
+	{"📌", ":pushpin:", "Pin dependencies to specific versions", "build"},
+	{"👷", ":construction_worker:", "Add or update CI build system", "ci"},
+	{"📈", ":chart_with_upwards_trend:", "Add or update analytics or track code", "feat"},
+	{"♻️", ":recycle:", "Refactor code", "refactor"},
+	{"➕", ":heavy_plus_sign:", "Add a dependency", "build"},
+	{"➖", ":heavy_minus_sign:", "Remove a dependency", "build"},
+	{"🔧", ":wrench:", "Add or update configuration files", "chore"},
+	{"🔨", ":hammer:", "Add or update development scripts", "build"},
+	{"🌐", ":globe_with_meridians:", "Internationalization and localization", "feat"},
+	{"✏️", ":pencil2:", "Fix typos", "fix"},
+	{"💩", ":poop:", "Write bad code that needs to be improved", "chore"},
+	// revert is not a commitz type; lint would reject it
+	{"⏪️", ":rewind:", "Revert changes", "chore"},
+	{"🔀", ":twisted_rightwards_arrows:", "Merge branches", "chore"},
+	{"📦️", ":package:", "Add or update compiled files or packages", "build"},
+	{"👽️", ":alien:", "Update code due to external API changes", "fix"},
+	{"🚚", ":truck:", "Move or rename resources (e.g.: files, paths, routes)", "refactor"},
+	{"📄", ":page_facing_up:", "Add or update license", "chore"},
+	{"💥", ":boom:", "Introduce breaking changes", "feat"},
+	{"🍱", ":bento:", "Add or update assets", "chore"},
+	{"♿️", ":wheelchair:", "Improve accessibility", "feat"},
+	{"💡", ":bulb:", "Add or update comments in source code", "docs"},
+	{"🍻", ":beers:", "Write code drunkenly", "chore"},
+	{"💬", ":speech_balloon:", "Add or update text and literals", "feat"},
+	{"🗃️", ":card_file_box:", "Perform database related changes", "feat"},
+	{"🔊", ":loud_sound:", "Add or update logs", "feat"},
+	{"🔇", ":mute:", "Remove logs", "chore"},
+	{"👥", ":busts_in_silhouette:", "Add or update contributor(s)", "docs"},
+	{"🚸", ":children_crossing:", "Improve user experience / usability", "feat"},
+	{"🏗️", ":building_construction:", "Make architectural changes", "refactor"},
+	{"📱", ":iphone:", "Work on responsive design", "feat"},
+	{"🤡", ":clown_face:", "Mock things", "test"},
+	{"🥚", ":egg:", "Add or update an easter egg", "feat"},
+	{"🙈", ":see_no_evil:", "Add or update a .gitignore file", "chore"},
+	{"📸", ":camera_flash:", "Add or update snapshots", "test"},
+	{"⚗️", ":alembic:", "Perform experiments", "chore"},
+	{"🔍️", ":mag:", "Improve SEO", "feat"},
+	{"🏷️", ":label:", "Add or update types", "refactor"},
+	{"🌱", ":seedling:", "Add or update seed files", "chore"},
+	{"🚩", ":triangular_flag_on_post:", "Add, update, or remove feature flags", "feat"},
+	{"🥅", ":goal_net:", "Catch errors", "fix"},
+	{"💫", ":dizzy:", "Add or update animations and transitions", "feat"},
+	{"🗑️", ":wastebasket:", "Deprecate code that needs to be cleaned up", "refactor"},
+	{"🛂", ":passport_control:", "Work on code related to authorization, roles and permissions", "feat"},
+	{"🩹", ":adhesive_bandage:", "Simple fix for a non-critical issue", "fix"},
+	{"🧐", ":monocle_face:", "Data exploration/inspection", "chore"},
+	{"⚰️", ":coffin:", "Remove dead code", "refactor"},
+	{"🧪", ":test_tube:", "Add a failing test", "test"},
+	{"👔", ":necktie:", "Add or update business logic", "feat"},
+	{"🩺", ":stethoscope:", "Add or update healthcheck", "feat"},
+	{"🧱", ":bricks:", "Infrastructure related changes", "ci"},
+	{"🧑‍💻", ":technologist:", "Improve developer experience", "chore"},
+	{"💸", ":money_with_wings:", "Add sponsorships or money related infrastructure", "chore"},
+	{"🧵", ":thread:", "Add or update code related to multithreading or concurrency", "refactor"},
+	{"🦺", ":safety_vest:", "Add or update code related to validation", "feat"},
+	{"✈️", ":airplane:", "Improve offline support", "feat"},
+}
+
+// typeGitmojis are the gitmojis used for a detected type when nobody
+// picked one.
+var typeGitmojis = map[string]string{
+	"feat":     ":sparkles:",
+	"fix":      ":bug:",
+	"docs":     ":memo:",
+	"style":    ":art:",
+	"refactor": ":recycle:",
+	"perf":     ":zap:",
+	"test":     ":white_check_mark:",
+	"build":    ":package:",
+	"ci":       ":construction_worker:",
+	"chore":    ":wrench:",
+}
+
+// initGitmoji turns on emoji for --gitmoji, whose emoji are the point, and
+// applies emoji.enabled and emoji.gitmoji.
+func initGitmoji() {
+	useEmoji = useEmoji || cfg.Emoji.Enabled
+	useGitmoji = useGitmoji || cfg.Emoji.Gitmoji
+	if useGitmoji {
+		useEmoji = true
+	}
+}
+
+// gitmojiByCode finds a catalogue entry by its shortcode.
+func gitmojiByCode(code string) (gitmoji, bool) {
+	for _, g := range gitmojis {
+		if g.Code == code {
+			return g, true
+		}
+	}
+	return gitmoji{}, false
+}
+
+// gitmojiByEmoji finds a catalogue entry by its emoji. Variation
+// selectors are ignored, as editors often drop them.
+func gitmojiByEmoji(emoji string) (gitmoji, bool) {
+	emoji = strings.TrimSuffix(emoji, "\ufe0f")
+	for _, g := range gitmojis {
+		if strings.TrimSuffix(g.Emoji, "\ufe0f") == emoji {
+			return g, true
+		}
+	}
+	return gitmoji{}, false
+}
+
+// typeGitmoji is the gitmoji for a commit type, with security.type
+// committed as :lock:.
+func typeGitmoji(commitType string) (gitmoji, bool) {
+	code, ok := typeGitmojis[commitType]
+	if !ok && commitType == cfg.Security.Type && commitType != "" {
+		code, ok = ":lock:", true
+	}
+	if !ok {
+		return gitmoji{}, false
+	}
+	return gitmojiByCode(code)
+}
+
+// selectGitmojiInteractive offers the whole gitmoji catalogue, searchable
+// by emoji code, description or type, and returns the type the chosen
+// gitmoji maps to with the emoji.
+func selectGitmojiInteractive(suggested string) (string, string) {
+	templates := &promptui.SelectTemplates{
+		Label:    "{{ . }}?",
+		Active:   "▸ {{ .Emoji }} {{ .Code | cyan }} {{ .Description }} {{ .Type | faint }}",
+		Inactive: "  {{ .Emoji }} {{ .Code | cyan }} {{ .Description }} {{ .Type | faint }}",
+		Selected: "{{ .Emoji }} {{ .Type | cyan }}",
+	}
+	if !emojiSupported() {
+		templates.Active = "> {{ .Code | cyan }} {{ .Description }} {{ .Type | faint }}"
+		templates.Inactive = "  {{ .Code | cyan }} {{ .Description }} {{ .Type | faint }}"
+		templates.Selected = "{{ .Code }} {{ .Type | cyan }}"
+	}
+
+	cursor := 0
+	if g, ok := typeGitmoji(suggested); ok {
+		for i, entry := range gitmojis {
+			if entry.Code == g.Code {
+				cursor = i
+			}
+		}
+	}
+
+	prompt := promptui.Select{
+		Label:             tr("prompt.select_gitmoji"),
+		Items:             gitmojis,
+		Templates:         templates,
+		Size:              10,
+		CursorPos:         cursor,
+		StartInSearchMode: true,
+		Searcher: func(input string, index int) bool {
+			return gitmojiMatches(gitmojis[index], index, input)
+		},
+	}
+	idx, _, err := runSelect(prompt)
+	if err != nil {
+		color.Red("%s", tr("prompt.selection_cancelled"))
+		os.Exit(0)
+	}
+
+	selected := gitmojis[idx]
+	return selected.Type, selected.Emoji + " "
+}
+
+// gitmojiMatches reports whether a search matches a gitmoji: its number,
+// part of its code or description, or its type.
+func gitmojiMatches(g gitmoji, index int, input string) bool {
+	input = strings.ToLower(strings.TrimSpace(input))
+	if n, err := strconv.Atoi(input); err == nil {
+		return n == index+1
+	}
+	return strings.Contains(g.Code, strings.Trim(input, ":")) ||
+		strings.Contains(strings.ToLower(g.Description), input) ||
+		g.Type == input
+}
+
+// String describes a gitmoji in accessible mode.
+func (g gitmoji) String() string {
+	return fmt.Sprintf("%s - %s (%s)", g.Code, g.Description, g.Type)
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import "testing"
+
+func TestGitmojiCatalogue(t *testing.T) {
+	if len(gitmojis) < 70 {
+		t.Errorf("catalogue has %d gitmojis, want the full list", len(gitmojis))
+	}
+	types := commitTypeNames()
+	seen := map[string]bool{}
+	for _, g := range gitmojis {
+		if seen[g.Code] {
+			t.Errorf("%s listed twice", g.Code)
+		}
+		seen[g.Code] = true
+		if !contains(types, g.Type) {
+			t.Errorf("%s maps to %q, which is not a commit type", g.Code, g.Type)
+		}
+	}
+	for commitType, code := range typeGitmojis {
+		if g, ok := gitmojiByCode(code); !ok || g.Type != commitType {
+			t.Errorf("default gitmoji for %s is %s, which maps to %q", commitType, code, g.Type)
+		}
+	}
+}
+
+func TestGitmojiMatches(t *testing.T) {
+	ambulance, _ := gitmojiByCode(":ambulance:")
+	index := 4
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"", true},
+		{"5", true},
+		{"6", false},
+		{":ambul", true},
+		{"HOTFIX", true},
+		{"fix", true},
+		{"feat", false},
+	}
+	for _, tt := range tests {
+		if got := gitmojiMatches(ambulance, index, tt.input); got != tt.want {
+			t.Errorf("gitmojiMatches(:ambulance:, %q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestGitmojiShortcode(t *testing.T) {
+	defer func(s bool) { cfg.Emoji.Shortcode = s }(cfg.Emoji.Shortcode)
+	cfg.Emoji.Shortcode = true
+	// Without the variation selector, as some editors save it
+	if got := emojiText("🚑 "); got != ":ambulance: " {
+		t.Errorf("emojiText(🚑) = %q, want %q", got, ":ambulance: ")
+	}
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barisdilekci/commitz/internal/detect"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// TestGoldenMessages stages each recorded diff in testdata/golden in a
+// fresh repository, runs it through the non-interactive pipeline and
+// compares the message with its .golden file.
+//
+// To add a case, save "git diff --cached" output as NAME.diff and copy the
+// files it changes, as they were before, into NAME.before. Then run
+// "go test ./cmd -run TestGoldenMessages -update" and check NAME.golden.
+func TestGoldenMessages(t *testing.T) {
+	dir, err := filepath.Abs(filepath.Join("testdata", "golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixtures, err := filepath.Glob(filepath.Join(dir, "*.diff"))
+	if err != nil || len(fixtures) == 0 {
+		t.Fatalf("no fixtures found: %v", err)
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	isolateGit(t)
+	defer func(i, y bool) { interactive, assumeYes = i, y }(interactive, assumeYes)
+	interactive, assumeYes = false, true
+
+	for _, fixture := range fixtures {
+		name := strings.TrimSuffix(filepath.Base(fixture), ".diff")
+		t.Run(name, func(t *testing.T) {
+			stageFixture(t, filepath.Join(dir, name))
+
+			files, err := loadStagedFiles()
+			if err != nil {
+				t.Fatal(err)
+			}
+			classifyFiles(files)
+			got := composeMessage(detect.DiffText(files), files) + "\n"
+
+			golden := filepath.Join(dir, name+".golden")
+			if *updateGolden {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v; run with -update to create it", err)
+			}
+			if got != string(want) {
+				t.Errorf("message for %s.diff changed\ngot:\n%s\nwant:\n%s", name, got, want)
+			}
+		})
+	}
+}
+
+// isolateGit keeps the user's git config, identity and commitz data out
+// of the test.
+func isolateGit(t *testing.T) {
+	t.Helper()
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	for _, key := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(key, "commitz")
+	}
+	for _, key := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(key, "commitz@example.com")
+	}
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+}
+
+// testRepo creates an isolated repository on main, runs each git command
+// in it and makes it the working directory.
+func testRepo(t *testing.T, commands ...[]string) string {
+	t.Helper()
+	isolateGit(t)
+	repo := t.TempDir()
+	for _, args := range append([][]string{{"init", "-q", "-b", "main"}}, commands...) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+	t.Chdir(repo)
+	resetRepoCaches()
+	return repo
+}
+
+// stageFixture creates a repository on main holding fixture.before, if
+// any, and stages fixture.diff in it.
+func stageFixture(t *testing.T, fixture string) {
+	t.Helper()
+	repo := testRepo(t)
+	git := func(args ...string) {
+		t.Helper()
+		if err := gitRun(args...); err != nil {
+			t.Fatalf("git %s: %v", strings.Join(args, " "), err)
+		}
+	}
+
+	if _, err := os.Stat(fixture + ".before"); err == nil {
+		if err := os.CopyFS(repo, os.DirFS(fixture+".before")); err != nil {
+			t.Fatal(err)
+		}
+		git("add", "-A")
+		git("commit", "-q", "-m", "before")
+	}
+	git("apply", "--cached", fixture+".diff")
+}
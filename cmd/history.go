@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+// similarityThreshold is the minimum similarity for two subjects to count
+// as near-identical.
+const similarityThreshold = 0.9
+
+var conventionalPrefix = regexp.MustCompile(`^\W*\w+(\([^)]*\))?!?:\s*`)
+
+// recentSubjects returns the subjects of the current user's commits from the
+// last week, newest first.
+var recentSubjects = sync.OnceValue(loadRecentSubjects)
+
+func loadRecentSubjects() []string {
+	args := []string{"log", "--since=1.week", "--format=%s"}
+	if email, err := gitOutput("config", "user.email"); err == nil {
+		args = append(args, "--author="+strings.TrimSpace(string(email)))
+	}
+
+	if repoClone().Shallow {
+		logger.Debug("shallow clone: duplicate check sees only the fetched commits")
+	}
+	out, err := gitOutput(args...)
+	if err != nil {
+		return nil
+	}
+
+	var subjects []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line != "" {
+			subjects = append(subjects, line)
+		}
+	}
+	return subjects
+}
+
+// checkDuplicateSubject warns when the subject repeats recent history.
+func checkDuplicateSubject(message string) {
+	subject, _, _ := strings.Cut(message, "\n")
+	history := recentSubjects()
+	if len(history) == 0 {
+		return
+	}
+
+	count := 0
+	for _, s := range history {
+		if similarSubjects(subject, s) {
+			count++
+		}
+	}
+	if count == 0 {
+		return
+	}
+
+	fmt.Println()
+	if similarSubjects(subject, history[0]) {
+		color.Yellow("%s", tr("history.double_commit", history[0]))
+	}
+	if count > 1 || !similarSubjects(subject, history[0]) {
+		color.Yellow("%s", tr("history.repeated", normalizeSubject(subject), count))
+	}
+}
+
+// similarSubjects compares subjects ignoring type, scope, emoji and case.
+func similarSubjects(a, b string) bool {
+	a, b = normalizeSubject(a), normalizeSubject(b)
+	if a == "" || b == "" {
+		return false
+	}
+	return similarity(a, b) >= similarityThreshold
+}
+
+func normalizeSubject(subject string) string {
+	subject = strings.ToLower(strings.TrimSpace(subject))
+	subject = conventionalPrefix.ReplaceAllString(subject, "")
+	return strings.Join(strings.Fields(strings.TrimSuffix(subject, ".")), " ")
+}
+
+// similarity returns a 0..1 score based on the Levenshtein distance.
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
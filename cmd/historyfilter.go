@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// historyFilter narrows the commits that changelog, stats and lint --range
+// walk through.
+type historyFilter struct {
+	FirstParent bool
+	Paths       []string
+	Author      string
+}
+
+var historyFilters historyFilter
+
+// addHistoryFilterFlags registers the filter flags on a command that walks
+// history.
+func addHistoryFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&historyFilters.FirstParent,
+		"first-parent",
+		false,
+		"Follow only the first parent of merges, e.g. one entry per merged pull request",
+	)
+
+	cmd.Flags().StringSliceVar(
+		&historyFilters.Paths,
+		"path",
+		nil,
+		"Only commits touching these paths or globs, e.g. 'services/api/**'",
+	)
+
+	cmd.Flags().StringVar(
+		&historyFilters.Author,
+		"author",
+		"",
+		"Only commits by authors matching this pattern",
+	)
+}
+
+// logArgs wraps git log arguments with the filters. Paths go last, after
+// "--".
+func (f historyFilter) logArgs(args ...string) []string {
+	if f.FirstParent {
+		args = append(args, "--first-parent")
+	}
+	if f.Author != "" {
+		args = append(args, "--author="+f.Author)
+	}
+	if len(f.Paths) > 0 {
+		args = append(args, "--")
+		for _, p := range f.Paths {
+			args = append(args, ":(glob)"+p)
+		}
+	}
+	return args
+}
@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+// Hosting platforms with known URL schemes.
+const (
+	hostGitHub    = "github"
+	hostGitLab    = "gitlab"
+	hostBitbucket = "bitbucket"
+	hostGitea     = "gitea"
+)
+
+// issueRefPattern matches issue and PR references like "#12".
+var issueRefPattern = regexp.MustCompile(`(^|[\s(])#(\d+)\b`)
+
+// scpRemotePattern matches scp-like remotes such as git@host:owner/repo.git.
+var scpRemotePattern = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+
+// hostingRepo is where the repository's web pages live.
+type hostingRepo struct {
+	Kind string
+	// Base is the repository's web URL, e.g. https://github.com/owner/repo.
+	Base string
+}
+
+// remoteFlag is --remote, the remote to use for platform integrations.
+var remoteFlag string
+
+// repoHosting resolves the hosting platform from hosting in the config or
+// the canonical remote. The second result is false for unknown hosts.
+var repoHosting = sync.OnceValues(loadRepoHosting)
+
+func loadRepoHosting() (hostingRepo, bool) {
+	if cfg.Hosting.URL != "" {
+		base := strings.TrimSuffix(cfg.Hosting.URL, "/")
+		kind := strings.ToLower(cfg.Hosting.Type)
+		if kind == "" {
+			kind = hostKind(base)
+		}
+		return hostingRepo{Kind: kind, Base: base}, kind != ""
+	}
+
+	remote := canonicalRemote()
+	if remote == "" {
+		return hostingRepo{}, false
+	}
+	host, ok := remoteHosting(remote)
+	logger.Info("hosting detected", "remote", remote, "kind", host.Kind, "url", host.Base)
+	return host, ok
+}
+
+// canonicalRemote is the remote whose platform gets links and API calls:
+// --remote, hosting.remote, or else the one pickRemote prefers. It returns
+// "" when none fits.
+func canonicalRemote() string {
+	if remoteFlag != "" {
+		return remoteFlag
+	}
+	if cfg.Hosting.Remote != "" {
+		return cfg.Hosting.Remote
+	}
+	out, err := gitOutput("remote")
+	if err != nil {
+		return ""
+	}
+	return pickRemote(strings.Fields(string(out)))
+}
+
+// pickRemote prefers upstream, the usual name of the original repository
+// when origin is a fork, then origin, then a lone remote.
+func pickRemote(remotes []string) string {
+	for _, name := range []string{"upstream", "origin"} {
+		if contains(remotes, name) {
+			return name
+		}
+	}
+	if len(remotes) == 1 {
+		return remotes[0]
+	}
+	return ""
+}
+
+// remoteHosting resolves the platform of one remote from its URL.
+func remoteHosting(remote string) (hostingRepo, bool) {
+	out, err := gitOutput("remote", "get-url", remote)
+	if err != nil {
+		if remoteFlag != "" || cfg.Hosting.Remote != "" {
+			color.Yellow("%s", tr("hosting.unknown_remote", remote))
+		}
+		return hostingRepo{}, false
+	}
+	base, ok := remoteWebURL(strings.TrimSpace(string(out)))
+	if !ok {
+		return hostingRepo{}, false
+	}
+	kind := strings.ToLower(cfg.Hosting.Type)
+	if kind == "" {
+		kind = hostKind(base)
+	}
+	return hostingRepo{Kind: kind, Base: base}, kind != ""
+}
+
+// remoteWebURL turns a clone URL into the repository's https URL.
+func remoteWebURL(remote string) (string, bool) {
+	var host, path string
+	if u, err := url.Parse(remote); err == nil && u.Host != "" {
+		host, path = u.Hostname(), u.Path
+	} else if m := scpRemotePattern.FindStringSubmatch(remote); m != nil {
+		host, path = m[1], m[2]
+	} else {
+		return "", false
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	// Bitbucket Server clones live under /scm/
+	path = strings.TrimPrefix(path, "scm/")
+	if host == "" || path == "" {
+		return "", false
+	}
+	return "https://" + host + "/" + path, true
+}
+
+// hostKind guesses the platform from the host name.
+func hostKind(base string) string {
+	u, err := url.Parse(base)
+	if err != nil {
+		return ""
+	}
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case strings.Contains(host, "github"):
+		return hostGitHub
+	case strings.Contains(host, "gitlab"):
+		return hostGitLab
+	case strings.Contains(host, "bitbucket"):
+		return hostBitbucket
+	case strings.Contains(host, "gitea"), host == "codeberg.org":
+		return hostGitea
+	}
+	return ""
+}
+
+func (h hostingRepo) CommitURL(hash string) string {
+	switch h.Kind {
+	case hostGitLab:
+		return h.Base + "/-/commit/" + hash
+	case hostBitbucket:
+		return h.Base + "/commits/" + hash
+	}
+	return h.Base + "/commit/" + hash
+}
+
+func (h hostingRepo) IssueURL(number int) string {
+	if h.Kind == hostGitLab {
+		return fmt.Sprintf("%s/-/issues/%d", h.Base, number)
+	}
+	return fmt.Sprintf("%s/issues/%d", h.Base, number)
+}
+
+func (h hostingRepo) PullRequestURL(number int) string {
+	switch h.Kind {
+	case hostGitLab:
+		return fmt.Sprintf("%s/-/merge_requests/%d", h.Base, number)
+	case hostBitbucket:
+		return fmt.Sprintf("%s/pull-requests/%d", h.Base, number)
+	case hostGitea:
+		return fmt.Sprintf("%s/pulls/%d", h.Base, number)
+	}
+	return fmt.Sprintf("%s/pull/%d", h.Base, number)
+}
+
+// NewPullRequestURL opens the form for a pull request from branch. Gitea
+// has no such link, so it returns "" there.
+func (h hostingRepo) NewPullRequestURL(branch string) string {
+	switch h.Kind {
+	case hostGitLab:
+		return h.Base + "/-/merge_requests/new?merge_request%5Bsource_branch%5D=" + url.QueryEscape(branch)
+	case hostBitbucket:
+		return h.Base + "/pull-requests/new?source=" + url.QueryEscape(branch)
+	case hostGitea:
+		return ""
+	}
+	return h.Base + "/pull/new/" + branch
+}
+
+func (h hostingRepo) CompareURL(from, to string) string {
+	switch h.Kind {
+	case hostGitLab:
+		return h.Base + "/-/compare/" + from + "..." + to
+	case hostBitbucket:
+		return h.Base + "/branches/compare/" + to + "%0D" + from
+	}
+	return h.Base + "/compare/" + from + "..." + to
+}
+
+// hostingFuncs are the link helpers available to changelog templates. They
+// fall back to plain text when the host is unknown.
+func hostingFuncs() map[string]any {
+	host, ok := repoHosting()
+	return map[string]any{
+		"commitLink": func(c historyCommit) string {
+			if !ok {
+				return c.Short
+			}
+			return fmt.Sprintf("[%s](%s)", c.Short, host.CommitURL(c.Hash))
+		},
+		"prLink": func(number int) string {
+			if !ok {
+				return fmt.Sprintf("#%d", number)
+			}
+			return fmt.Sprintf("[#%d](%s)", number, host.PullRequestURL(number))
+		},
+		// linkIssues turns "#12" references in text into links
+		"linkIssues": func(text string) string {
+			if !ok {
+				return text
+			}
+			return issueRefPattern.ReplaceAllStringFunc(text, func(ref string) string {
+				m := issueRefPattern.FindStringSubmatch(ref)
+				number, _ := strconv.Atoi(m[2])
+				return fmt.Sprintf("%s[#%d](%s)", m[1], number, host.IssueURL(number))
+			})
+		},
+		"compareURL": func(from, to string) string {
+			if !ok || from == "" {
+				return ""
+			}
+			return host.CompareURL(from, to)
+		},
+	}
+}
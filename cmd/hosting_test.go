@@ -0,0 +1,35 @@
+package cmd
+
+import "testing"
+
+func TestPickRemote(t *testing.T) {
+	tests := []struct {
+		remotes []string
+		want    string
+	}{
+		{[]string{"origin"}, "origin"},
+		{[]string{"fork", "origin"}, "origin"},
+		{[]string{"origin", "upstream"}, "upstream"},
+		{[]string{"gitlab"}, "gitlab"},
+		{[]string{"gitlab", "github"}, ""},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := pickRemote(tt.remotes); got != tt.want {
+			t.Errorf("pickRemote(%v) = %q, want %q", tt.remotes, got, tt.want)
+		}
+	}
+}
+
+func TestRemoteWebURL(t *testing.T) {
+	tests := map[string]string{
+		"git@github.com:acme/app.git":                 "https://github.com/acme/app",
+		"https://gitlab.example.com/team/app.git":     "https://gitlab.example.com/team/app",
+		"ssh://git@bitbucket.example.com/scm/app.git": "https://bitbucket.example.com/app",
+	}
+	for remote, want := range tests {
+		if got, _ := remoteWebURL(remote); got != want {
+			t.Errorf("remoteWebURL(%q) = %q, want %q", remote, got, want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/message"
+	"github.com/fatih/color"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultLocale is used for missing translations and unknown languages.
+const defaultLocale = "en"
+
+//go:embed locales/*.yaml
+var localeFiles embed.FS
+
+var (
+	locale   = defaultLocale
+	catalogs = loadCatalogs("locales")
+)
+
+// loadCatalogs reads every <lang>.yaml file in an embedded directory.
+func loadCatalogs(dir string) map[string]map[string]string {
+	entries, err := localeFiles.ReadDir(dir)
+	if err != nil {
+		panic(err)
+	}
+
+	catalogs := map[string]map[string]string{}
+	for _, entry := range entries {
+		data, err := localeFiles.ReadFile(dir + "/" + entry.Name())
+		if err != nil {
+			panic(err)
+		}
+
+		catalog := map[string]string{}
+		if err := yaml.Unmarshal(data, &catalog); err != nil {
+			panic(fmt.Sprintf("invalid locale %s: %v", entry.Name(), err))
+		}
+		catalogs[strings.TrimSuffix(entry.Name(), ".yaml")] = catalog
+	}
+
+	return catalogs
+}
+
+// initLocale picks the UI language from config, COMMITZ_LANG or the
+// standard locale environment variables.
+func initLocale() {
+	candidates := []string{
+		cfg.Language,
+		os.Getenv("COMMITZ_LANG"),
+		os.Getenv("LC_ALL"),
+		os.Getenv("LC_MESSAGES"),
+		os.Getenv("LANG"),
+	}
+
+	for _, candidate := range candidates {
+		if lang := normalizeLocale(candidate); lang != "" {
+			if _, ok := catalogs[lang]; ok {
+				locale = lang
+			}
+			return
+		}
+	}
+}
+
+// initMessageLanguage picks the language for generated commit messages
+// from the message_language config value.
+func initMessageLanguage() {
+	lang := normalizeLocale(cfg.MessageLanguage)
+	if lang == "" {
+		return
+	}
+
+	if !message.SetLanguage(lang) {
+		color.Yellow("%s", tr("config.unknown_message_language", cfg.MessageLanguage))
+	}
+}
+
+// normalizeLocale turns values like "tr_TR.UTF-8" into "tr".
+func normalizeLocale(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" || value == "c" || value == "posix" {
+		return ""
+	}
+
+	value, _, _ = strings.Cut(value, ".")
+	value, _, _ = strings.Cut(value, "_")
+	value, _, _ = strings.Cut(value, "-")
+	return value
+}
+
+// tr returns the localized UI message for key, formatted with args.
+func tr(key string, args ...any) string {
+	return displayText(lookup(catalogs, locale, key, args...))
+}
+
+func lookup(catalogs map[string]map[string]string, lang, key string, args ...any) string {
+	text, ok := catalogs[lang][key]
+	if !ok {
+		text, ok = catalogs[defaultLocale][key]
+	}
+	if !ok {
+		text = key
+	}
+
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// isYes reports whether an answer to a yes/no question is affirmative.
+func isYes(answer string) bool {
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	for _, yes := range strings.Split(tr("answer.yes"), ",") {
+		if answer == yes {
+			return true
+		}
+	}
+	return answer == "y" || answer == "yes"
+}
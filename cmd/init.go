@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+var (
+	initPreset string
+	initList   bool
+	initForce  bool
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Start a repository's config from a built-in preset",
+	Long: `Writes .commitz.yaml in the repository root naming one of the built-in
+conventions, which set the commit types, templates and lint rules. Settings
+added to the file override the preset. Without --preset the gallery is
+shown to pick from.`,
+	Example: `  commitz init --list
+  commitz init --preset angular
+  commitz init --preset jira-first --force`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if initList {
+			displayPresets()
+			return
+		}
+
+		name := strings.ToLower(initPreset)
+		if name == "" {
+			if assumeYes || !isatty.IsTerminal(os.Stdin.Fd()) {
+				color.Red("%s", tr("preset.required"))
+				displayPresets()
+				os.Exit(1)
+			}
+			var ok bool
+			if name, ok = selectPreset(); !ok {
+				color.Yellow("%s", tr("commit.cancelled"))
+				os.Exit(1)
+			}
+		}
+		if _, err := presetData(name); err != nil {
+			color.Red("%s", err.Error())
+			os.Exit(1)
+		}
+
+		root, err := repoRoot()
+		if err != nil {
+			color.Red("%s", tr("repo.not_found"))
+			os.Exit(1)
+		}
+		for _, existing := range []string{".commitz.yaml", ".commitz.yml"} {
+			if _, err := os.Stat(filepath.Join(root, existing)); err == nil && !initForce {
+				color.Red("%s", tr("preset.exists", existing))
+				os.Exit(1)
+			}
+		}
+
+		path := filepath.Join(root, ".commitz.yaml")
+		if err := os.WriteFile(path, []byte(presetConfig(name)), 0o644); err != nil {
+			color.Red("%s", tr("preset.write_error", path, err))
+			os.Exit(1)
+		}
+		color.Green("%s", tr("preset.written", path, name))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+
+	initCmd.Flags().StringVar(
+		&initPreset,
+		"preset",
+		"",
+		"Built-in convention: "+strings.Join(presetNames(), ", "),
+	)
+
+	initCmd.Flags().BoolVar(
+		&initList,
+		"list",
+		false,
+		"Show the built-in presets",
+	)
+
+	initCmd.Flags().BoolVar(
+		&initForce,
+		"force",
+		false,
+		"Replace an existing .commitz.yaml",
+	)
+}
+
+// presetConfig is the starting .commitz.yaml for a preset.
+func presetConfig(name string) string {
+	return fmt.Sprintf("# %s\n# %s\npreset: %s\n", presetDescription(name), tr("preset.file_hint"), name)
+}
+
+func displayPresets() {
+	for _, name := range presetNames() {
+		fmt.Printf("  %-12s %s\n", color.CyanString("%s", name), presetDescription(name))
+	}
+}
+
+func selectPreset() (string, bool) {
+	names := presetNames()
+	labels := make([]string, len(names))
+	for i, name := range names {
+		labels[i] = fmt.Sprintf("%-12s %s", name, presetDescription(name))
+	}
+	i, _, err := runSelect(promptui.Select{
+		Label: tr("preset.select"),
+		Items: labels,
+		Size:  len(labels),
+	})
+	if err != nil {
+		return "", false
+	}
+	return names[i], true
+}
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+var (
+	installPrefix string
+	installDir    string
+	installShells []string
+)
+
+// completionTarget is where a shell loads completion scripts from.
+type completionTarget struct {
+	file string
+	// prefixDir is the directory below --prefix, userDir the one below
+	// ~/.local/share
+	prefixDir string
+	userDir   string
+	generate  func(w io.Writer) error
+}
+
+// completionShells are the shells install-completions writes scripts for.
+var completionShells = map[string]completionTarget{
+	"bash": {"commitz", "share/bash-completion/completions", "bash-completion/completions", func(w io.Writer) error {
+		return rootCmd.GenBashCompletionV2(w, true)
+	}},
+	"zsh": {"_commitz", "share/zsh/site-functions", "zsh/site-functions", func(w io.Writer) error {
+		return rootCmd.GenZshCompletion(w)
+	}},
+	// fish reads user completions from ~/.config instead, see
+	// installCompletion
+	"fish": {"commitz.fish", "share/fish/vendor_completions.d", "fish/vendor_completions.d", func(w io.Writer) error {
+		return rootCmd.GenFishCompletion(w, true)
+	}},
+	"powershell": {"commitz.ps1", "share/powershell/completions", "powershell/completions", func(w io.Writer) error {
+		return rootCmd.GenPowerShellCompletionWithDesc(w)
+	}},
+}
+
+var installManpagesCmd = &cobra.Command{
+	Use:   "install-manpages",
+	Short: "Write man pages for commitz and its commands",
+	Long: `Writes a man page for commitz and each of its commands, generated from
+their help. By default they go to ~/.local/share/man/man1. Package builds
+set --prefix, e.g. --prefix "$DESTDIR/usr" writes to
+$DESTDIR/usr/share/man/man1, or --dir to choose the directory itself.`,
+	Example: `  commitz install-manpages
+  man commitz-lint
+
+  # In a package build
+  commitz install-manpages --prefix "$DESTDIR/usr"`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := installDir
+		if dir == "" {
+			dir = installPath(filepath.Join("share", "man", "man1"), filepath.Join("man", "man1"))
+		}
+		installManpages(dir)
+	},
+}
+
+var installCompletionsCmd = &cobra.Command{
+	Use:   "install-completions",
+	Short: "Write shell completion scripts for bash, zsh and fish",
+	Long: `Writes completion scripts where each shell looks for them. Without
+--prefix they go to your user directories: bash-completion and zsh
+site-functions under ~/.local/share and fish completions under
+~/.config/fish. Package builds set --prefix, e.g. --prefix "$DESTDIR/usr",
+to use the system directories below it. --dir writes every script to one
+directory instead.
+
+Use --shell to choose shells; powershell is also supported.`,
+	Example: `  commitz install-completions
+  commitz install-completions --shell zsh --dir ~/.zfunc
+
+  # In a package build
+  commitz install-completions --prefix "$DESTDIR/usr"`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, shell := range installShells {
+			if _, ok := completionShells[shell]; !ok {
+				color.Red("%s", tr("install.unknown_shell", shell))
+				os.Exit(1)
+			}
+		}
+		for _, shell := range installShells {
+			installCompletion(shell)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(installManpagesCmd)
+	rootCmd.AddCommand(installCompletionsCmd)
+
+	for _, c := range []*cobra.Command{installManpagesCmd, installCompletionsCmd} {
+		c.Flags().StringVar(
+			&installPrefix,
+			"prefix",
+			"",
+			"Install below this prefix, such as /usr/local, instead of your home directory",
+		)
+		c.Flags().StringVar(
+			&installDir,
+			"dir",
+			"",
+			"Write the files to this directory",
+		)
+	}
+
+	installCompletionsCmd.Flags().StringSliceVar(
+		&installShells,
+		"shell",
+		[]string{"bash", "zsh", "fish"},
+		"Shells to write completions for: bash, zsh, fish or powershell",
+	)
+}
+
+// installPath returns the directory below --prefix, or below the user's
+// data directory (~/.local/share) without one.
+func installPath(prefixDir, userDir string) string {
+	if installPrefix != "" {
+		return filepath.Join(installPrefix, prefixDir)
+	}
+	base := os.Getenv("XDG_DATA_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			color.Red("%s", tr("install.no_home", err))
+			os.Exit(1)
+		}
+		base = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(base, userDir)
+}
+
+func installManpages(dir string) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		color.Red("%s", tr("install.error", dir, err))
+		os.Exit(1)
+	}
+
+	// No generation date, so package builds are reproducible
+	rootCmd.DisableAutoGenTag = true
+	header := &doc.GenManHeader{
+		Title:   "COMMITZ",
+		Section: "1",
+		Source:  "commitz " + buildInfo().Version,
+		Manual:  "commitz manual",
+	}
+	if err := doc.GenManTree(rootCmd, header, dir); err != nil {
+		color.Red("%s", tr("install.error", dir, err))
+		os.Exit(1)
+	}
+
+	pages, _ := filepath.Glob(filepath.Join(dir, "commitz*.1"))
+	color.Green("%s", tr("install.manpages", len(pages), dir))
+}
+
+func installCompletion(shell string) {
+	target := completionShells[shell]
+	dir := installDir
+	switch {
+	case dir != "":
+	case shell == "fish" && installPrefix == "":
+		dir = filepath.Join(userConfigDir(), "fish", "completions")
+	default:
+		dir = installPath(target.prefixDir, target.userDir)
+	}
+
+	path := filepath.Join(dir, target.file)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		color.Red("%s", tr("install.error", path, err))
+		os.Exit(1)
+	}
+	f, err := os.Create(path)
+	if err == nil {
+		err = target.generate(f)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		color.Red("%s", tr("install.error", path, err))
+		os.Exit(1)
+	}
+
+	color.Green("%s", tr("install.completion", shell, path))
+	if shell == "zsh" && installPrefix == "" && installDir == "" {
+		fmt.Println(tr("install.zsh_hint", dir))
+	}
+}
+
+// userConfigDir is ~/.config, or XDG_CONFIG_HOME when set.
+func userConfigDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		color.Red("%s", tr("install.no_home", err))
+		os.Exit(1)
+	}
+	return filepath.Join(home, ".config")
+}
@@ -0,0 +1,283 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/barisdilekci/commitz/internal/message"
+	"github.com/barisdilekci/commitz/internal/summary"
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+)
+
+func selectCommitTypeInteractive(suggested string) (string, string) {
+	if useGitmoji {
+		return selectGitmojiInteractive(suggested)
+	}
+
+	templates := &promptui.SelectTemplates{
+		Label:    "{{ . }}?",
+		Active:   "▸ {{ .Emoji }} {{ .Type | cyan }} - {{ .Description }}",
+		Inactive: "  {{ .Emoji }} {{ .Type | cyan }} - {{ .Description }}",
+		Selected: "{{ .Emoji }} {{ .Type | cyan }}",
+	}
+	if !emojiSupported() {
+		templates.Active = "> {{ .Type | cyan }} - {{ .Description }}"
+		templates.Inactive = "  {{ .Type | cyan }} - {{ .Description }}"
+		templates.Selected = "{{ .Type | cyan }}"
+	}
+
+	// Recent types come first; a run of one type wins over the heuristics
+	types := withRecentTypes(localizedCommitTypes())
+	preselected := suggested
+	if streak := streakType(); streak != "" {
+		preselected = streak
+	}
+
+	prompt := promptui.Select{
+		Label:     tr("prompt.select_type"),
+		Items:     types,
+		Templates: templates,
+		Size:      10,
+		CursorPos: commitTypeIndex(types, preselected),
+	}
+	idx, _, err := runSelect(prompt)
+	if err != nil {
+		color.Red("%s", tr("prompt.selection_cancelled"))
+		os.Exit(0)
+	}
+
+	selected := types[idx]
+	emoji := ""
+	if useEmoji {
+		emoji = selected.Emoji + " "
+	}
+
+	return selected.Type, emoji
+}
+
+// selectScopeInteractive offers the suggested scope, from the branch, the
+// staged paths or the security heuristics, first, the other scopes of the
+// staged paths next and then recently used scopes.
+func selectScopeInteractive(suggested, label string, staged []string) string {
+	// Get recent and common scopes from project structure
+	commonScopes := slices.Clone(staged)
+	for _, scope := range append(recentChoices(repoLearning().RecentScopes), getCommonScopes()...) {
+		if !contains(commonScopes, scope) {
+			commonScopes = append(commonScopes, scope)
+		}
+	}
+	commonScopes = slices.DeleteFunc(commonScopes, func(scope string) bool { return scope == suggested })
+
+	// Add suggested scope if available
+	labels := commonScopes
+	if suggested != "" {
+		commonScopes = append([]string{suggested}, commonScopes...)
+		labels = append([]string{label}, labels...)
+	}
+
+	session.ScopeCandidates = commonScopes
+
+	// Add "no scope" option
+	labels = append(labels, tr("prompt.skip_scope"))
+
+	prompt := promptui.Select{
+		Label: tr("prompt.select_scope"),
+		Items: labels,
+		Size:  8,
+	}
+	idx, _, err := runSelect(prompt)
+	if err != nil || idx >= len(commonScopes) {
+		return ""
+	}
+
+	selected := []string{commonScopes[idx]}
+	for multipleScopesAllowed() {
+		var remaining []string
+		for _, scope := range commonScopes {
+			if !contains(selected, scope) {
+				remaining = append(remaining, scope)
+			}
+		}
+		if len(remaining) == 0 {
+			break
+		}
+
+		// Finishing comes first so Enter keeps a single scope
+		more := promptui.Select{
+			Label: tr("prompt.select_more_scopes", strings.Join(selected, scopeDelimiter())),
+			Items: append([]string{tr("prompt.scopes_done")}, remaining...),
+			Size:  8,
+		}
+		idx, _, err := runSelect(more)
+		if err != nil || idx == 0 {
+			break
+		}
+		selected = append(selected, remaining[idx-1])
+	}
+
+	return strings.Join(selected, scopeDelimiter())
+}
+
+func getCommonScopes() []string {
+	scopes := []string{}
+
+	// Check for common directories
+	dirs := []string{"cmd", "pkg", "internal", "api", "web", "docs", "test", "config", "auth", "db", "ui"}
+
+	for _, dir := range dirs {
+		if _, err := os.Stat(dir); err == nil {
+			scopes = append(scopes, dir)
+		}
+	}
+
+	// Add generic options
+	scopes = append(scopes, "core", "deps", "ci")
+
+	return scopes
+}
+
+func generateSummaryInteractive(interactive bool, diff string, files []git.File, commitType string) string {
+	// Generate smart suggestion
+	suggestion := message.Imperative(applyLearnedVerb(resolveSummary(diff, files, commitType)))
+	session.SuggestedSummary = suggestion
+
+	if !interactive {
+		session.Summary = suggestion
+		return suggestion
+	}
+
+	// Let the user pick among alternative phrasings first
+	defaultSummary := suggestion
+	candidates := summary.Candidates(suggestion, files, commitType)
+	if len(candidates) > 1 {
+		defaultSummary = selectSummaryCandidate(candidates)
+	}
+
+	validate := func(input string) error {
+		if len(input) < 3 {
+			return errors.New(tr("validate.summary_short"))
+		}
+		if message.Width(input) > maxHeaderLength {
+			return errors.New(tr("validate.summary_long"))
+		}
+		return nil
+	}
+
+	prompt := promptui.Prompt{
+		Label:    tr("prompt.summary", color.CyanString("%s", suggestion)),
+		Default:  defaultSummary,
+		Validate: validate,
+	}
+
+	result, err := runPrompt(prompt)
+	if err != nil {
+		color.Red("%s", tr("prompt.input_cancelled"))
+		os.Exit(0)
+	}
+
+	session.Summary = strings.TrimSpace(result)
+	return session.Summary
+}
+
+func selectSummaryCandidate(candidates []string) string {
+	writeOwn := tr("prompt.write_own")
+
+	prompt := promptui.Select{
+		Label: tr("prompt.pick_summary"),
+		Items: append(candidates, writeOwn),
+		Size:  len(candidates) + 1,
+	}
+
+	_, result, err := runSelect(prompt)
+	if err != nil {
+		color.Red("%s", tr("prompt.selection_cancelled"))
+		os.Exit(0)
+	}
+	if result == writeOwn {
+		return ""
+	}
+
+	return result
+}
+
+// addDescriptionInteractive asks for an optional body, or always reads one
+// when required.
+func addDescriptionInteractive(msg string, interactive, required bool) string {
+	if assumeYes && (!interactive || !required) {
+		return msg
+	}
+
+	if interactive && !required {
+		prompt := promptui.Prompt{
+			Label:     tr("prompt.add_description"),
+			IsConfirm: true,
+		}
+
+		result, err := runPrompt(prompt)
+		if err != nil || !isYes(result) {
+			return msg
+		}
+	}
+
+	fmt.Println("\n" + color.CyanString("%s", tr("prompt.enter_description")))
+
+	var bodyLines []string
+	emptyLineCount := 0
+
+	for {
+		line, err := readLine()
+		if err != nil {
+			break
+		}
+		if line == "" {
+			emptyLineCount++
+			if emptyLineCount >= 2 || (len(bodyLines) > 0 && emptyLineCount >= 1) {
+				break
+			}
+		} else {
+			emptyLineCount = 0
+			bodyLines = append(bodyLines, line)
+			saveDraft(msg + "\n\n" + strings.Join(bodyLines, "\n"))
+		}
+	}
+
+	body := strings.TrimSpace(strings.Join(bodyLines, "\n"))
+	if body == "" {
+		return msg
+	}
+	// The description goes above footers such as BREAKING CHANGE
+	if message.ParseFooters(msg) != nil {
+		cut := strings.LastIndex(msg, "\n\n")
+		return msg[:cut] + "\n\n" + body + msg[cut:]
+	}
+	return msg + "\n\n" + body
+}
+
+func confirmCommitInteractive(interactive bool) bool {
+	if assumeYes {
+		return true
+	}
+
+	if !interactive {
+		fmt.Print(tr("prompt.proceed_plain"))
+		confirm, _ := readLine()
+		return isYes(confirm) || strings.TrimSpace(confirm) == ""
+	}
+
+	prompt := promptui.Prompt{
+		Label:     tr("prompt.proceed"),
+		IsConfirm: true,
+	}
+
+	result, err := runPrompt(prompt)
+	if err != nil {
+		return false
+	}
+
+	return isYes(result) || result == ""
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInteractiveFlow(t *testing.T) {
+	tests := []struct {
+		name   string
+		script []promptStep
+		want   string
+	}{
+		{
+			name: "commit",
+			script: []promptStep{
+				{"commit type", "fix"},
+				{"scope", "internal"},
+				{"Add another scope", "Done"},
+				{"summary suggestion", "fix issue"},
+				{"Commit summary", "handle Get before the first Set"},
+				{"Add detailed description", "y"},
+				{"", "Get read from a nil map."},
+				{"", ""},
+				{"Proceed with commit", "y"},
+			},
+			want: "fix(internal): handle Get before the first Set\n\nGet read from a nil map.",
+		},
+		{
+			name: "suggested summary without description",
+			script: []promptStep{
+				{"commit type", ""},
+				{"scope", "internal"},
+				{"Add another scope", "Done"},
+				{"summary suggestion", ""},
+				{"Commit summary", ""},
+				{"Add detailed description", "n"},
+				{"Proceed with commit", "y"},
+			},
+			want: "fix(internal): fix issue in cache",
+		},
+		{
+			name: "cancel",
+			script: []promptStep{
+				{"commit type", "fix"},
+				{"scope", "internal"},
+				{"Add another scope", "Done"},
+				{"summary suggestion", ""},
+				{"Commit summary", ""},
+				{"Add detailed description", "n"},
+				{"Proceed with commit", "n"},
+			},
+			want: "before",
+		},
+	}
+
+	fixture, err := filepath.Abs(filepath.Join("testdata", "golden", "nil-map-fix"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	isolateGit(t)
+	defer func(i bool) { interactive = i }(interactive)
+	interactive = true
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stageFixture(t, fixture)
+			script := useScript(t, tt.script...)
+
+			generateCommitMessage()
+
+			if len(script.steps) > 0 {
+				t.Errorf("script has %d unused steps, from %q", len(script.steps), script.steps[0].Label)
+			}
+			out, err := exec.Command("git", "log", "-1", "--format=%B").Output()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSpace(string(out)); got != tt.want {
+				t.Errorf("last commit is %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
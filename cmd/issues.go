@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ticketTimeout bounds the tracker lookup, which runs before every
+// suggestion.
+const ticketTimeout = 5 * time.Second
+
+// Trackers for issues.provider.
+const (
+	issuesGitHub = "github"
+	issuesGitLab = "gitlab"
+	issuesJira   = "jira"
+)
+
+// ticketTypes maps ticket types and labels to commit types.
+// issues.types adds to and overrides it.
+var ticketTypes = map[string]string{
+	"bug":           "fix",
+	"defect":        "fix",
+	"incident":      "fix",
+	"regression":    "fix",
+	"story":         "feat",
+	"feature":       "feat",
+	"new feature":   "feat",
+	"enhancement":   "feat",
+	"improvement":   "feat",
+	"task":          "chore",
+	"chore":         "chore",
+	"maintenance":   "chore",
+	"documentation": "docs",
+	"docs":          "docs",
+}
+
+var (
+	// jiraKeyPattern matches a Jira key such as PROJ-123, in any case as
+	// branch names are often lowercased.
+	jiraKeyPattern = regexp.MustCompile(`(?i)\b([a-z][a-z0-9]+-\d+)\b`)
+	// issueNumberPattern matches an issue number starting a branch
+	// segment, as in feature/42-login or gh-42.
+	issueNumberPattern = regexp.MustCompile(`(?:^|/)(?:issue-|gh-)?#?(\d+)(?:[-_]|$)`)
+)
+
+// issueTicket is what the tracker knows about a ticket.
+type issueTicket struct {
+	Key    string
+	Type   string
+	Labels []string
+}
+
+// issuesProvider returns the configured tracker, or "" when none is.
+func issuesProvider() string {
+	return strings.ToLower(cfg.Issues.Provider)
+}
+
+// branchTicketKey returns the ticket the current branch is named after:
+// the "ticket" group of a branch.patterns match, or else a Jira key for
+// Jira and an issue number for GitHub and GitLab.
+func branchTicketKey() (string, bool) {
+	branch, err := currentBranch()
+	if err != nil || branch == "" {
+		return "", false
+	}
+	if parts, ok := parseBranch(branch); ok && parts.Ticket != "" {
+		return parts.Ticket, true
+	}
+	switch issuesProvider() {
+	case issuesJira:
+		if m := jiraKeyPattern.FindStringSubmatch(branch); m != nil {
+			return strings.ToUpper(m[1]), true
+		}
+	case issuesGitHub, issuesGitLab:
+		if m := issueNumberPattern.FindStringSubmatch(branch); m != nil {
+			return m[1], true
+		}
+	}
+	return "", false
+}
+
+// ticketCommitType looks up the branch's ticket and maps its type, or
+// else its first known label, to a commit type. Lookup failures are only
+// logged; the diff heuristics decide then.
+func ticketCommitType() (string, string, bool) {
+	if issuesProvider() == "" {
+		return "", "", false
+	}
+	key, ok := branchTicketKey()
+	if !ok || skipOffline("offline.issues") {
+		return "", "", false
+	}
+
+	ctx, cancel := context.WithTimeout(rootCtx, ticketTimeout)
+	defer cancel()
+	t, err := fetchTicket(ctx, key)
+	if err != nil {
+		logger.Info("ticket lookup failed", "ticket", key, "error", err)
+		return "", "", false
+	}
+	logger.Info("ticket found", "ticket", key, "type", t.Type, "labels", t.Labels)
+
+	for _, name := range append([]string{t.Type}, t.Labels...) {
+		if commitType, ok := ticketTypeFor(name); ok {
+			return commitType, tr("why.ticket", key, name), true
+		}
+	}
+	return "", "", false
+}
+
+// ticketTypeFor maps one ticket type or label to a commit type. Scoped
+// labels such as "type::bug", "type: bug" or "kind/bug" count by their
+// last part.
+func ticketTypeFor(name string) (string, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return "", false
+	}
+	if i := strings.LastIndexAny(name, ":/"); i >= 0 {
+		name = strings.TrimSpace(name[i+1:])
+	}
+	for configured, commitType := range cfg.Issues.Types {
+		if strings.ToLower(configured) == name {
+			return commitType, true
+		}
+	}
+	commitType, ok := ticketTypes[name]
+	return commitType, ok
+}
+
+// fetchTicket asks the configured tracker about a ticket.
+func fetchTicket(ctx context.Context, key string) (issueTicket, error) {
+	t := issueTicket{Key: key}
+	switch issuesProvider() {
+	case issuesGitHub:
+		api, repo, err := githubRepo()
+		if err != nil {
+			return t, err
+		}
+		header := http.Header{"Accept": {"application/vnd.github+json"}}
+		// Public repositories work without a token
+		if token, err := githubToken(); err == nil {
+			header.Set("Authorization", "Bearer "+token)
+		}
+		var issue struct {
+			Type *struct {
+				Name string `json:"name"`
+			} `json:"type"`
+			Labels []struct {
+				Name string `json:"name"`
+			} `json:"labels"`
+		}
+		if err := getJSON(ctx, api+"/repos/"+repo+"/issues/"+key, header, &issue); err != nil {
+			return t, err
+		}
+		if issue.Type != nil {
+			t.Type = issue.Type.Name
+		}
+		for _, label := range issue.Labels {
+			t.Labels = append(t.Labels, label.Name)
+		}
+
+	case issuesGitLab:
+		host, ok := repoHosting()
+		if !ok || host.Kind != hostGitLab {
+			return t, errors.New(tr("issues.not_gitlab"))
+		}
+		u, err := url.Parse(host.Base)
+		if err != nil {
+			return t, err
+		}
+		header := http.Header{}
+		if token := providerToken("gitlab"); token != "" {
+			header.Set("PRIVATE-TOKEN", token)
+		}
+		project := url.PathEscape(strings.Trim(u.Path, "/"))
+		var issue struct {
+			IssueType string   `json:"issue_type"`
+			Labels    []string `json:"labels"`
+		}
+		if err := getJSON(ctx, u.Scheme+"://"+u.Host+"/api/v4/projects/"+project+"/issues/"+key, header, &issue); err != nil {
+			return t, err
+		}
+		// Every GitLab issue has type "issue", which says nothing
+		if issue.IssueType != "issue" {
+			t.Type = issue.IssueType
+		}
+		t.Labels = issue.Labels
+
+	case issuesJira:
+		if cfg.Issues.URL == "" {
+			return t, errors.New(tr("issues.no_jira_url"))
+		}
+		header := http.Header{"Accept": {"application/json"}}
+		jira, _ := findAuthProvider("jira")
+		token, _ := authToken(jira)
+		switch user := authUser(jira); {
+		case user != "" && token != "":
+			header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(user+":"+token)))
+		case token != "":
+			header.Set("Authorization", "Bearer "+token)
+		}
+		var issue struct {
+			Fields struct {
+				IssueType struct {
+					Name string `json:"name"`
+				} `json:"issuetype"`
+				Labels []string `json:"labels"`
+			} `json:"fields"`
+		}
+		endpoint := strings.TrimSuffix(cfg.Issues.URL, "/") + "/rest/api/2/issue/" + url.PathEscape(key) + "?fields=issuetype,labels"
+		if err := getJSON(ctx, endpoint, header, &issue); err != nil {
+			return t, err
+		}
+		t.Type = issue.Fields.IssueType.Name
+		t.Labels = issue.Fields.Labels
+
+	default:
+		return t, errors.New(tr("issues.unknown_provider", cfg.Issues.Provider))
+	}
+	return t, nil
+}
+
+// getJSON fetches a URL and decodes the JSON response into v.
+func getJSON(ctx context.Context, endpoint string, header http.Header, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header = header
+
+	client, err := httpClient()
+	if err != nil {
+		return err
+	}
+	logger.Debug("http", "url", endpoint)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s", resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
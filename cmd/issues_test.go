@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barisdilekci/commitz/internal/config"
+)
+
+func TestTicketTypeFor(t *testing.T) {
+	defer func(types map[string]string) { cfg.Issues.Types = types }(cfg.Issues.Types)
+	cfg.Issues.Types = map[string]string{"Spike": "chore", "task": "feat"}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Bug", "fix"},
+		{"type::bug", "fix"},
+		{"kind/feature", "feat"},
+		{"Type: Story", "feat"},
+		{"spike", "chore"},
+		{"Task", "feat"},
+		{"good first issue", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got, _ := ticketTypeFor(tt.name); got != tt.want {
+			t.Errorf("ticketTypeFor(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBranchTicketKey(t *testing.T) {
+	defer func(p string, b func() (string, error)) { cfg.Issues.Provider, currentBranch = p, b }(cfg.Issues.Provider, currentBranch)
+
+	tests := []struct {
+		provider, branch, want string
+	}{
+		{"jira", "feature/proj-42-login", "PROJ-42"},
+		{"jira", "main", ""},
+		{"github", "fix/42-crash", "42"},
+		{"github", "gh-7", "7"},
+		{"gitlab", "release/v2", ""},
+	}
+	for _, tt := range tests {
+		cfg.Issues.Provider = tt.provider
+		currentBranch = func() (string, error) { return tt.branch, nil }
+		if got, _ := branchTicketKey(); got != tt.want {
+			t.Errorf("%s branch %q: ticket %q, want %q", tt.provider, tt.branch, got, tt.want)
+		}
+	}
+}
+
+func TestTicketCommitTypeJira(t *testing.T) {
+	setOffline(t, false)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/issue/PROJ-42" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"fields": {"issuetype": {"name": "Bug"}, "labels": ["backend"]}}`))
+	}))
+	defer server.Close()
+
+	defer func(issues config.IssuesConfig, b func() (string, error)) { cfg.Issues, currentBranch = issues, b }(cfg.Issues, currentBranch)
+	cfg.Issues = config.IssuesConfig{Provider: "jira", URL: server.URL}
+	currentBranch = func() (string, error) { return "PROJ-42-null-session", nil }
+
+	if got, _, ok := ticketCommitType(); !ok || got != "fix" {
+		t.Errorf("ticketCommitType() = %q, %v, want fix", got, ok)
+	}
+
+	currentBranch = func() (string, error) { return "PROJ-7-unknown", nil }
+	if got, _, ok := ticketCommitType(); ok {
+		t.Errorf("ticketCommitType() for a missing ticket = %q, want no type", got)
+	}
+}
+
+func TestRepoConfigCannotMoveJira(t *testing.T) {
+	setOffline(t, false)
+	var stolen []string
+	evil := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stolen = append(stolen, r.Header.Get("Authorization"))
+		w.Write([]byte(`{"fields": {"issuetype": {"name": "Bug"}}}`))
+	}))
+	defer evil.Close()
+
+	dir := t.TempDir()
+	user := filepath.Join(dir, "config.yaml")
+	repo := filepath.Join(dir, ".commitz.yaml")
+	if err := os.WriteFile(user, []byte("issues:\n  provider: jira\n  url: https://acme.atlassian.net\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(repo, []byte("issues:\n  url: "+evil.URL+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var layered config.Config
+	if _, err := readConfigLayer(user, &layered); err != nil {
+		t.Fatal(err)
+	}
+	ignored, err := readConfigLayer(repo, &layered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(ignored, ",") != "issues.url" || layered.Issues.URL != "https://acme.atlassian.net" {
+		t.Fatalf("ignored %v, issues.url %q; want the user's Jira site", ignored, layered.Issues.URL)
+	}
+
+	// Without a user-level site the token must not go to the repository's
+	layered = config.Config{}
+	if _, err := readConfigLayer(repo, &layered); err != nil {
+		t.Fatal(err)
+	}
+	defer func(issues config.IssuesConfig, b func() (string, error)) { cfg.Issues, currentBranch = issues, b }(cfg.Issues, currentBranch)
+	cfg.Issues = config.IssuesConfig{Provider: "jira", URL: layered.Issues.URL}
+	currentBranch = func() (string, error) { return "PROJ-42-login", nil }
+	t.Setenv("JIRA_TOKEN", "secret")
+	ticketCommitType()
+	if len(stolen) > 0 {
+		t.Errorf("Jira token sent to the repository's issues.url: %q", stolen)
+	}
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/ui"
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+)
+
+// selectKeys builds the selector key bindings from keys in the config,
+// warning about key names it cannot read.
+func selectKeys() *promptui.SelectKeys {
+	keys, invalid := ui.SelectKeys(cfg.Keys)
+	for _, name := range invalid {
+		color.Yellow("%s", tr("keys.invalid", name))
+	}
+	return keys
+}
+
+// applyKeys sets the configured key bindings on a selector. With
+// keys.quick_select it starts in search mode, where typing the number
+// shown next to an item picks it.
+func applyKeys(prompt *promptui.Select) {
+	prompt.Keys = selectKeys()
+	if cfg.Keys.QuickSelect {
+		ui.QuickSelect(prompt)
+	}
+}
+
+// escapeKeepsDefault reports whether Esc keeps the pre-selected item
+// rather than closing the selector without a choice.
+func escapeKeepsDefault() bool {
+	switch strings.ToLower(cfg.Keys.Escape) {
+	case "", ui.EscapeCancel:
+		return false
+	case ui.EscapeDefault:
+		return true
+	}
+	color.Yellow("%s", tr("keys.invalid_escape", cfg.Keys.Escape))
+	return false
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/barisdilekci/commitz/internal/ui"
+	"github.com/manifoldco/promptui"
+)
+
+func TestQuickSelectNumbers(t *testing.T) {
+	saved := cfg.Keys
+	t.Cleanup(func() { cfg.Keys = saved })
+	cfg.Keys.QuickSelect = true
+
+	types := localizedCommitTypes()
+	prompt := promptui.Select{
+		Items:     types,
+		Templates: &promptui.SelectTemplates{Inactive: "{{ .Type }}"},
+	}
+	applyKeys(&prompt)
+
+	tmpl := template.Must(template.New("").Funcs(prompt.Templates.FuncMap).Parse(prompt.Templates.Inactive))
+	var out strings.Builder
+	if err := tmpl.Execute(&out, types[2]); err != nil {
+		t.Fatal(err)
+	}
+	if got := ui.ColorCodePattern.ReplaceAllString(out.String(), ""); got != " 3 "+types[2].Type {
+		t.Errorf("inactive item = %q, want its number first", got)
+	}
+	if !prompt.Searcher("3", 2) || prompt.Searcher("3", 1) {
+		t.Error("typing a number does not pick the item shown with it")
+	}
+
+	// Recent types must not renumber the list
+	savedLearning := repoLearning
+	t.Cleanup(func() { repoLearning = savedLearning })
+	repoLearning = func() *learning { return &learning{RecentTypes: []string{"docs"}} }
+	if got := withRecentTypes(types); got[0].Type != types[0].Type {
+		t.Errorf("withRecentTypes() moved %s first", got[0].Type)
+	}
+	cfg.Keys.QuickSelect = false
+	if got := withRecentTypes(types); got[0].Type != "docs" {
+		t.Errorf("withRecentTypes() kept %s first without quick_select", got[0].Type)
+	}
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/fatih/color"
+)
+
+// defaultLargeFileThreshold is used when large_files.threshold is unset.
+const defaultLargeFileThreshold = 5 << 20
+
+// largeFile is a staged file above the size threshold.
+type largeFile struct {
+	Path string
+	Size int64
+}
+
+// findLargeFiles returns staged files bigger than the configured threshold.
+func findLargeFiles(files []git.File) []largeFile {
+	threshold, err := parseSize(cfg.LargeFiles.Threshold)
+	if err != nil {
+		color.Yellow("%s", tr("large.invalid_threshold", cfg.LargeFiles.Threshold, err))
+		threshold = defaultLargeFileThreshold
+	}
+	if threshold <= 0 {
+		return nil
+	}
+
+	var large []largeFile
+	for _, f := range files {
+		if f.Deleted {
+			continue
+		}
+		out, err := gitOutput("cat-file", "-s", ":"+f.Path)
+		if err != nil {
+			continue
+		}
+		size, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+		if err == nil && size > threshold {
+			large = append(large, largeFile{Path: f.Path, Size: size})
+		}
+	}
+
+	return large
+}
+
+// displayLargeFiles warns about large staged files and suggests Git LFS.
+func displayLargeFiles(large []largeFile) {
+	if len(large) == 0 {
+		return
+	}
+
+	fmt.Println()
+	color.Yellow("%s", tr("large.title"))
+	var patterns []string
+	for _, f := range large {
+		fmt.Printf("  %s  %s\n", color.CyanString("%s", f.Path), formatSize(f.Size))
+		if ext := filepath.Ext(f.Path); ext != "" && !contains(patterns, "*"+ext) {
+			patterns = append(patterns, "*"+ext)
+		}
+	}
+
+	fmt.Println(tr("large.hint"))
+	for _, p := range patterns {
+		fmt.Printf("  git lfs track %q\n", p)
+	}
+}
+
+// parseSize parses sizes like "500KB", "5MB" or "1GB". An empty value means
+// the default threshold and "off" disables the check.
+func parseSize(value string) (int64, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	switch value {
+	case "":
+		return defaultLargeFileThreshold, nil
+	case "OFF", "0":
+		return 0, nil
+	}
+
+	units := []struct {
+		suffix string
+		factor int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}}
+
+	for _, u := range units {
+		if strings.HasSuffix(value, u.suffix) {
+			n, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(value, u.suffix)), 64)
+			if err != nil {
+				return 0, err
+			}
+			return int64(n * float64(u.factor)), nil
+		}
+	}
+
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// formatSize renders a byte count for humans.
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(size)/(1<<30))
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", size)
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/barisdilekci/commitz/internal/summary"
+)
+
+// learningFile keeps per-repository feedback in the git directory, so it is
+// local to this clone and never committed.
+const learningFile = "commitz-learning.json"
+
+// learnThreshold is how many consistent choices are needed before they
+// override the built-in heuristics.
+const learnThreshold = 3
+
+// recentLimit is how many of the latest types and scopes are remembered.
+const recentLimit = 10
+
+// recentStreak is how many commits in a row with one type make it the
+// preselected type, as in a long docs session.
+const recentStreak = 3
+
+// learning records what the user actually chose, per repository.
+type learning struct {
+	// Types counts the committed type per top-level directory.
+	Types map[string]map[string]int `json:"types"`
+	// Verbs counts replacements of a suggested summary's leading verb.
+	Verbs map[string]map[string]int `json:"verbs"`
+	// RecentTypes and RecentScopes hold the latest choices, newest first.
+	RecentTypes  []string `json:"recent_types"`
+	RecentScopes []string `json:"recent_scopes"`
+}
+
+var repoLearning = sync.OnceValue(loadLearning)
+
+func loadLearning() *learning {
+	l := &learning{Types: map[string]map[string]int{}, Verbs: map[string]map[string]int{}}
+
+	data, err := os.ReadFile(learningPath())
+	if err != nil {
+		return l
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		logger.Info("ignoring unreadable learning file", "error", err)
+	}
+	if l.Types == nil {
+		l.Types = map[string]map[string]int{}
+	}
+	if l.Verbs == nil {
+		l.Verbs = map[string]map[string]int{}
+	}
+	return l
+}
+
+func learningPath() string {
+	out, err := gitOutput("rev-parse", "--git-path", learningFile)
+	if err != nil {
+		return learningFile
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// changeArea returns the top-level directory all files share, or "".
+func changeArea(files []git.File) string {
+	area, _, _ := strings.Cut(summary.CommonDir(files), "/")
+	return area
+}
+
+// learnedType returns the type this repository's commits in the same area
+// consistently use, when it differs from the detected one.
+func learnedType(files []git.File, detected string) (string, int, bool) {
+	counts := repoLearning().Types[changeArea(files)]
+	best, bestCount, total := preferred(counts)
+	if best == "" || best == detected || bestCount < learnThreshold || bestCount*3 < total*2 {
+		return "", 0, false
+	}
+	return best, bestCount, true
+}
+
+// applyLearnedVerb swaps the leading verb of a suggested summary for the
+// one the user keeps replacing it with.
+func applyLearnedVerb(summary string) string {
+	verb, rest, ok := strings.Cut(summary, " ")
+	if !ok {
+		return summary
+	}
+
+	best, bestCount, _ := preferred(repoLearning().Verbs[strings.ToLower(verb)])
+	if best == "" || bestCount < learnThreshold {
+		return summary
+	}
+	return best + " " + rest
+}
+
+func preferred(counts map[string]int) (string, int, int) {
+	var best string
+	var bestCount, total int
+	for name, count := range counts {
+		total += count
+		if count > bestCount || (count == bestCount && name < best) {
+			best, bestCount = name, count
+		}
+	}
+	return best, bestCount, total
+}
+
+// recordLearning stores the choices of a completed commit.
+func recordLearning(files []git.File) {
+	if session.SelectedType == "" {
+		return
+	}
+
+	l := repoLearning()
+	if area := changeArea(files); area != "" {
+		if l.Types[area] == nil {
+			l.Types[area] = map[string]int{}
+		}
+		l.Types[area][session.SelectedType]++
+	}
+
+	// Only a changed verb with the same remaining text is a verb preference
+	suggestedVerb, suggestedRest, ok1 := strings.Cut(session.SuggestedSummary, " ")
+	finalVerb, finalRest, ok2 := strings.Cut(session.Summary, " ")
+	if ok1 && ok2 && strings.EqualFold(suggestedRest, finalRest) && !strings.EqualFold(suggestedVerb, finalVerb) {
+		key := strings.ToLower(suggestedVerb)
+		if l.Verbs[key] == nil {
+			l.Verbs[key] = map[string]int{}
+		}
+		l.Verbs[key][finalVerb]++
+	}
+
+	l.RecentTypes = remember(l.RecentTypes, session.SelectedType)
+	l.RecentScopes = remember(l.RecentScopes, splitScopes(session.Scope)...)
+
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err == nil {
+		err = writeFileAtomic(learningPath(), data)
+	}
+	if err != nil {
+		logger.Info("learning not saved", "error", err)
+	}
+}
+
+// remember puts choices in front of the recent list, keeping recentLimit.
+func remember(recent []string, choices ...string) []string {
+	recent = append(slices.Clone(choices), recent...)
+	return recent[:min(len(recent), recentLimit)]
+}
+
+// recentChoices returns the remembered choices without repeats, newest
+// first.
+func recentChoices(recent []string) []string {
+	var unique []string
+	for _, choice := range recent {
+		if choice != "" && !contains(unique, choice) {
+			unique = append(unique, choice)
+		}
+	}
+	return unique
+}
+
+// streakType returns the type of the last recentStreak commits when they
+// all share one.
+func streakType() string {
+	recent := repoLearning().RecentTypes
+	if len(recent) < recentStreak {
+		return ""
+	}
+	for _, t := range recent[1:recentStreak] {
+		if t != recent[0] {
+			return ""
+		}
+	}
+	return recent[0]
+}
+
+// withRecentTypes moves the recently used types to the front, newest
+// first. With keys.quick_select the order stays, so each type keeps its
+// number.
+func withRecentTypes(types []CommitType) []CommitType {
+	if cfg.Keys.QuickSelect {
+		return types
+	}
+	var ordered []CommitType
+	for _, name := range recentChoices(repoLearning().RecentTypes) {
+		for _, ct := range types {
+			if ct.Type == name {
+				ordered = append(ordered, ct)
+			}
+		}
+	}
+	for _, ct := range types {
+		if !slices.ContainsFunc(ordered, func(o CommitType) bool { return o.Type == ct.Type }) {
+			ordered = append(ordered, ct)
+		}
+	}
+	return ordered
+}
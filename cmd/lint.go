@@ -0,0 +1,393 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/barisdilekci/commitz/internal/message"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// maxHeaderLength is the longest header lint accepts.
+const maxHeaderLength = 72
+
+// scissorsLine marks where git cuts the message in verbose commits.
+const scissorsLine = "------------------------ >8 ------------------------"
+
+var (
+	lintRange        string
+	lintScore        bool
+	lintFix          bool
+	lintBaselineFlag string
+)
+
+type lintIssue struct {
+	Rule    string
+	Message string
+}
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [file]",
+	Short: "Check commit messages against the conventional commit rules",
+	Long: `Checks a commit message file, such as the one git passes to a commit-msg
+hook, or standard input when no file is given. With --range, checks every
+commit in a revision range instead. Exits with status 1 when a message has
+problems.
+
+With --fix, problems that have an obvious correction, such as a trailing
+period, "feature" instead of "feat", a capitalized subject (with
+lint.subject_case) or overlong body lines (with lint.body_max_line_length),
+are corrected in the file, or the fixed message is printed when reading
+standard input.
+
+With --baseline, or lint.baseline in the config, commits reachable from a
+revision or authored before a date are not checked, so a repository can
+adopt the rules without failing on its history.`,
+	Example: `  # In .git/hooks/commit-msg
+  commitz lint --fix "$1"
+
+  # Check a branch before opening a pull request, with quality scores
+  commitz lint --range main..HEAD --score
+
+  # Only enforce the rules from v2.0.0 on
+  commitz lint --range main..HEAD --baseline v2.0.0`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		baseline, hasBaseline, err := resolveBaseline()
+		if err != nil {
+			color.Red("%s", err.Error())
+			os.Exit(1)
+		}
+
+		if lintRange != "" {
+			if !lintCommitRange(lintRange, baseline) {
+				os.Exit(1)
+			}
+			return
+		}
+
+		// A rebase rewording a grandfathered commit keeps its old message
+		if hash, authored, ok := rebasedCommit(); ok && hasBaseline && baseline.covers(hash, authored) {
+			fmt.Println(tr("lint.baseline_skip", hash[:min(7, len(hash))]))
+			return
+		}
+
+		message, err := readMessageInput(args)
+		if err != nil {
+			color.Red("%s", tr("lint.read_error", err))
+			os.Exit(1)
+		}
+
+		// With --fix on stdin, stdout only gets the fixed message
+		report := io.Writer(os.Stdout)
+		if lintFix {
+			if readsStdin(args) {
+				report = os.Stderr
+			}
+			message = fixMessageInput(message, args, report)
+		}
+
+		// The branch being committed on is only known for a single message
+		issues := append(lintMessage(message), ticketIssues(message)...)
+		printLintIssues(report, "", issues)
+		if lintScore {
+			printScoreBreakdown(report, scoreMessage(message, -1))
+		}
+		if len(issues) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+
+	lintCmd.Flags().StringVar(
+		&lintRange,
+		"range",
+		"",
+		"Lint every commit in a revision range, e.g. main..HEAD",
+	)
+
+	addHistoryFilterFlags(lintCmd)
+
+	lintCmd.Flags().StringVar(
+		&lintBaselineFlag,
+		"baseline",
+		"",
+		"Skip commits reachable from this revision or authored before this date (YYYY-MM-DD)",
+	)
+
+	lintCmd.Flags().BoolVar(
+		&lintFix,
+		"fix",
+		false,
+		"Correct fixable problems in the message file instead of only reporting them",
+	)
+
+	lintCmd.Flags().BoolVar(
+		&lintScore,
+		"score",
+		false,
+		"Also print the quality score of each message",
+	)
+}
+
+// readsStdin reports whether the message comes from stdin rather than a
+// file.
+func readsStdin(args []string) bool {
+	return len(args) == 0 || args[0] == "-"
+}
+
+// readMessageInput reads the message file named in args, or stdin.
+func readMessageInput(args []string) (string, error) {
+	var data []byte
+	var err error
+	if readsStdin(args) {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		return "", err
+	}
+	return cleanMessage(string(data)), nil
+}
+
+// fixMessageInput applies fixMessage and writes the result back to the
+// message file, or prints it when the message came from stdin. The fixed
+// rules are listed on report.
+func fixMessageInput(message string, args []string, report io.Writer) string {
+	fixed, rules := fixMessage(message)
+
+	if readsStdin(args) {
+		fmt.Println(fixed)
+	} else if len(rules) > 0 {
+		if err := os.WriteFile(args[0], []byte(fixed+"\n"), 0o644); err != nil {
+			fmt.Fprintln(report, color.RedString("%s", tr("lint.fix_error", args[0], err)))
+			os.Exit(1)
+		}
+	}
+
+	for _, rule := range rules {
+		fmt.Fprintf(report, "  %s %s\n", color.GreenString("%s", displayText("✓")), tr("lint.fixed", rule))
+	}
+	return fixed
+}
+
+// cleanMessage removes what git strips before committing: comment lines and
+// everything below the scissors line.
+func cleanMessage(text string) string {
+	commentChar := gitConfig().CommentChar
+
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(line, commentChar) {
+			if strings.Contains(line, scissorsLine) {
+				break
+			}
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, " \t"))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// lintMessage checks a message against the conventional commit rules.
+// Relaxed messages, such as reverts, only get the layout rules.
+func lintMessage(msg string) []lintIssue {
+	mode := exemptionMode(msg)
+	if mode == exemptSkip {
+		return nil
+	}
+
+	var issues []lintIssue
+	add := func(rule, key string, args ...any) {
+		issues = append(issues, lintIssue{Rule: rule, Message: tr(key, args...)})
+	}
+
+	lines := strings.Split(msg, "\n")
+	header := lines[0]
+
+	if strings.TrimSpace(header) == "" {
+		add("header-empty", "lint.header_empty")
+		return issues
+	}
+
+	if mode != exemptRelaxed {
+		h, ok := message.ParseHeader(header)
+		if !ok {
+			add("header-format", "lint.header_format", header)
+			return issues
+		}
+
+		if !contains(commitTypeNames(), h.Type) {
+			add("type-enum", "lint.type_enum", h.Type, strings.Join(commitTypeNames(), ", "))
+		}
+		if scope := normalizeScope(h.Scope); scope != h.Scope {
+			add("scope-case", "lint.scope_case", h.Scope, scope)
+		}
+		if !multipleScopesAllowed() && len(splitScopes(h.Scope)) > 1 {
+			add("scope-multiple", "lint.scope_multiple", h.Scope)
+		}
+		if strings.TrimSpace(h.Subject) == "" {
+			add("subject-empty", "lint.subject_empty")
+		}
+		if checkSubjectCase() && subjectStartsUpper(h.Subject) {
+			add("subject-case", "lint.subject_case")
+		}
+		if strings.HasSuffix(h.Subject, ".") {
+			add("subject-full-stop", "lint.subject_full_stop")
+		}
+	}
+	if n := message.Width(header); n > maxHeaderLength {
+		add("header-max-length", "lint.header_max_length", n, maxHeaderLength)
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		add("body-leading-blank", "lint.body_leading_blank")
+	}
+	// Only lines --fix can wrap count; URLs and footers may run longer
+	if limit := cfg.Lint.BodyMaxLineLength; limit > 0 {
+		for _, i := range message.Overlong(wrappableBody(lines), limit) {
+			add("body-max-line-length", "lint.body_max_line_length", i+2, message.Width(lines[i+1]), limit)
+		}
+	}
+
+	issues = append(issues, messageRuleIssues(msg)...)
+	issues = append(issues, pluginIssues(msg)...)
+	return append(issues, footerIssues(msg)...)
+}
+
+func commitTypeNames() []string {
+	types := availableCommitTypes()
+	names := make([]string, len(types))
+	for i, ct := range types {
+		names[i] = ct.Type
+	}
+	return names
+}
+
+func displayLintIssues(label string, issues []lintIssue) {
+	printLintIssues(os.Stdout, label, issues)
+}
+
+func printLintIssues(w io.Writer, label string, issues []lintIssue) {
+	if len(issues) == 0 {
+		if label == "" && !quiet {
+			fmt.Fprintln(w, color.GreenString("%s", tr("lint.ok")))
+		}
+		return
+	}
+
+	if label != "" {
+		fmt.Fprintln(w, color.YellowString("%s", label))
+	}
+	for _, issue := range issues {
+		fmt.Fprintf(w, "  %s %s %s\n", color.RedString("%s", displayText("✗")), issue.Message, color.New(color.Faint).Sprintf("[%s]", issue.Rule))
+	}
+}
+
+// lintCommitRange lints every commit in a revision range and reports
+// whether all of them passed.
+func lintCommitRange(revRange string, baseline lintBaseline) bool {
+	var exclude []string
+	if baseline.Ref != "" {
+		exclude = append(exclude, "^"+baseline.Ref)
+	}
+	all, err := rangeCommits(revRange, exclude...)
+	if err != nil {
+		color.Red("%s", tr("lint.range_error", revRange, err))
+		os.Exit(1)
+	}
+
+	var commits []rangeCommit
+	for _, c := range all {
+		if baseline.Since.IsZero() || !c.Authored.Before(baseline.Since) {
+			commits = append(commits, c)
+		}
+	}
+	if skipped := len(all) - len(commits); skipped > 0 {
+		fmt.Println(tr("lint.baseline_skipped", skipped))
+	}
+
+	failed, total := 0, 0
+	for _, c := range commits {
+		subject, _, _ := strings.Cut(c.Message, "\n")
+		label := fmt.Sprintf("%s %s", c.Hash[:min(7, len(c.Hash))], subject)
+
+		issues := lintMessage(c.Message)
+		displayLintIssues(label, issues)
+		if len(issues) > 0 {
+			failed++
+		}
+
+		if lintScore {
+			score := scoreMessage(c.Message, c.ChangedLines)
+			total += score.Total
+			if len(issues) == 0 {
+				fmt.Println(color.YellowString("%s", label))
+			}
+			fmt.Printf("  %s\n", tr("score.line", scoreColor(score.Total)))
+		}
+	}
+
+	if lintScore && len(commits) > 0 {
+		fmt.Println()
+		fmt.Println(tr("lint.average_score", scoreColor(total/len(commits)), len(commits)))
+	}
+	if failed == 0 {
+		color.Green("%s", tr("lint.range_ok", len(commits)))
+		return true
+	}
+	color.Red("%s", tr("lint.range_failed", failed, len(commits)))
+	return false
+}
+
+type rangeCommit struct {
+	Hash         string
+	Authored     time.Time
+	Message      string
+	ChangedLines int
+}
+
+var shortstatPattern = regexp.MustCompile(`(\d+) (insertion|deletion)`)
+
+// rangeCommits lists the commits in a range with their messages and the
+// number of changed lines, oldest first. Extra revisions such as "^v1.0"
+// narrow the range. A partial clone does not count lines, which would
+// download the contents of every commit.
+func rangeCommits(revRange string, extra ...string) ([]rangeCommit, error) {
+	checkHistoryDepth()
+	args := []string{"log", "--reverse", "--format=%x1e%H%x00%at%x00%B%x00"}
+	if !repoClone().Partial {
+		args = append(args, "--shortstat")
+	}
+	args = append(append(args, revRange), extra...)
+	out, err := gitOutput(historyFilters.logArgs(args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []rangeCommit
+	for _, record := range strings.Split(string(out), "\x1e") {
+		fields := strings.SplitN(record, "\x00", 4)
+		if len(fields) < 4 {
+			continue
+		}
+
+		seconds, _ := strconv.ParseInt(fields[1], 10, 64)
+		c := rangeCommit{Hash: fields[0], Authored: time.Unix(seconds, 0), Message: strings.TrimSpace(fields[2])}
+		for _, m := range shortstatPattern.FindAllStringSubmatch(fields[3], -1) {
+			n, _ := strconv.Atoi(m[1])
+			c.ChangedLines += n
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/barisdilekci/commitz/internal/config"
+	"github.com/barisdilekci/commitz/internal/message"
+)
+
+func FuzzLintMessage(f *testing.F) {
+	for _, seed := range []string{
+		"feat(api)!: drop v1\n\nBREAKING CHANGE: v1 is gone",
+		"fix: handle nil\n\n- one\n- two\n\nRefs: #12\nSigned-off-by: A <a@b>",
+		"Revert \"feat: add\"\n\nThis reverts commit abc.",
+		"fixup! feat: add",
+		"✨ feat(ui,api): add menu.\n# comment\n" + scissorsLine + "\ndiff",
+		"\n\n\xff\x00:",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, msg string) {
+		lintMessage(msg)
+		fixed, _ := fixMessage(msg)
+		lintMessage(fixed)
+		message.ParseFooters(msg)
+	})
+}
+
+func TestMessageRuleIssues(t *testing.T) {
+	defer func(c config.LintConfig) { cfg.Lint = c }(cfg.Lint)
+	cfg.Lint.Forbidden = []string{"wip", "asdf"}
+	cfg.Lint.Patterns = []config.MessagePattern{
+		{Pattern: `(?i)\bfalcon\b`, Forbidden: true, Message: "no codenames"},
+		{Pattern: `(?m)^Signed-off-by: `},
+	}
+
+	tests := []struct {
+		message string
+		want    []string
+	}{
+		{"feat: add login\n\nSigned-off-by: A <a@example.com>", nil},
+		{"feat: WIP login\n\nSigned-off-by: A <a@example.com>", []string{"forbidden-word"}},
+		{"feat: add wiping\n\nSigned-off-by: A <a@example.com>", nil},
+		{"feat: ship Falcon", []string{"pattern-forbidden", "pattern-required"}},
+	}
+	for _, tt := range tests {
+		var rules []string
+		for _, issue := range messageRuleIssues(tt.message) {
+			rules = append(rules, issue.Rule)
+		}
+		if !slices.Equal(rules, tt.want) {
+			t.Errorf("messageRuleIssues(%q) = %v, want %v", tt.message, rules, tt.want)
+		}
+	}
+}
+
+func TestOptionalLintRules(t *testing.T) {
+	defer func(c config.LintConfig) { cfg.Lint = c }(cfg.Lint)
+	url := "See https://example.com/" + strings.Repeat("a", 100)
+	long := strings.Repeat("word ", 30)
+	message := "feat: Add paging\n\n" + url + "\n" + long + "\n\nRefs: " + strings.Repeat("PROJ-1 ", 20)
+
+	rules := func(issues []lintIssue) []string {
+		var names []string
+		for _, issue := range issues {
+			names = append(names, issue.Rule)
+		}
+		return names
+	}
+
+	cfg.Lint = config.LintConfig{}
+	if issues := lintMessage(message); len(issues) != 0 {
+		t.Errorf("default rules report %v", rules(issues))
+	}
+
+	cfg.Lint = config.LintConfig{SubjectCase: "lower", BodyMaxLineLength: 100}
+	issues := lintMessage(message)
+	if got := rules(issues); !slices.Equal(got, []string{"subject-case", "body-max-line-length"}) {
+		t.Fatalf("rules = %v, want subject-case and only the prose line", got)
+	}
+	if !strings.Contains(issues[1].Message, "4") {
+		t.Errorf("body-max-line-length names the wrong line: %s", issues[1].Message)
+	}
+
+	fixed, _ := fixMessage(message)
+	if issues := lintMessage(fixed); len(issues) != 0 {
+		t.Errorf("fixed message still reports %v:\n%s", rules(issues), fixed)
+	}
+	if !strings.Contains(fixed, "\n"+url+"\n") || !strings.HasSuffix(fixed, "Refs: "+strings.Repeat("PROJ-1 ", 20)) {
+		t.Errorf("the URL line or the footer was changed:\n%s", fixed)
+	}
+}
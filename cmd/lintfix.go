@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/barisdilekci/commitz/internal/message"
+)
+
+// subjectCaseLower is the lint.subject_case setting requiring a lowercase
+// subject.
+const subjectCaseLower = "lower"
+
+// bodyWrapWidth is where body lines are wrapped unless body.wrap says
+// otherwise.
+const bodyWrapWidth = 72
+
+// typeAliases maps common misspellings of types to the conventional type.
+var typeAliases = map[string]string{
+	"feature":       "feat",
+	"features":      "feat",
+	"bug":           "fix",
+	"bugfix":        "fix",
+	"hotfix":        "fix",
+	"doc":           "docs",
+	"documentation": "docs",
+	"tests":         "test",
+	"testing":       "test",
+	"refactoring":   "refactor",
+	"performance":   "perf",
+	"chores":        "chore",
+	"deps":          "build",
+}
+
+// checkSubjectCase reports whether lint.subject_case asks for lowercase
+// subjects.
+func checkSubjectCase() bool {
+	return strings.EqualFold(cfg.Lint.SubjectCase, subjectCaseLower)
+}
+
+// wrappableBody returns the body lines below the header that may be
+// wrapped: everything but a closing paragraph of footers, whose values must
+// stay on one line.
+func wrappableBody(lines []string) []string {
+	body := lines[1:]
+	if message.ParseFooters(strings.Join(lines, "\n")) == nil {
+		return body
+	}
+	for i := len(body) - 1; i >= 0; i-- {
+		if strings.TrimSpace(body[i]) == "" {
+			return body[:i]
+		}
+	}
+	return nil
+}
+
+// subjectStartsUpper reports whether the subject starts with a capital
+// letter that is not part of an acronym like "API".
+func subjectStartsUpper(subject string) bool {
+	first, _ := utf8.DecodeRuneInString(subject)
+	if !unicode.IsUpper(first) {
+		return false
+	}
+	word, _, _ := strings.Cut(subject, " ")
+	return strings.ToUpper(word) != word || utf8.RuneCountInString(word) == 1
+}
+
+// fixMessage corrects the violations lint knows how to fix and returns the
+// rules it fixed.
+func fixMessage(msg string) (string, []string) {
+	if exemptionMode(msg) != exemptStrict {
+		return msg, nil
+	}
+
+	var fixed []string
+	lines := strings.Split(msg, "\n")
+
+	m := message.HeaderPattern.FindStringSubmatchIndex(lines[0])
+	if m != nil {
+		header := lines[0]
+		prefix := header[:m[2]]
+		h, _ := message.ParseHeader(header)
+
+		if t := strings.ToLower(h.Type); !contains(commitTypeNames(), h.Type) {
+			if alias, ok := typeAliases[t]; ok {
+				h.Type = alias
+			} else if contains(commitTypeNames(), t) {
+				h.Type = t
+			}
+			if contains(commitTypeNames(), h.Type) {
+				fixed = append(fixed, "type-enum")
+			}
+		}
+		if scope := normalizeScope(h.Scope); scope != h.Scope {
+			h.Scope = scope
+			fixed = append(fixed, "scope-case")
+		}
+		if subject := strings.TrimRight(h.Subject, "."); subject != h.Subject {
+			h.Subject = subject
+			fixed = append(fixed, "subject-full-stop")
+		}
+		if checkSubjectCase() && subjectStartsUpper(h.Subject) {
+			first, size := utf8.DecodeRuneInString(h.Subject)
+			h.Subject = string(unicode.ToLower(first)) + h.Subject[size:]
+			fixed = append(fixed, "subject-case")
+		}
+
+		header = prefix + h.Type
+		if h.Scope != "" {
+			header += "(" + h.Scope + ")"
+		}
+		if h.Breaking {
+			header += "!"
+		}
+		lines[0] = header + ": " + h.Subject
+	}
+
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		lines = append([]string{lines[0], ""}, lines[1:]...)
+		fixed = append(fixed, "body-leading-blank")
+	}
+
+	if limit := cfg.Lint.BodyMaxLineLength; limit > 0 {
+		width := bodyWidth()
+		if width <= 0 {
+			width = bodyWrapWidth
+		}
+		prose := wrappableBody(lines)
+		if body, wrapped := message.WrapLines(prose, limit, min(width, limit)); wrapped {
+			rest := lines[1+len(prose):]
+			lines = append(append(lines[:1:1], body...), rest...)
+			fixed = append(fixed, "body-max-line-length")
+		}
+	}
+
+	return strings.Join(lines, "\n"), fixed
+}
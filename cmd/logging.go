@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/fatih/color"
+)
+
+var (
+	verbose bool
+	debug   bool
+	logFile string
+
+	// logger receives diagnostics; it discards everything unless --verbose
+	// or --debug is given.
+	logger = slog.New(slog.DiscardHandler)
+)
+
+// initLogging sets up the logger from --verbose, --debug and --log-file.
+// Verbose logs decisions, debug also logs every git command. Logs go to
+// stderr as text, or to the given file as JSON for bug reports.
+func initLogging() {
+	if !verbose && !debug && logFile == "" {
+		return
+	}
+
+	level := slog.LevelInfo
+	if debug {
+		level = slog.LevelDebug
+	}
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	if logFile != "" {
+		f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			color.Red("%s", tr("log.open_error", logFile, err))
+			os.Exit(1)
+		}
+		handler = slog.NewJSONHandler(f, opts)
+	} else {
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	}
+
+	logger = slog.New(handler)
+	logger.Debug("commitz starting", "args", os.Args[1:])
+}
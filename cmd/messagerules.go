@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/config"
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+)
+
+// forbiddenWordPattern matches word on its own, in any case. Unlike \b it
+// also works for words starting or ending with punctuation.
+func forbiddenWordPattern(word string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)(?:^|\W)` + regexp.QuoteMeta(word) + `(?:$|\W)`)
+}
+
+// messageRuleIssues checks a message against lint.forbidden and
+// lint.patterns.
+func messageRuleIssues(message string) []lintIssue {
+	var issues []lintIssue
+	for _, word := range cfg.Lint.Forbidden {
+		if word = strings.TrimSpace(word); word != "" && forbiddenWordPattern(word).MatchString(message) {
+			issues = append(issues, lintIssue{Rule: "forbidden-word", Message: tr("lint.forbidden_word", word)})
+		}
+	}
+
+	for _, rule := range cfg.Lint.Patterns {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			issues = append(issues, lintIssue{Rule: "pattern-invalid", Message: tr("lint.pattern_invalid", rule.Pattern, err)})
+			continue
+		}
+		matched := re.MatchString(message)
+		switch {
+		case rule.Forbidden && matched:
+			issues = append(issues, lintIssue{Rule: "pattern-forbidden", Message: ruleMessage(rule, tr("lint.pattern_forbidden", rule.Pattern))})
+		case !rule.Forbidden && !matched:
+			issues = append(issues, lintIssue{Rule: "pattern-required", Message: ruleMessage(rule, tr("lint.pattern_required", rule.Pattern))})
+		}
+	}
+	return issues
+}
+
+// ruleMessage prefers the explanation the config gives for a pattern.
+func ruleMessage(rule config.MessagePattern, fallback string) string {
+	if rule.Message != "" {
+		return rule.Message
+	}
+	return fallback
+}
+
+// enforceMessageRules keeps a message that breaks lint.forbidden or
+// lint.patterns from being committed. The header can be rewritten until
+// the message passes; without prompts the commit is refused.
+func enforceMessageRules(message string, interactive bool) string {
+	for {
+		issues := messageRuleIssues(message)
+		if len(issues) == 0 {
+			return message
+		}
+
+		fmt.Println()
+		displayLintIssues(tr("rules.title"), issues)
+		if assumeYes {
+			os.Exit(1)
+		}
+		fmt.Println(tr("rules.hint"))
+
+		header, rest, _ := strings.Cut(message, "\n")
+		header, ok := askHeader(header, interactive)
+		if !ok || header == "" {
+			color.Yellow("%s", tr("commit.cancelled"))
+			os.Exit(1)
+		}
+		if rest != "" {
+			header += "\n" + rest
+		}
+		message = header
+	}
+}
+
+func askHeader(header string, interactive bool) (string, bool) {
+	if interactive {
+		prompt := promptui.Prompt{
+			Label:   tr("rules.prompt"),
+			Default: header,
+		}
+		result, err := runPrompt(prompt)
+		return strings.TrimSpace(result), err == nil
+	}
+
+	fmt.Print(tr("rules.prompt_plain"))
+	answer, err := readLine()
+	return strings.TrimSpace(answer), err == nil
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+// httpClient is shared by the GitHub, issue tracker and update check
+// requests. Proxies come from HTTPS_PROXY, HTTP_PROXY and NO_PROXY, as for
+// other command line tools; rate limits and passing failures are retried.
+var httpClient = sync.OnceValues(newHTTPClient)
+
+func newHTTPClient() (*http.Client, error) {
+	if off, reason := offlineMode(); off {
+		return nil, errors.New(tr("offline.blocked", reason))
+	}
+	tlsConfig, err := networkTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig.InsecureSkipVerify {
+		color.Red("%s", tr("network.insecure"))
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: newRetryTransport(transport)}, nil
+}
+
+// networkTLSConfig trusts the certificates in network.ca_bundle on top of
+// the system ones, for proxies that re-sign traffic with a company CA.
+func networkTLSConfig() (*tls.Config, error) {
+	settings := cfg.Network
+	tlsConfig := &tls.Config{InsecureSkipVerify: settings.InsecureSkipVerify}
+	if settings.CABundle == "" {
+		return tlsConfig, nil
+	}
+
+	path := settings.CABundle
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.New(tr("network.ca_unreadable", path, err))
+	}
+
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+	if !roots.AppendCertsFromPEM(pem) {
+		return nil, errors.New(tr("network.ca_invalid", path))
+	}
+	tlsConfig.RootCAs = roots
+	return tlsConfig, nil
+}
+
+// checkNetwork reports the proxy used for GitHub and the TLS settings.
+func checkNetwork() doctorCheck {
+	if off, reason := offlineMode(); off {
+		return doctorCheck{"network", checkWarn, tr("doctor.offline", reason)}
+	}
+	if _, err := networkTLSConfig(); err != nil {
+		return doctorCheck{"network", checkFail, err.Error()}
+	}
+
+	proxy := tr("doctor.proxy_direct")
+	if req, err := http.NewRequest(http.MethodGet, latestReleaseURL, nil); err == nil {
+		if u, err := http.ProxyFromEnvironment(req); err == nil && u != nil {
+			proxy = tr("doctor.proxy", u.Redacted())
+		}
+	}
+
+	details := []string{proxy}
+	if cfg.Network.CABundle != "" {
+		details = append(details, tr("doctor.ca_bundle", cfg.Network.CABundle))
+	}
+	if cfg.Network.InsecureSkipVerify {
+		details = append(details, tr("doctor.insecure"))
+		return doctorCheck{"network", checkWarn, strings.Join(details, ", ")}
+	}
+	return doctorCheck{"network", checkOK, strings.Join(details, ", ")}
+}
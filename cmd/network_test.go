@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"encoding/pem"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/barisdilekci/commitz/internal/config"
+)
+
+// setOffline pins offline mode for a test, whatever the machine's network.
+func setOffline(t *testing.T, off bool) {
+	t.Helper()
+	saved := offlineMode
+	offlineMode = func() (bool, string) { return off, "--offline" }
+	t.Cleanup(func() { offlineMode = saved })
+}
+
+func TestNetworkCABundle(t *testing.T) {
+	setOffline(t, false)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	defer func(c config.NetworkConfig) { cfg.Network = c }(cfg.Network)
+	cfg.Network = config.NetworkConfig{}
+	client, err := newHTTPClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Get(server.URL); err == nil {
+		t.Fatal("self-signed certificate accepted without ca_bundle")
+	}
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}
+	if err := os.WriteFile(bundle, pem.EncodeToMemory(block), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Network.CABundle = bundle
+	client, err = newHTTPClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request with ca_bundle: %v", err)
+	}
+	resp.Body.Close()
+
+	cfg.Network.CABundle = filepath.Join(t.TempDir(), "missing.pem")
+	if _, err := newHTTPClient(); err == nil {
+		t.Error("missing ca_bundle accepted")
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	setOffline(t, false)
+	defer func(d time.Duration) { retryBaseDelay = d }(retryBaseDelay)
+	retryBaseDelay = time.Millisecond
+	defer func(c config.NetworkConfig) { cfg.Network = c }(cfg.Network)
+	cfg.Network = config.NetworkConfig{Retries: 2}
+
+	var hits int
+	code := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if hits < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(code)
+		}
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: newRetryTransport(http.DefaultTransport)}
+
+	resp, err := client.Get(server.URL)
+	if err != nil || resp.StatusCode != http.StatusOK || hits != 3 {
+		t.Fatalf("GET after two 503s: %v, %v, %d hits", resp.StatusCode, err, hits)
+	}
+	resp.Body.Close()
+
+	// A POST may have been applied despite the error, so it is not repeated
+	hits = 0
+	resp, err = client.Post(server.URL, "application/json", strings.NewReader("{}"))
+	if err != nil || resp.StatusCode != code || hits != 1 {
+		t.Fatalf("POST after a 503: %v, %v, %d hits", resp.StatusCode, err, hits)
+	}
+	resp.Body.Close()
+
+	// but a rate limit turned it away untouched
+	hits, code = 0, http.StatusTooManyRequests
+	resp, err = client.Post(server.URL, "application/json", strings.NewReader("{}"))
+	if err != nil || resp.StatusCode != http.StatusOK || hits != 3 {
+		t.Fatalf("POST after two 429s: %v, %v, %d hits", resp.StatusCode, err, hits)
+	}
+	resp.Body.Close()
+}
+
+func TestOffline(t *testing.T) {
+	defer func(f func() ([]net.Addr, error), flag bool) { interfaceAddrs, offlineFlag = f, flag }(interfaceAddrs, offlineFlag)
+	defer func(c config.Config) { cfg = c }(cfg)
+	cfg, offlineFlag = config.Config{}, false
+
+	addrs := []net.Addr{
+		&net.IPNet{IP: net.IPv4(127, 0, 0, 1), Mask: net.CIDRMask(8, 32)},
+		&net.IPNet{IP: net.ParseIP("fe80::1"), Mask: net.CIDRMask(64, 128)},
+	}
+	interfaceAddrs = func() ([]net.Addr, error) { return addrs, nil }
+	if off, reason := detectOffline(); !off || reason != tr("offline.reason_no_network") {
+		t.Errorf("detectOffline() with loopback only = %v, %q", off, reason)
+	}
+
+	addrs = append(addrs, &net.IPNet{IP: net.IPv4(10, 0, 0, 2), Mask: net.CIDRMask(24, 32)})
+	if off, _ := detectOffline(); off {
+		t.Error("detectOffline() with a LAN address = true")
+	}
+	offlineFlag = true
+	if off, reason := detectOffline(); !off || reason != "--offline" {
+		t.Errorf("detectOffline() with --offline = %v, %q", off, reason)
+	}
+
+	setOffline(t, true)
+	if _, err := newHTTPClient(); err == nil {
+		t.Error("newHTTPClient() works offline")
+	}
+}
+
+func TestRateLimitWait(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	tests := []struct {
+		header http.Header
+		want   time.Duration
+		ok     bool
+	}{
+		{http.Header{"Retry-After": {"7"}}, 7 * time.Second, true},
+		{http.Header{"Retry-After": {now.Add(time.Minute).UTC().Format(http.TimeFormat)}}, time.Minute, true},
+		{http.Header{"X-Ratelimit-Reset": {"1700000042"}}, 42 * time.Second, true},
+		{http.Header{"Ratelimit-Reset": {"1699999999"}}, 0, true},
+		{http.Header{}, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := rateLimitWait(tt.header, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("rateLimitWait(%v) = %v, %v; want %v, %v", tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRepoConfigUserOnlyKeys(t *testing.T) {
+	dir := t.TempDir()
+	user := filepath.Join(dir, "config.yaml")
+	repo := filepath.Join(dir, ".commitz.yaml")
+	if err := os.WriteFile(user, []byte("network:\n  ca_bundle: ~/ca.pem\nhosting:\n  url: https://git.example.com/team/app\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(repo, []byte("network:\n  insecure_skip_verify: true\n  retries: 1\nhosting:\n  url: https://evil.example.com/team/app\n  type: github\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var layered config.Config
+	if ignored, err := readConfigLayer(user, &layered); err != nil || len(ignored) > 0 {
+		t.Fatalf("user config: ignored %v, %v", ignored, err)
+	}
+	ignored, err := readConfigLayer(repo, &layered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(ignored, ",") != "network.insecure_skip_verify,hosting.url" {
+		t.Errorf("ignored = %v", ignored)
+	}
+	if layered.Network.InsecureSkipVerify || layered.Network.CABundle != "~/ca.pem" || layered.Hosting.URL != "https://git.example.com/team/app" {
+		t.Errorf("repository config changed user-only keys: %+v %+v", layered.Network, layered.Hosting)
+	}
+	// Other keys of the repository config still apply
+	if layered.Network.Retries != 1 || layered.Hosting.Type != "github" {
+		t.Errorf("repository config not applied: %+v %+v", layered.Network, layered.Hosting)
+	}
+
+	// --config names a file the user chose
+	saved := cfgFile
+	t.Cleanup(func() { cfgFile = saved })
+	cfgFile = repo
+	if ignored, err := readConfigLayer(repo, &layered); err != nil || len(ignored) > 0 || !layered.Network.InsecureSkipVerify {
+		t.Errorf("--config: ignored %v, %v, %+v", ignored, err, layered.Network)
+	}
+}
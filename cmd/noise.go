@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/detect"
+	"github.com/barisdilekci/commitz/internal/git"
+)
+
+// classifyFiles marks binary, lockfile, vendored and generated files.
+func classifyFiles(files []git.File) {
+	detect.Classify(files, linguistAttributes(files))
+	for _, f := range files {
+		if f.Kind != "" {
+			logger.Info("excluded from analysis", "path", f.Path, "kind", f.Kind)
+		}
+	}
+}
+
+// linguistAttributes reads linguist-generated/linguist-vendored markers from
+// .gitattributes for the given files.
+func linguistAttributes(files []git.File) map[string]string {
+	attrs := map[string]string{}
+	if len(files) == 0 {
+		return attrs
+	}
+
+	args := []string{"check-attr", "linguist-generated", "linguist-vendored", "--"}
+	for _, f := range files {
+		args = append(args, f.Path)
+	}
+
+	out, err := gitOutput(args...)
+	if err != nil {
+		return attrs
+	}
+
+	// Lines look like "path: linguist-generated: set"
+	for _, line := range strings.Split(string(out), "\n") {
+		parts := strings.Split(line, ": ")
+		if len(parts) != 3 || (parts[2] != "set" && parts[2] != "true") {
+			continue
+		}
+		attrs[parts[0]] = strings.TrimPrefix(parts[1], "linguist-")
+	}
+
+	return attrs
+}
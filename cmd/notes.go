@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// notesRef is where commit metadata is kept unless notes.ref is set.
+const notesRef = "commitz"
+
+// commitNote is the metadata recorded for a generated commit. Version is
+// bumped when fields change meaning.
+type commitNote struct {
+	Version          int      `json:"version"`
+	DetectedType     string   `json:"detected_type,omitempty"`
+	SelectedType     string   `json:"selected_type,omitempty"`
+	TypeAccepted     bool     `json:"type_accepted"`
+	ScopeCandidates  []string `json:"scope_candidates,omitempty"`
+	DetectedScope    string   `json:"detected_scope,omitempty"`
+	Scope            string   `json:"scope,omitempty"`
+	SuggestedSummary string   `json:"suggested_summary,omitempty"`
+	Summary          string   `json:"summary,omitempty"`
+	SummaryAccepted  bool     `json:"summary_accepted"`
+	Interactive      bool     `json:"interactive"`
+}
+
+// recordNote attaches the session's suggestions and choices to HEAD as a
+// git note when notes.enabled is set. Failures never affect the commit.
+func recordNote() {
+	if !cfg.Notes.Enabled || session.SelectedType == "" {
+		return
+	}
+
+	note := commitNote{
+		Version:          1,
+		DetectedType:     session.DetectedType,
+		SelectedType:     session.SelectedType,
+		TypeAccepted:     session.SelectedType == session.DetectedType,
+		ScopeCandidates:  session.ScopeCandidates,
+		DetectedScope:    session.DetectedScope,
+		Scope:            session.Scope,
+		SuggestedSummary: session.SuggestedSummary,
+		Summary:          session.Summary,
+		SummaryAccepted:  session.Summary == session.SuggestedSummary,
+		Interactive:      interactive,
+	}
+	data, err := json.Marshal(note)
+	if err != nil {
+		logger.Info("note not recorded", "error", err)
+		return
+	}
+
+	ref := strings.TrimPrefix(cfg.Notes.Ref, "refs/notes/")
+	if ref == "" {
+		ref = notesRef
+	}
+	if err := gitRun("notes", "--ref="+ref, "add", "-f", "-m", string(data), "HEAD"); err != nil {
+		logger.Info("note not recorded", "ref", ref, "error", err)
+	}
+}
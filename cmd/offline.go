@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+// offlineFlag is --offline.
+var offlineFlag bool
+
+// interfaceAddrs lists the machine's addresses; tests replace it.
+var interfaceAddrs = net.InterfaceAddrs
+
+// offlineMode reports whether network features are off and why: from
+// --offline, offline in the config, or because there is no network.
+var offlineMode = sync.OnceValues(detectOffline)
+
+// offlineSkipped remembers which features were already reported as
+// skipped in this run.
+var offlineSkipped = map[string]bool{}
+
+func detectOffline() (bool, string) {
+	switch {
+	case offlineFlag:
+		return true, "--offline"
+	case cfg.Offline:
+		return true, tr("offline.reason_config")
+	case !hasNetwork():
+		return true, tr("offline.reason_no_network")
+	}
+	return false, ""
+}
+
+// hasNetwork looks for an address other than loopback and link-local.
+// Nothing is sent, so the check itself is safe on an air-gapped machine;
+// a connected machine may still not reach a given host.
+func hasNetwork() bool {
+	addrs, err := interfaceAddrs()
+	if err != nil {
+		logger.Debug("network interfaces not readable", "error", err)
+		return true
+	}
+	for _, addr := range addrs {
+		ip, ok := addr.(*net.IPNet)
+		if ok && !ip.IP.IsLoopback() && !ip.IP.IsLinkLocalUnicast() {
+			return true
+		}
+	}
+	return false
+}
+
+// skipOffline reports whether a network feature must be skipped. The
+// first time a feature is skipped, it says which one and why.
+func skipOffline(feature string) bool {
+	off, reason := offlineMode()
+	if !off {
+		return false
+	}
+	if !offlineSkipped[feature] {
+		offlineSkipped[feature] = true
+		fmt.Fprintln(os.Stderr, color.YellowString("%s", tr("offline.skipped", reason, tr(feature))))
+	}
+	return true
+}
@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/detect"
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/barisdilekci/commitz/internal/message"
+	"github.com/barisdilekci/commitz/internal/summary"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// containerDirs hold one module per subdirectory, so the scope of a file
+// below them is the subdirectory name.
+var containerDirs = []string{"cmd", "pkg", "internal", "src", "lib", "apps", "packages", "services", "modules"}
+
+// zeroHash removes an entry when fed to git update-index --index-info.
+const zeroHash = "0000000000000000000000000000000000000000"
+
+var perScope bool
+
+var splitCmd = &cobra.Command{
+	Use:   "split",
+	Short: "Commit the staged files as one commit per scope",
+	Long: `Groups the staged files by the scope their paths suggest and creates one
+commit for each group, as commitz --per-scope does. Each commit contains
+exactly the staged state of its files. When all files share a scope, they
+are committed together as usual.`,
+	Example: `  commitz split --dry-run
+  commitz split -y`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		perScope = true
+		generateCommitMessage()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(splitCmd)
+}
+
+type scopeGroup struct {
+	Scope   string
+	Files   []git.File
+	Message string
+	// Private is the full message when redact keeps it out of history.
+	Private string
+}
+
+// fileScope infers the scope of a single file from its path.
+func fileScope(f git.File) string {
+	if f.Kind == detect.KindLockfile {
+		return "deps"
+	}
+
+	parts := strings.Split(filepath.ToSlash(f.Path), "/")
+	switch {
+	case len(parts) == 1:
+		return ""
+	case len(parts) > 2 && contains(containerDirs, parts[0]):
+		return normalizeScope(parts[1])
+	}
+	return normalizeScope(parts[0])
+}
+
+// groupByScope splits files into groups with the same inferred scope,
+// ordered by scope name with root files last.
+func groupByScope(files []git.File) []scopeGroup {
+	index := map[string]int{}
+	var groups []scopeGroup
+	for _, f := range files {
+		scope := fileScope(f)
+		i, ok := index[scope]
+		if !ok {
+			i = len(groups)
+			index[scope] = i
+			groups = append(groups, scopeGroup{Scope: scope})
+		}
+		groups[i].Files = append(groups[i].Files, f)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if (groups[i].Scope == "") != (groups[j].Scope == "") {
+			return groups[j].Scope == ""
+		}
+		return groups[i].Scope < groups[j].Scope
+	})
+	return groups
+}
+
+// suggestMessage generates a full message for files without any prompts.
+func suggestMessage(files []git.File, scope string) string {
+	analyzed := files
+	if kept := filterIgnoredFiles(files); len(kept) > 0 {
+		analyzed = kept
+	}
+	diff := detect.DiffText(analyzed)
+
+	selectedType, _, _ := explainCommitType(diff, analyzed)
+	if commitType != "" {
+		selectedType = commitType
+	}
+
+	emoji := getEmojiForType(selectedType)
+	subject := fitSummary(emoji, selectedType, scope, message.Imperative(applyLearnedVerb(resolveSummary(diff, analyzed, selectedType))), analyzed)
+	placed := messageEmoji(emoji)
+	msg := message.Build(message.Header{Type: selectedType, Scope: scope, Subject: subject}, placed)
+	if body := summary.Body(analyzed, selectedType, subject, gitOutput); body != "" {
+		msg = message.MarkBreaking(msg + "\n\n" + body)
+	}
+	return wrapBody(message.AddEmoji(msg, placed))
+}
+
+// commitPerScope creates one commit per scope group. It reports false when
+// all files share one scope, so the regular flow should be used. Each
+// message goes through the same rules as a single commit, and the staged
+// files through the same checks; scanned are the files for the content
+// checks.
+func commitPerScope(files, scanned []git.File) bool {
+	groups := groupByScope(files)
+	if len(groups) < 2 {
+		return false
+	}
+
+	redacted := false
+	for i := range groups {
+		message := suggestMessage(groups[i].Files, groups[i].Scope)
+		message = requireFooters(message, interactive)
+		message = requireTicket(message, interactive)
+		message = enforceMessageRules(message, interactive)
+		groups[i].Message, groups[i].Private = redactMessage(message)
+		redacted = redacted || groups[i].Private != ""
+	}
+
+	fmt.Println()
+	color.Green("%s", tr("perscope.plan", len(groups)))
+	for _, g := range groups {
+		subject, _, _ := strings.Cut(g.Message, "\n")
+		fmt.Printf("  %s\n", color.GreenString("%s", subject))
+		for _, f := range g.Files {
+			fmt.Printf("      %s\n", f.Path)
+		}
+	}
+	if redacted {
+		fmt.Println(tr("redact.redacted"))
+	}
+
+	displayContentWarnings(scanContentWarnings(scanned), interactive)
+	displayLargeFiles(findLargeFiles(scanned))
+	guardProtectedBranch(groups[0].Message, interactive)
+
+	if dryRun {
+		color.Yellow("%s", tr("message.dry_run"))
+		return true
+	}
+	if !runChecks() {
+		os.Exit(1)
+	}
+	if !confirmCommitInteractive(interactive) {
+		color.Yellow("%s", tr("commit.cancelled"))
+		return true
+	}
+
+	for i, g := range groups {
+		if err := commitGroup(g); err != nil {
+			color.Red("%s", tr("perscope.failed", i+1, len(groups), err))
+			fmt.Println(tr("perscope.failed_hint"))
+			os.Exit(1)
+		}
+		storePrivateMessage(g.Private)
+		if quiet {
+			printCommitResult()
+		}
+	}
+	if !quiet {
+		color.Green("%s", tr("perscope.done", len(groups)))
+	}
+	return true
+}
+
+// commitGroup commits exactly the staged state of the group's files. It
+// builds a temporary index from HEAD plus those entries, so the rest of the
+// real index, including partially staged files, stays untouched.
+func commitGroup(g scopeGroup) error {
+	tmp, err := os.CreateTemp("", "commitz-index-*")
+	if err != nil {
+		return err
+	}
+	tmp.Close()
+	os.Remove(tmp.Name())
+	defer os.Remove(tmp.Name())
+	env := append(os.Environ(), "GIT_INDEX_FILE="+tmp.Name())
+
+	base := []string{"read-tree", "HEAD"}
+	if gitRun("rev-parse", "--verify", "-q", "HEAD") != nil {
+		base = []string{"read-tree", "--empty"}
+	}
+	readTree := gitCommand(base...)
+	readTree.Env = env
+	if out, err := readTree.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, out)
+	}
+
+	var info strings.Builder
+	for _, f := range g.Files {
+		paths := []string{f.Path}
+		if f.OldPath != "" && f.OldPath != f.Path {
+			paths = append(paths, f.OldPath)
+		}
+		for _, path := range paths {
+			out, err := gitOutput("ls-files", "-s", "--", path)
+			if err != nil {
+				return err
+			}
+			if entry := strings.TrimSpace(string(out)); entry != "" {
+				// "mode hash stage\tpath" is accepted as is
+				info.WriteString(entry + "\n")
+			} else {
+				fmt.Fprintf(&info, "0 %s\t%s\n", zeroHash, path)
+			}
+		}
+	}
+
+	update := gitCommand("update-index", "--index-info")
+	update.Env = env
+	update.Stdin = strings.NewReader(info.String())
+	if out, err := update.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, out)
+	}
+
+	commit := gitCommand("commit", "-q", "-F", "-")
+	commit.Env = env
+	commit.Stdin = strings.NewReader(g.Message)
+	commit.Stdout = os.Stdout
+	commit.Stderr = os.Stderr
+	return commit.Run()
+}
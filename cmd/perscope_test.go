@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barisdilekci/commitz/internal/config"
+)
+
+func TestCommitPerScopeRules(t *testing.T) {
+	repo := testRepo(t,
+		[]string{"commit", "-q", "--allow-empty", "-m", "chore: init"},
+		[]string{"switch", "-q", "-c", "feature/PROJ-42-login"},
+	)
+	for _, name := range []string{"api/handler.go", "web/page.go"} {
+		path := filepath.Join(repo, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("package x\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gitRun("add", ".")
+
+	savedConfig, savedYes := cfg, assumeYes
+	t.Cleanup(func() { cfg, assumeYes = savedConfig, savedYes })
+	cfg.Branch.Patterns = []string{`^feature/(?P<ticket>[A-Z]+-\d+)-`}
+	cfg.Tickets.Required = []string{"feature/*"}
+	cfg.Redact = config.RedactConfig{Enabled: true}
+	assumeYes = true
+
+	files, err := loadStagedFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	classifyFiles(files)
+	if !commitPerScope(files, files) {
+		t.Fatal("commitPerScope() used the regular flow for two scopes")
+	}
+
+	// Each commit got the ticket, and only its header went into history
+	out, err := gitOutput("log", "--format=%s%n%b%N--", "--notes="+privateNotesRef, "-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	commits := strings.Split(strings.TrimSuffix(strings.TrimSpace(string(out)), "--"), "--")
+	if len(commits) != 2 {
+		t.Fatalf("got %d commits:\n%s", len(commits), out)
+	}
+	for _, c := range commits {
+		header, rest, _ := strings.Cut(strings.TrimSpace(c), "\n")
+		if strings.Contains(header, "PROJ-42") || !strings.Contains(rest, "Refs: PROJ-42") {
+			t.Errorf("commit %q: ticket not kept in the private note:\n%s", header, rest)
+		}
+	}
+}
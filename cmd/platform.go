@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+var (
+	gitPathOnce sync.Once
+	gitPath     string
+
+	// stdinReader is shared by all line-based prompts so buffered input is
+	// never lost between them.
+	stdinReader = bufio.NewReader(os.Stdin)
+)
+
+// gitExecutable resolves the git binary once, falling back to the default
+// Git for Windows install locations when git is not on PATH.
+func gitExecutable() string {
+	gitPathOnce.Do(func() {
+		if path, err := exec.LookPath("git"); err == nil {
+			gitPath = path
+			return
+		}
+
+		if runtime.GOOS == "windows" {
+			for _, dir := range []string{os.Getenv("ProgramFiles"), os.Getenv("ProgramFiles(x86)"), os.Getenv("LocalAppData") + `\Programs`} {
+				candidate := filepath.Join(dir, "Git", "cmd", "git.exe")
+				if _, err := os.Stat(candidate); dir != "" && err == nil {
+					gitPath = candidate
+					return
+				}
+			}
+		}
+
+		gitPath = "git"
+	})
+	return gitPath
+}
+
+// shellCommand runs a command line from the config through the platform
+// shell.
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	stopGently(cmd)
+	return cmd
+}
+
+// stopGently makes a canceled command get an interrupt, as Ctrl+C would
+// send it, so hooks and checks can clean up. It is killed when it is still
+// running after interruptGracePeriod. Windows cannot deliver the interrupt,
+// so there the command is killed right away.
+func stopGently(cmd *exec.Cmd) {
+	cmd.Cancel = func() error {
+		if runtime.GOOS == "windows" {
+			return cmd.Process.Kill()
+		}
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = interruptGracePeriod
+}
+
+// writeFileAtomic writes to a temporary file first so a crash mid-write
+// never leaves a truncated file behind.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// dataDir returns the directory for commitz's local data, following the
+// XDG base directory spec (~/.local/share/commitz) and LocalAppData on
+// Windows.
+func dataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "commitz"), nil
+	}
+	if dir := os.Getenv("LocalAppData"); runtime.GOOS == "windows" && dir != "" {
+		return filepath.Join(dir, "commitz"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "commitz"), nil
+}
+
+// readLine asks the prompter for a single line of input.
+func readLine() (string, error) {
+	return prompter.ReadLine()
+}
+
+// stdinLine reads a single line from stdin without the line ending, so
+// answers containing spaces and CRLF input both work.
+func stdinLine() (string, error) {
+	line, err := stdinReader.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+// emojiSupported reports whether the terminal can render emoji. The legacy
+// Windows console host cannot, while Windows Terminal, VS Code and ConEmu can.
+func emojiSupported() bool {
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	if runtime.GOOS != "windows" {
+		return true
+	}
+	return os.Getenv("WT_SESSION") != "" ||
+		os.Getenv("TERM_PROGRAM") == "vscode" ||
+		os.Getenv("ConEmuANSI") == "ON" ||
+		os.Getenv("TERM") != ""
+}
+
+// plainSymbols replaces emoji in UI text on terminals that cannot show them.
+var plainSymbols = strings.NewReplacer(
+	"✓", "OK",
+	"✗", "x",
+	"…", "...",
+	"⚠", "!",
+	"✎", ">",
+	"📦", "*",
+	"🔒", "!",
+	" 🎉", "",
+)
+
+// displayText degrades emoji for legacy consoles.
+func displayText(text string) string {
+	if emojiSupported() {
+		return text
+	}
+	return plainSymbols.Replace(text)
+}
@@ -0,0 +1,435 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/barisdilekci/commitz/internal/config"
+	"github.com/barisdilekci/commitz/internal/summary"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// pluginPrefix names plugin executables on PATH: commitz-jira is the
+// plugin "jira", as git finds git-lfs.
+const pluginPrefix = "commitz-"
+
+// pluginTimeout bounds each call to a plugin.
+const pluginTimeout = 5 * time.Second
+
+// pluginManifest is what "commitz-<name> manifest" prints as JSON.
+type pluginManifest struct {
+	Version string `json:"version"`
+	// Types are commit types added to the built-in ones.
+	Types []pluginType `json:"types"`
+	// Validators name the lint rules "validate" checks. The plugin gets
+	// the message on stdin and prints one "rule: problem" line per issue.
+	Validators []string `json:"validators"`
+	// Providers lists what else the plugin offers. "summary" makes it a
+	// summary generator that gets the diff on stdin, like summary.command.
+	Providers []string `json:"providers"`
+}
+
+type pluginType struct {
+	Type        string `json:"type"`
+	Emoji       string `json:"emoji"`
+	Description string `json:"description"`
+}
+
+// plugin is a discovered executable and what it says it contributes.
+type plugin struct {
+	Name     string
+	Path     string
+	Manifest pluginManifest
+}
+
+// activePlugins are the enabled plugins that were found and match their
+// pinned version.
+var activePlugins = sync.OnceValue(loadActivePlugins)
+
+var pluginsCmd = &cobra.Command{
+	Use:   "plugins",
+	Short: "Manage plugins that add commit types, validators and providers",
+	Long: `Plugins are executables named commitz-<name> on PATH. Enabled plugins
+are listed in the plugins setting of .commitz.yaml with the version they
+were enabled at, so everyone on the repository runs the same one; a plugin
+reporting another version is skipped until it is enabled again.`,
+	Example: `  commitz plugins list
+  commitz plugins enable jira
+  commitz plugins disable jira`,
+}
+
+var pluginsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show discovered and enabled plugins and what they contribute",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		found := discoverPlugins()
+		names := make([]string, 0, len(found))
+		for name := range found {
+			names = append(names, name)
+		}
+		for _, p := range cfg.Plugins {
+			if _, ok := found[p.Name]; !ok {
+				names = append(names, p.Name)
+			}
+		}
+		if len(names) == 0 {
+			fmt.Println(tr("plugins.none", pluginPrefix))
+			return
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			displayPlugin(name, found[name])
+		}
+	},
+}
+
+var pluginsEnableCmd = &cobra.Command{
+	Use:   "enable <name>",
+	Short: "Enable a plugin and pin its current version",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := strings.TrimPrefix(args[0], pluginPrefix)
+		path, ok := discoverPlugins()[name]
+		if !ok {
+			color.Red("%s", tr("plugins.not_found", pluginPrefix+name))
+			os.Exit(1)
+		}
+		manifest, err := readManifest(path)
+		if err != nil {
+			color.Red("%s", tr("plugins.manifest_error", name, err))
+			os.Exit(1)
+		}
+
+		entry := config.PluginConfig{Name: name, Version: manifest.Version}
+		configPath := updatePluginConfig(func(plugins []config.PluginConfig) []config.PluginConfig {
+			plugins = slices.DeleteFunc(plugins, func(p config.PluginConfig) bool { return p.Name == name })
+			return append(plugins, entry)
+		})
+		color.Green("%s", tr("plugins.enabled", name, pluginVersion(manifest.Version), configPath))
+	},
+}
+
+var pluginsDisableCmd = &cobra.Command{
+	Use:   "disable <name>",
+	Short: "Disable a plugin",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := strings.TrimPrefix(args[0], pluginPrefix)
+		var removed bool
+		configPath := updatePluginConfig(func(plugins []config.PluginConfig) []config.PluginConfig {
+			kept := slices.DeleteFunc(plugins, func(p config.PluginConfig) bool { return p.Name == name })
+			removed = len(kept) < len(plugins)
+			return kept
+		})
+		if !removed {
+			fmt.Println(tr("plugins.not_enabled", name, configPath))
+			return
+		}
+		color.Green("%s", tr("plugins.disabled", name, configPath))
+	},
+}
+
+func init() {
+	pluginsCmd.AddCommand(pluginsListCmd, pluginsEnableCmd, pluginsDisableCmd)
+	rootCmd.AddCommand(pluginsCmd)
+}
+
+// discoverPlugins finds the plugin executables on PATH. The first one of
+// a name wins, as for any command.
+func discoverPlugins() map[string]string {
+	found := map[string]string{}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name, ok := strings.CutPrefix(e.Name(), pluginPrefix)
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, ".exe")
+			}
+			if !ok || name == "" || e.IsDir() {
+				continue
+			}
+			if _, seen := found[name]; seen {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			if _, err := exec.LookPath(path); err == nil {
+				found[name] = path
+			}
+		}
+	}
+	return found
+}
+
+// runPlugin calls a plugin with input on stdin and returns what it printed.
+// env adds variables to the plugin's environment.
+func runPlugin(path, input string, env []string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(rootCtx, pluginTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, path, args...)
+	if root, err := repoRoot(); err == nil {
+		cmd.Dir = root
+	}
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Stderr = os.Stderr
+	logger.Debug("plugin", "path", path, "args", args)
+	return cmd.Output()
+}
+
+func readManifest(path string) (pluginManifest, error) {
+	var manifest pluginManifest
+	out, err := runPlugin(path, "", nil, "manifest")
+	if err != nil {
+		return manifest, err
+	}
+	err = json.Unmarshal(out, &manifest)
+	return manifest, err
+}
+
+func loadActivePlugins() []plugin {
+	if len(cfg.Plugins) == 0 {
+		return nil
+	}
+	found := discoverPlugins()
+
+	var active []plugin
+	for _, p := range cfg.Plugins {
+		path, ok := found[p.Name]
+		if !ok {
+			color.Yellow("%s", tr("plugins.missing", p.Name, pluginPrefix+p.Name))
+			continue
+		}
+		manifest, err := readManifest(path)
+		switch {
+		case err != nil:
+			color.Yellow("%s", tr("plugins.manifest_error", p.Name, err))
+			continue
+		case p.Version != "" && manifest.Version != p.Version:
+			color.Yellow("%s", tr("plugins.version_mismatch", p.Name, pluginVersion(manifest.Version), p.Version))
+			continue
+		}
+		logger.Info("plugin loaded", "plugin", p.Name, "version", manifest.Version)
+		active = append(active, plugin{Name: p.Name, Path: path, Manifest: manifest})
+	}
+	return active
+}
+
+// pluginCommitTypes are the types the active plugins add.
+func pluginCommitTypes() []CommitType {
+	var types []CommitType
+	for _, p := range activePlugins() {
+		for _, t := range p.Manifest.Types {
+			if t.Type != "" {
+				types = append(types, CommitType{t.Type, t.Emoji, t.Description})
+			}
+		}
+	}
+	return types
+}
+
+// pluginIssues runs the validators of the active plugins on a message.
+func pluginIssues(message string) []lintIssue {
+	var issues []lintIssue
+	for _, p := range activePlugins() {
+		if len(p.Manifest.Validators) == 0 {
+			continue
+		}
+		out, err := runPlugin(p.Path, message, nil, "validate")
+		var found []lintIssue
+		for _, line := range strings.Split(string(out), "\n") {
+			if line = strings.TrimSpace(line); line == "" {
+				continue
+			}
+			rule, problem, ok := strings.Cut(line, ": ")
+			if !ok {
+				rule, problem = "validate", line
+			}
+			found = append(found, lintIssue{Rule: p.Name + "/" + rule, Message: problem})
+		}
+		// Validators may exit non-zero when they report issues
+		if err != nil && len(found) == 0 {
+			found = append(found, lintIssue{Rule: p.Name + "/error", Message: tr("plugins.validate_error", p.Name, err)})
+		}
+		issues = append(issues, found...)
+	}
+	return issues
+}
+
+// pluginSummaryGenerator returns the active plugin of that name when it
+// provides summaries.
+func pluginSummaryGenerator(name string) (summary.Generator, bool) {
+	for _, p := range activePlugins() {
+		if strings.EqualFold(p.Name, name) && contains(p.Manifest.Providers, "summary") {
+			return pluginGenerator{p}, true
+		}
+	}
+	return nil, false
+}
+
+// pluginGenerator asks a plugin for the summary, with the staged diff on
+// stdin and the type in COMMITZ_TYPE.
+type pluginGenerator struct {
+	plugin plugin
+}
+
+func (g pluginGenerator) Name() string { return g.plugin.Name }
+
+func (g pluginGenerator) Summary(req summary.Request) (string, error) {
+	out, err := runPlugin(g.plugin.Path, req.Diff, []string{"COMMITZ_TYPE=" + req.Type}, "summary")
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", g.plugin.Name, err)
+	}
+	line, _, _ := strings.Cut(string(out), "\n")
+	return strings.TrimSpace(line), nil
+}
+
+// displayPlugin prints one plugin of plugins list.
+func displayPlugin(name, path string) {
+	var pinned *config.PluginConfig
+	for i, p := range cfg.Plugins {
+		if p.Name == name {
+			pinned = &cfg.Plugins[i]
+		}
+	}
+
+	status := color.New(color.Faint).Sprint(tr("plugins.available"))
+	if pinned != nil {
+		status = color.GreenString("%s", tr("plugins.status_enabled", pluginVersion(pinned.Version)))
+	}
+	if path == "" {
+		fmt.Printf("%s  %s\n", color.CyanString("%s", name), color.RedString("%s", tr("plugins.status_missing")))
+		return
+	}
+
+	manifest, err := readManifest(path)
+	if err != nil {
+		fmt.Printf("%s  %s\n", color.CyanString("%s", name), color.RedString("%s", tr("plugins.manifest_error", name, err)))
+		return
+	}
+	if pinned != nil && pinned.Version != "" && pinned.Version != manifest.Version {
+		status = color.YellowString("%s", tr("plugins.status_mismatch", pinned.Version))
+	}
+	fmt.Printf("%s %s  %s\n", color.CyanString("%s", name), pluginVersion(manifest.Version), status)
+	fmt.Printf("  %s\n", color.New(color.Faint).Sprint(path))
+
+	var types []string
+	for _, t := range manifest.Types {
+		types = append(types, t.Type)
+	}
+	for _, part := range []struct {
+		key   string
+		items []string
+	}{
+		{"plugins.types", types},
+		{"plugins.validators", manifest.Validators},
+		{"plugins.providers", manifest.Providers},
+	} {
+		if len(part.items) > 0 {
+			fmt.Printf("  %s %s\n", tr(part.key), strings.Join(part.items, ", "))
+		}
+	}
+}
+
+func pluginVersion(version string) string {
+	if version == "" {
+		return tr("plugins.unversioned")
+	}
+	return version
+}
+
+// updatePluginConfig changes the plugins setting of the repository config,
+// or of --config, and returns the file it wrote.
+func updatePluginConfig(change func([]config.PluginConfig) []config.PluginConfig) string {
+	path := cfgFile
+	if path == "" {
+		root, err := repoRoot()
+		if err != nil {
+			color.Red("%s", tr("repo.not_found"))
+			os.Exit(1)
+		}
+		path = filepath.Join(root, ".commitz.yaml")
+		if _, err := os.Stat(filepath.Join(root, ".commitz.yml")); err == nil {
+			path = filepath.Join(root, ".commitz.yml")
+		}
+	}
+
+	var current config.Config
+	if err := config.Read(path, &current); err != nil && !errors.Is(err, os.ErrNotExist) {
+		color.Red("%s", tr("config.read_error", path, err))
+		os.Exit(1)
+	}
+	if err := writePluginConfig(path, change(current.Plugins)); err != nil {
+		color.Red("%s", tr("plugins.write_error", path, err))
+		os.Exit(1)
+	}
+	return path
+}
+
+// writePluginConfig replaces the plugins setting of a config file, keeping
+// the rest of it, comments included.
+func writePluginConfig(path string, plugins []config.PluginConfig) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return errors.New(tr("plugins.not_mapping"))
+	}
+
+	var value yaml.Node
+	if err := value.Encode(plugins); err != nil {
+		return err
+	}
+	// A mapping's content alternates keys and values
+	i := 0
+	for i < len(root.Content) && root.Content[i].Value != "plugins" {
+		i += 2
+	}
+	switch {
+	case i < len(root.Content) && len(plugins) == 0:
+		root.Content = slices.Delete(root.Content, i, i+2)
+	case i < len(root.Content):
+		root.Content[i+1] = &value
+	case len(plugins) > 0:
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "plugins"}, &value)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, buf.Bytes())
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/barisdilekci/commitz/internal/config"
+)
+
+func TestWritePluginConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".commitz.yaml")
+	if err := os.WriteFile(path, []byte("# team settings\nbody:\n  wrap: 80 # columns\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writePluginConfig(path, []config.PluginConfig{{Name: "jira", Version: "1.2.0"}}); err != nil {
+		t.Fatal(err)
+	}
+	var layered config.Config
+	if err := config.Read(path, &layered); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if len(layered.Plugins) != 1 || layered.Plugins[0].Version != "1.2.0" || layered.Body.Wrap != 80 || !strings.Contains(string(data), "# columns") {
+		t.Errorf("after enable:\n%s", data)
+	}
+
+	if err := writePluginConfig(path, nil); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	if strings.Contains(string(data), "plugins") || !strings.Contains(string(data), "# team settings") {
+		t.Errorf("after disable:\n%s", data)
+	}
+}
+
+func TestActivePlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin fixture is a shell script")
+	}
+	dir := t.TempDir()
+	script := `#!/bin/sh
+case "$1" in
+manifest) echo '{"version": "1.2.0", "types": [{"type": "deps", "emoji": "📦"}], "validators": ["no-todo"]}' ;;
+validate) grep -q TODO && echo "no-todo: mentions TODO" && exit 1 ;;
+esac
+exit 0
+`
+	if err := os.WriteFile(filepath.Join(dir, "commitz-demo"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Chdir(dir)
+	resetRepoCaches()
+
+	defer func(c config.Config) { cfg, activePlugins = c, sync.OnceValue(loadActivePlugins) }(cfg)
+	cfg = config.Config{Plugins: []config.PluginConfig{{Name: "demo", Version: "1.2.0"}}}
+	activePlugins = sync.OnceValue(loadActivePlugins)
+
+	if !contains(commitTypeNames(), "deps") {
+		t.Errorf("types = %v, want the plugin's deps", commitTypeNames())
+	}
+	issues := pluginIssues("deps: bump TODO")
+	if len(issues) != 1 || issues[0].Rule != "demo/no-todo" {
+		t.Errorf("pluginIssues() = %+v", issues)
+	}
+	if issues := pluginIssues("deps: bump go"); len(issues) != 0 {
+		t.Errorf("pluginIssues() for a clean message = %+v", issues)
+	}
+
+	cfg.Plugins[0].Version = "1.1.0"
+	activePlugins = sync.OnceValue(loadActivePlugins)
+	if got := activePlugins(); len(got) != 0 {
+		t.Errorf("plugin with another version than pinned was loaded: %+v", got)
+	}
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"errors"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/barisdilekci/commitz/internal/config"
+)
+
+// presetFiles are the built-in conventions. The first comment line of each
+// file describes it in the gallery.
+//
+//go:embed presets/*.yaml
+var presetFiles embed.FS
+
+// typeAlternatives are the types a detected type falls back to, in order,
+// when types leaves it out.
+var typeAlternatives = map[string][]string{
+	"chore":    {"refactor", "build"},
+	"build":    {"chore", "ci"},
+	"ci":       {"build", "chore"},
+	"style":    {"refactor", "chore"},
+	"perf":     {"refactor", "fix"},
+	"refactor": {"chore"},
+	"test":     {"chore"},
+	"docs":     {"chore"},
+}
+
+// presetNames lists the built-in presets alphabetically.
+func presetNames() []string {
+	entries, err := presetFiles.ReadDir("presets")
+	if err != nil {
+		panic(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), path.Ext(e.Name())))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func presetData(name string) ([]byte, error) {
+	data, err := presetFiles.ReadFile("presets/" + strings.ToLower(name) + ".yaml")
+	if err != nil {
+		return nil, errors.New(tr("preset.unknown", name, strings.Join(presetNames(), ", ")))
+	}
+	return data, nil
+}
+
+// presetDescription is the first comment line of a preset.
+func presetDescription(name string) string {
+	data, err := presetData(name)
+	if err != nil {
+		return ""
+	}
+	line, _ := bufio.NewReader(bytes.NewReader(data)).ReadString('\n')
+	return strings.TrimSpace(strings.TrimPrefix(line, "#"))
+}
+
+// applyPreset puts the config files that were read over the preset they
+// name, so any setting in them wins over the preset's.
+func applyPreset(paths []string) error {
+	if cfg.Preset == "" {
+		return nil
+	}
+	data, err := presetData(cfg.Preset)
+	if err != nil {
+		return err
+	}
+
+	var merged config.Config
+	if err := yaml.Unmarshal(data, &merged); err != nil {
+		return err
+	}
+	for _, p := range paths {
+		if _, err := readConfigLayer(p, &merged); err != nil {
+			return err
+		}
+	}
+	cfg = merged
+	logger.Info("preset applied", "preset", cfg.Preset)
+	return nil
+}
+
+// allowedType returns a type the types setting allows in place of t.
+func allowedType(t string) string {
+	allowed := commitTypeNames()
+	if contains(allowed, t) || len(allowed) == 0 {
+		return t
+	}
+	for _, alternative := range typeAlternatives[t] {
+		if contains(allowed, alternative) {
+			return alternative
+		}
+	}
+	return allowed[0]
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/barisdilekci/commitz/internal/config"
+)
+
+func TestPresetsAreValid(t *testing.T) {
+	for _, want := range []string{"angular", "gitmoji", "jira-first", "karma", "minimal"} {
+		if !slices.Contains(presetNames(), want) {
+			t.Errorf("preset %s is missing", want)
+		}
+	}
+
+	for _, name := range presetNames() {
+		data, err := presetData(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var layered config.Config
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&layered); err != nil {
+			t.Errorf("preset %s: %v", name, err)
+		}
+		for _, typ := range layered.Types {
+			if !slices.ContainsFunc(commitTypes, func(ct CommitType) bool { return ct.Type == typ }) {
+				t.Errorf("preset %s: unknown type %q", name, typ)
+			}
+		}
+		if presetDescription(name) == "" {
+			t.Errorf("preset %s has no description", name)
+		}
+	}
+}
+
+func TestApplyPreset(t *testing.T) {
+	defer func(c config.Config) { cfg = c }(cfg)
+	path := filepath.Join(t.TempDir(), ".commitz.yaml")
+	if err := os.WriteFile(path, []byte("preset: angular\nbody:\n  wrap: 80\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg = config.Config{}
+	if err := config.Read(path, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyPreset([]string{path}); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Body.Wrap != 80 || cfg.Scopes.Case != "kebab" || contains(commitTypeNames(), "chore") {
+		t.Errorf("config = %+v, want the angular preset with wrap 80", cfg)
+	}
+	if got := allowedType("chore"); got != "refactor" {
+		t.Errorf("allowedType(chore) = %q, want refactor", got)
+	}
+
+	cfg.Preset = "nope"
+	if err := applyPreset(nil); err == nil {
+		t.Error("unknown preset accepted")
+	}
+}
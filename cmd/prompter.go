@@ -0,0 +1,24 @@
+package cmd
+
+import "github.com/manifoldco/promptui"
+
+// Prompter is how commitz asks the user anything: picking from a list,
+// entering text or answering a plain line prompt. Tests replace it with
+// a scripted one to drive the interactive flow without a terminal.
+type Prompter interface {
+	Select(prompt promptui.Select) (int, string, error)
+	Prompt(prompt promptui.Prompt) (string, error)
+	ReadLine() (string, error)
+}
+
+// prompter is the Prompter all prompts go through.
+var prompter Prompter = terminalPrompter{}
+
+// terminalPrompter prompts on the terminal with promptui, or with plain
+// numbered prompts in accessible mode.
+type terminalPrompter struct{}
+
+// ReadLine reads a line from stdin.
+func (terminalPrompter) ReadLine() (string, error) {
+	return stdinLine()
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/barisdilekci/commitz/internal/ui"
+	"github.com/manifoldco/promptui"
+)
+
+// promptStep is one scripted answer. Label must appear in the prompt's
+// label; for plain line prompts, which have none, it is left empty.
+type promptStep struct {
+	Label  string
+	Answer string
+}
+
+// scriptedPrompter answers prompts from a script and fails the test when
+// a prompt does not match the next step or the script runs out.
+type scriptedPrompter struct {
+	t     *testing.T
+	steps []promptStep
+}
+
+// useScript makes the steps answer every prompt until the test ends.
+func useScript(t *testing.T, steps ...promptStep) *scriptedPrompter {
+	t.Helper()
+	script := &scriptedPrompter{t: t, steps: steps}
+	previous := prompter
+	prompter = script
+	t.Cleanup(func() { prompter = previous })
+	return script
+}
+
+// next returns the answer to the prompt with the given label.
+func (s *scriptedPrompter) next(kind, label string) (string, bool) {
+	s.t.Helper()
+	if len(s.steps) == 0 {
+		s.t.Errorf("unexpected %s prompt %q after the script ended", kind, label)
+		return "", false
+	}
+	step := s.steps[0]
+	s.steps = s.steps[1:]
+	if !strings.Contains(label, step.Label) {
+		s.t.Errorf("got %s prompt %q, script expected %q", kind, label, step.Label)
+	}
+	return step.Answer, true
+}
+
+// Select picks the first item starting with the answer, or the
+// preselected item for an empty answer.
+func (s *scriptedPrompter) Select(prompt promptui.Select) (int, string, error) {
+	answer, ok := s.next("select", ui.PlainLabel(prompt.Label))
+	if !ok {
+		return 0, "", promptui.ErrEOF
+	}
+	items := reflect.ValueOf(prompt.Items)
+	labels := make([]string, items.Len())
+	for i := range labels {
+		labels[i] = plainItem(items.Index(i).Interface())
+		if answer == "" && i == prompt.CursorPos || answer != "" && strings.HasPrefix(labels[i], answer) {
+			return i, labels[i], nil
+		}
+	}
+	s.t.Errorf("no item of %q starts with %q: %q", ui.PlainLabel(prompt.Label), answer, labels)
+	return 0, "", promptui.ErrEOF
+}
+
+// Prompt enters the answer, or confirms when it is a yes.
+func (s *scriptedPrompter) Prompt(prompt promptui.Prompt) (string, error) {
+	answer, ok := s.next("text", ui.PlainLabel(prompt.Label))
+	if !ok {
+		return "", promptui.ErrEOF
+	}
+	if prompt.IsConfirm && !isYes(answer) {
+		return answer, promptui.ErrAbort
+	}
+	if answer == "" {
+		answer = prompt.Default
+	}
+	if prompt.Validate != nil {
+		if err := prompt.Validate(answer); err != nil {
+			s.t.Errorf("answer %q to %q is invalid: %v", answer, ui.PlainLabel(prompt.Label), err)
+		}
+	}
+	return answer, nil
+}
+
+// ReadLine returns the answer to a plain line prompt.
+func (s *scriptedPrompter) ReadLine() (string, error) {
+	answer, ok := s.next("line", "")
+	if !ok {
+		return "", io.EOF
+	}
+	return answer, nil
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/message"
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+)
+
+// Protected branch modes.
+const (
+	protectedWarn  = "warn"
+	protectedBlock = "block"
+)
+
+// maxBranchSlug bounds the summary part of generated branch names.
+const maxBranchSlug = 40
+
+// protectedBranch reports whether branch matches protected.branches.
+func protectedBranch(branch string) bool {
+	for _, pattern := range cfg.Protected.Branches {
+		if ok, _ := path.Match(pattern, branch); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// guardProtectedBranch warns about, or in block mode refuses, committing
+// directly to a protected branch and offers to move the staged changes to
+// a new branch named after the message.
+func guardProtectedBranch(message string, interactive bool) {
+	branch, err := currentBranch()
+	if err != nil || branch == "" || !protectedBranch(branch) {
+		return
+	}
+
+	block := strings.ToLower(cfg.Protected.Mode) == protectedBlock
+	fmt.Println()
+	if block {
+		color.Red("%s", tr("protected.blocked", branch))
+	} else {
+		color.Yellow("%s", tr("protected.warning", branch))
+	}
+	if dryRun {
+		return
+	}
+
+	name := branchNameFor(message)
+	if assumeYes {
+		if block {
+			os.Exit(1)
+		}
+		return
+	}
+
+	created := false
+	if name, ok := askBranchName(name, interactive); ok {
+		if err := gitRun("switch", "-c", name); err != nil {
+			color.Red("%s", tr("protected.switch_failed", name, err))
+		} else {
+			color.Green("%s", tr("protected.switched", name))
+			created = true
+		}
+	}
+
+	if block && !created {
+		os.Exit(1)
+	}
+}
+
+func askBranchName(name string, interactive bool) (string, bool) {
+	if interactive {
+		prompt := promptui.Prompt{
+			Label:   tr("protected.branch_prompt"),
+			Default: name,
+		}
+		result, err := runPrompt(prompt)
+		result = strings.TrimSpace(result)
+		return result, err == nil && result != ""
+	}
+
+	fmt.Print(tr("protected.branch_prompt_plain", name))
+	answer, _ := readLine()
+	return name, isYes(answer) || strings.TrimSpace(answer) == ""
+}
+
+// branchNameFor builds a branch name like "feat/add-rate-limiting" from the
+// message header.
+func branchNameFor(msg string) string {
+	header, _, _ := strings.Cut(msg, "\n")
+	h, ok := message.ParseHeader(header)
+	if !ok {
+		return "commitz/" + slugify(header)
+	}
+	return h.Type + "/" + slugify(h.Subject)
+}
+
+func slugify(text string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(text) {
+		if ('a' <= r && r <= 'z') || ('0' <= r && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	slug := strings.TrimSuffix(b.String(), "-")
+	if len(slug) > maxBranchSlug {
+		slug = slug[:maxBranchSlug]
+		if i := strings.LastIndexByte(slug, '-'); i > 0 {
+			slug = slug[:i]
+		}
+	}
+	if slug == "" {
+		return "change"
+	}
+	return slug
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// resultOutput receives the only output --quiet keeps on stdout: the
+// hash and subject of each new commit, or the message in a dry run.
+var resultOutput io.Writer = os.Stdout
+
+// initQuiet turns off colors for --quiet. Commands that commit also call
+// quietOutput.
+func initQuiet() {
+	if !quiet {
+		return
+	}
+	color.NoColor = true
+	// Nobody is there to answer prompts
+	assumeYes = true
+}
+
+// quietOutput moves everything but the result to stderr, so scripts and
+// editors capturing stdout get only the result. Warnings and errors stay
+// visible on the terminal.
+func quietOutput() {
+	if !quiet {
+		return
+	}
+	resultOutput = os.Stdout
+	os.Stdout = os.Stderr
+	color.Output = os.Stderr
+}
+
+// printCommitResult prints the short hash and subject of HEAD for
+// --quiet.
+func printCommitResult() {
+	out, err := gitOutput("log", "-1", "--format=%h %s")
+	if err != nil {
+		color.Red("%s", tr("commit.result_error", err))
+		return
+	}
+	fmt.Fprintln(resultOutput, strings.TrimSpace(string(out)))
+}
@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/detect"
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+)
+
+// rebaseDir holds the rewritten messages in the git directory until the
+// rebase has used them.
+const rebaseDir = "commitz-rebase"
+
+var rebaseCmd = &cobra.Command{
+	Use:   "rebase-msgs [upstream]",
+	Short: "Fix commit messages before rebasing a branch",
+	Long: `Lists the commits that git rebase -i would replay onto upstream (by
+default the branch's upstream), flags the messages that do not pass
+commitz lint and lets you rewrite each one, either with the automatic
+corrections of lint --fix or with commitz's prompts based on the commit's
+diff. The rebase then runs with a prepared todo list that applies the new
+messages.
+
+With --dry-run the todo list is only printed. With --yes only automatic
+corrections are applied.`,
+	Example: `  commitz rebase-msgs
+  commitz rebase-msgs origin/main -i`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ensureRepository(interactive)
+
+		upstream := "@{upstream}"
+		if len(args) == 1 {
+			upstream = args[0]
+		}
+		if err := gitRun("rev-parse", "--verify", "-q", upstream); err != nil {
+			color.Red("%s", tr("rebase.no_upstream", upstream))
+			os.Exit(1)
+		}
+		if out, _ := gitOutput("rev-list", "--merges", upstream+"..HEAD"); len(out) > 0 {
+			color.Red("%s", tr("rebase.merges"))
+			os.Exit(1)
+		}
+
+		commits, err := rangeCommits(upstream + "..HEAD")
+		if err != nil {
+			color.Red("%s", tr("lint.range_error", upstream+"..HEAD", err))
+			os.Exit(1)
+		}
+		if len(commits) == 0 {
+			fmt.Println(tr("rebase.nothing", upstream))
+			return
+		}
+
+		rewritten := rewriteMessages(commits)
+		if len(rewritten) == 0 {
+			color.Green("%s", tr("rebase.all_ok", len(commits)))
+			return
+		}
+
+		dir, err := prepareRebaseDir()
+		if err != nil {
+			color.Red("%s", tr("rebase.prepare_failed", err))
+			os.Exit(1)
+		}
+		todo, err := rebaseTodo(commits, rewritten, dir)
+		if err != nil {
+			color.Red("%s", tr("rebase.prepare_failed", err))
+			os.Exit(1)
+		}
+
+		fmt.Println()
+		fmt.Println(tr("rebase.todo"))
+		fmt.Print(todo)
+		if dryRun {
+			color.Yellow("%s", tr("message.dry_run"))
+			os.RemoveAll(dir)
+			return
+		}
+		if !confirmRebase(len(rewritten)) {
+			os.RemoveAll(dir)
+			color.Yellow("%s", tr("rebase.cancelled"))
+			return
+		}
+
+		todoPath := filepath.Join(dir, "todo")
+		if err := os.WriteFile(todoPath, []byte(todo), 0o644); err != nil {
+			color.Red("%s", tr("rebase.prepare_failed", err))
+			os.Exit(1)
+		}
+		if err := runRebase(upstream, todoPath); err != nil {
+			// The messages are still needed when the rebase continues
+			color.Red("%s", tr("rebase.stopped", err))
+			os.Exit(1)
+		}
+		os.RemoveAll(dir)
+		color.Green("%s", tr("rebase.done", len(rewritten)))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rebaseCmd)
+
+	addDiffFlags(rebaseCmd)
+}
+
+// rewriteMessages lints each commit and asks for a new message where it
+// fails. It returns the new messages by commit hash.
+func rewriteMessages(commits []rangeCommit) map[string]string {
+	rewritten := map[string]string{}
+	for _, c := range commits {
+		subject, _, _ := strings.Cut(c.Message, "\n")
+		label := fmt.Sprintf("%s %s", c.Hash[:min(7, len(c.Hash))], subject)
+
+		issues := lintMessage(c.Message)
+		displayLintIssues(label, issues)
+		if len(issues) == 0 {
+			continue
+		}
+
+		if fixed, changes := fixMessage(c.Message); len(changes) > 0 && len(lintMessage(fixed)) == 0 {
+			displaySuggestedMessage(fixed)
+			if confirmRewrite("rebase.use_fixed") {
+				rewritten[c.Hash] = fixed
+				continue
+			}
+		}
+		if assumeYes || !confirmRewrite("rebase.compose") {
+			continue
+		}
+		if message, ok := composeForCommit(c.Hash); ok {
+			rewritten[c.Hash] = message
+		}
+	}
+	return rewritten
+}
+
+// composeForCommit runs the usual suggestion and prompts on the diff of an
+// existing commit.
+func composeForCommit(hash string) (string, bool) {
+	var files []git.File
+	err := gitStream(func(r io.Reader) error {
+		var err error
+		files, err = git.ParseDiffReader(r, diffCapture.gitOptions())
+		return err
+	}, diffCapture.args("show", "--format=", hash)...)
+	if err == nil && diffCapture.IgnoreAllSpace {
+		files, err = addWhitespaceOnlyFiles(files, "show", "--format=", hash)
+	}
+	if err == nil && !detect.OnlyWhitespace(files) {
+		markWhitespaceOnly(files, hash+"^", hash)
+	}
+	if err != nil || len(files) == 0 {
+		color.Yellow("%s", tr("rebase.no_diff", hash[:min(7, len(hash))]))
+		return "", false
+	}
+	classifyFiles(files)
+	if len(cfg.Analysis.Ignore) > 0 {
+		files = filterIgnoredFiles(files)
+	}
+
+	// composeMessage keeps a draft for the staged changes; leave any real
+	// draft as it was
+	previous, hadDraft := loadDraft()
+	message := composeMessage(detect.DiffText(files), files)
+	if hadDraft {
+		saveDraft(previous.Message)
+	} else {
+		clearDraft()
+	}
+	return message, true
+}
+
+func confirmRewrite(key string) bool {
+	if assumeYes {
+		return key == "rebase.use_fixed"
+	}
+	if interactive {
+		prompt := promptui.Prompt{
+			Label:     tr(key),
+			IsConfirm: true,
+		}
+		result, err := runPrompt(prompt)
+		return err == nil && isYes(result)
+	}
+
+	fmt.Print(tr(key + "_plain"))
+	answer, _ := readLine()
+	return isYes(answer) || strings.TrimSpace(answer) == ""
+}
+
+func confirmRebase(count int) bool {
+	if assumeYes {
+		return true
+	}
+	if interactive {
+		prompt := promptui.Prompt{
+			Label:     tr("rebase.confirm", count),
+			IsConfirm: true,
+		}
+		result, err := runPrompt(prompt)
+		return err == nil && isYes(result)
+	}
+
+	fmt.Print(tr("rebase.confirm_plain", count))
+	answer, _ := readLine()
+	return isYes(answer) || strings.TrimSpace(answer) == ""
+}
+
+// prepareRebaseDir empties the directory left by an earlier run.
+func prepareRebaseDir() (string, error) {
+	out, err := gitOutput("rev-parse", "--git-path", rebaseDir)
+	if err != nil {
+		return "", err
+	}
+	dir, err := filepath.Abs(strings.TrimSpace(string(out)))
+	if err != nil {
+		return "", err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return "", err
+	}
+	return dir, os.MkdirAll(dir, 0o755)
+}
+
+// rebaseTodo picks every commit in order and amends the rewritten ones
+// right after they are applied.
+func rebaseTodo(commits []rangeCommit, rewritten map[string]string, dir string) (string, error) {
+	var todo strings.Builder
+	for i, c := range commits {
+		subject, _, _ := strings.Cut(c.Message, "\n")
+		fmt.Fprintf(&todo, "pick %s %s\n", c.Hash, subject)
+
+		message, ok := rewritten[c.Hash]
+		if !ok {
+			continue
+		}
+		path := filepath.Join(dir, fmt.Sprintf("%d.txt", i+1))
+		if err := os.WriteFile(path, []byte(message+"\n"), 0o644); err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&todo, "exec git commit --amend --allow-empty --no-verify -q --cleanup=strip -F %s\n", shellQuote(path))
+		// The amend skips the hooks, which audit.enabled records
+		audit, err := auditExec("rebase-msgs")
+		if err != nil {
+			return "", err
+		}
+		todo.WriteString(audit)
+	}
+	return todo.String(), nil
+}
+
+// runRebase starts git rebase -i with the prepared todo list in place of
+// the editor.
+func runRebase(upstream, todoPath string) error {
+	rebase := gitCommand("rebase", "-i", upstream)
+	rebase.Env = append(os.Environ(), "GIT_SEQUENCE_EDITOR=cp "+shellQuote(todoPath))
+	rebase.Stdin = os.Stdin
+	rebase.Stdout = os.Stdout
+	rebase.Stderr = os.Stderr
+	return rebase.Run()
+}
+
+// shellQuote quotes a path for the POSIX shell git runs editors and exec
+// lines with, on Windows too.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(filepath.ToSlash(s), "'", `'\''`) + "'"
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/message"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Where redact.store keeps the full messages.
+const (
+	redactNotes = "notes"
+	redactFile  = "file"
+)
+
+// privateNotesRef holds the full messages for redact.store: notes. It is
+// separate from the notes.ref metadata, which may be shared.
+const privateNotesRef = "commitz-private"
+
+// privateDir is the git directory folder for redact.store: file.
+const privateDir = "commitz-private"
+
+var privateCmd = &cobra.Command{
+	Use:   "private [revision]",
+	Short: "Show the full message kept locally for a redacted commit",
+	Long: `With redact.enabled, commits get only the header and the footers in
+redact.keep_footers; the full message stays in this clone. This prints the
+full message of a commit, HEAD by default.`,
+	Example: `  commitz private
+  commitz private HEAD~3`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		rev := "HEAD"
+		if len(args) == 1 {
+			rev = args[0]
+		}
+		message, err := privateMessage(rev)
+		if err != nil {
+			color.Red("%s", tr("redact.show_error", rev, err))
+			os.Exit(1)
+		}
+		fmt.Println(message)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(privateCmd)
+}
+
+// redactMessage returns what to commit when redact.enabled is set: the
+// header and the footers to keep. full is the message to keep locally, or
+// "" when nothing was left out.
+func redactMessage(msg string) (public, full string) {
+	if !cfg.Redact.Enabled {
+		return msg, ""
+	}
+
+	public, _, _ = strings.Cut(msg, "\n")
+	var kept []string
+	if message.ParseFooters(msg) != nil {
+		paragraphs := strings.Split(strings.TrimSpace(msg), "\n\n")
+		for _, line := range strings.Split(paragraphs[len(paragraphs)-1], "\n") {
+			m := message.FooterPattern.FindStringSubmatch(line)
+			if containsFold(cfg.Redact.KeepFooters, m[1]) {
+				kept = append(kept, line)
+			}
+		}
+	}
+	if len(kept) > 0 {
+		public += "\n\n" + strings.Join(kept, "\n")
+	}
+
+	if public == strings.TrimSpace(msg) {
+		return msg, ""
+	}
+	return public, msg
+}
+
+// storePrivateMessage keeps the full message of HEAD in this clone only.
+// Failures are reported but do not undo the commit.
+func storePrivateMessage(full string) {
+	if full == "" {
+		return
+	}
+
+	var err error
+	switch strings.ToLower(cfg.Redact.Store) {
+	case "", redactNotes:
+		notes := gitCommand("notes", "--ref="+privateNotesRef, "add", "-f", "-F", "-", "HEAD")
+		notes.Stdin = strings.NewReader(full)
+		if out, runErr := notes.CombinedOutput(); runErr != nil {
+			err = fmt.Errorf("%v: %s", runErr, strings.TrimSpace(string(out)))
+		}
+	case redactFile:
+		var path string
+		path, err = privateFilePath("HEAD")
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(path), 0o700)
+		}
+		if err == nil {
+			err = os.WriteFile(path, []byte(full+"\n"), 0o600)
+		}
+	default:
+		err = errors.New(tr("redact.unknown_store", cfg.Redact.Store))
+	}
+	if err != nil {
+		color.Red("%s", tr("redact.store_error", err))
+		return
+	}
+	fmt.Println(tr("redact.stored"))
+}
+
+// privateMessage returns the full message kept for a commit.
+func privateMessage(rev string) (string, error) {
+	switch strings.ToLower(cfg.Redact.Store) {
+	case "", redactNotes:
+		out, err := gitOutput("notes", "--ref="+privateNotesRef, "show", rev)
+		if err != nil {
+			return "", errors.New(tr("redact.none"))
+		}
+		return strings.TrimSpace(string(out)), nil
+	case redactFile:
+		path, err := privateFilePath(rev)
+		if err != nil {
+			return "", err
+		}
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return "", errors.New(tr("redact.none"))
+		}
+		return strings.TrimSpace(string(data)), err
+	}
+	return "", errors.New(tr("redact.unknown_store", cfg.Redact.Store))
+}
+
+// privateFilePath is the sidecar file of a commit for redact.store: file.
+func privateFilePath(rev string) (string, error) {
+	out, err := gitOutput("rev-parse", "--verify", rev+"^{commit}")
+	if err != nil {
+		return "", err
+	}
+	return gitDirPath(privateDir + "/" + strings.TrimSpace(string(out)))
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/barisdilekci/commitz/internal/config"
+)
+
+func TestRedactMessage(t *testing.T) {
+	defer func(c config.RedactConfig) { cfg.Redact = c }(cfg.Redact)
+	cfg.Redact = config.RedactConfig{Enabled: true, KeepFooters: []string{"BREAKING CHANGE", "closes"}}
+
+	tests := []struct {
+		message, public string
+		redacted        bool
+	}{
+		{"fix: handle nil", "fix: handle nil", false},
+		{"fix: handle nil\n\nThe cache returned nil for evicted keys.", "fix: handle nil", true},
+		{
+			"feat!: drop v1\n\nInternal billing no longer calls it.\n\nBREAKING CHANGE: v1 is gone\nRefs: PROJ-9\nCloses #12",
+			"feat!: drop v1\n\nBREAKING CHANGE: v1 is gone\nCloses #12",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		public, full := redactMessage(tt.message)
+		if public != tt.public {
+			t.Errorf("redactMessage(%q) public = %q, want %q", tt.message, public, tt.public)
+		}
+		if (full != "") != tt.redacted || (full != "" && full != tt.message) {
+			t.Errorf("redactMessage(%q) full = %q", tt.message, full)
+		}
+	}
+
+	cfg.Redact.Enabled = false
+	if public, full := redactMessage(tests[1].message); public != tests[1].message || full != "" {
+		t.Errorf("disabled redaction changed the message to %q", public)
+	}
+}
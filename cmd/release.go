@@ -0,0 +1,580 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+)
+
+// semverPattern matches versions like 1.2.3, 1.2.3-rc.1 and 1.2.3+build.5,
+// with an optional leading v.
+var semverPattern = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z.-]+))?(?:\+([0-9A-Za-z.-]+))?$`)
+
+// tagLightweight selects plain tags instead of annotated ones.
+const tagLightweight = "lightweight"
+
+// Version bumps, from smallest to largest.
+const (
+	bumpNone = iota
+	bumpPatch
+	bumpMinor
+	bumpMajor
+)
+
+var (
+	releaseScope  string
+	releasePre    string
+	releaseBuild  string
+	releaseGitHub bool
+	releaseDraft  bool
+	releaseLatest bool
+)
+
+type semver struct {
+	Major, Minor, Patch int
+	// Pre is the pre-release, e.g. "rc.1", and Build the build metadata.
+	Pre   string
+	Build string
+}
+
+func parseSemver(s string) (semver, bool) {
+	m := semverPattern.FindStringSubmatch(s)
+	if m == nil {
+		return semver{}, false
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	patch, _ := strconv.Atoi(m[3])
+	return semver{major, minor, patch, m[4], m[5]}, true
+}
+
+func (v semver) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// core drops the pre-release and build metadata.
+func (v semver) core() semver {
+	return semver{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+}
+
+// less compares by semver precedence: build metadata is ignored and a
+// pre-release sorts before its final version.
+func (v semver) less(o semver) bool {
+	if v.Major != o.Major {
+		return v.Major < o.Major
+	}
+	if v.Minor != o.Minor {
+		return v.Minor < o.Minor
+	}
+	if v.Patch != o.Patch {
+		return v.Patch < o.Patch
+	}
+	switch {
+	case v.Pre == o.Pre:
+		return false
+	case v.Pre == "":
+		return false
+	case o.Pre == "":
+		return true
+	}
+	return lessPrerelease(v.Pre, o.Pre)
+}
+
+// lessPrerelease compares dot-separated identifiers: numbers numerically
+// and before words, and a shorter list first when all else is equal.
+func lessPrerelease(a, b string) bool {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] == bs[i] {
+			continue
+		}
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			return an < bn
+		case aErr == nil:
+			return true
+		case bErr == nil:
+			return false
+		}
+		return as[i] < bs[i]
+	}
+	return len(as) < len(bs)
+}
+
+// prerelease splits "rc.2" into its channel and number.
+func prerelease(pre string) (string, int) {
+	channel, number, ok := strings.Cut(pre, ".")
+	n, err := strconv.Atoi(number)
+	if !ok || err != nil {
+		return pre, 0
+	}
+	return channel, n
+}
+
+// nextVersion applies a bump to the latest final version. With a channel,
+// the result is a pre-release that continues the latest pre-release of the
+// same version (rc.1 → rc.2); without one, a pending pre-release is
+// promoted to its final version. A channel that sorts before the pending
+// pre-release, such as alpha after rc, is an error.
+func nextVersion(final, latest semver, kind int, channel, build string) (semver, error) {
+	next := final.bump(kind)
+	if latest.Pre != "" && !latest.core().less(next) {
+		next = latest.core()
+	}
+
+	if channel != "" {
+		number := 1
+		if latest.Pre != "" && latest.core() == next {
+			if c, n := prerelease(latest.Pre); c == channel {
+				number = n + 1
+			}
+		}
+		next.Pre = fmt.Sprintf("%s.%d", channel, number)
+		if latest.Pre != "" && next.less(latest) {
+			return semver{}, errors.New(tr("release.lower_channel", channel, "v"+latest.String()))
+		}
+	}
+	next.Build = build
+	return next, nil
+}
+
+// bump returns the next version. Before 1.0.0, breaking changes only bump
+// the minor version, as is usual for initial development.
+func (v semver) bump(kind int) semver {
+	switch {
+	case kind == bumpMajor && v.Major > 0:
+		return semver{Major: v.Major + 1}
+	case kind == bumpMajor, kind == bumpMinor:
+		return semver{Major: v.Major, Minor: v.Minor + 1}
+	case kind == bumpPatch:
+		return semver{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+	}
+	return v.core()
+}
+
+var releaseCmd = &cobra.Command{
+	Use:   "release",
+	Short: "Calculate the next version from conventional commits and tag it",
+	Long: `Looks at the conventional commits since the latest version tag and
+creates the next semantic version tag: breaking changes bump the major
+version, features the minor version and fixes or performance improvements
+the patch version.
+
+With --pre, the release is a pre-release on that channel, numbered after
+the previous one (rc.1, rc.2, ...). Channels go forward only: alpha
+after a pending rc of the same version is refused. A release without
+--pre promotes a pending pre-release to its final version.
+
+With --github, the tag is pushed to the canonical remote (see --remote)
+and a GitHub release is created with the generated changelog as its
+notes. The token is read from GITHUB_TOKEN or GH_TOKEN, or taken from the
+gh CLI.
+
+In a monorepo, --scope limits the release to one package: only commits
+touching that path or using its name as their scope count, and tags are
+prefixed with the package name, like auth/v1.3.0.
+
+Tags are annotated with a summary of the changes they include, unless
+release.tag is set to "lightweight" in the config.`,
+	Example: `  commitz release --dry-run
+  commitz release --scope pkg/auth
+
+  # v2.0.0-rc.1, then v2.0.0-rc.2, then promote to v2.0.0
+  commitz release --pre rc
+  commitz release --pre rc
+  commitz release
+
+  # Publish a draft release on GitHub
+  commitz release --github --draft`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkHistoryDepth()
+		plan, ok := showReleasePlan(releaseScope, releasePre, releaseBuild)
+		if !ok {
+			return
+		}
+		next, version := plan.Next, plan.Version
+		if err := gitRun("show-ref", "--verify", "-q", "refs/tags/"+next); err == nil {
+			color.Red("%s", tr("release.exists", next))
+			os.Exit(1)
+		}
+		if dryRun {
+			if releaseGitHub {
+				previewReleaseNotes(next)
+			}
+			return
+		}
+
+		// A tag without its release is worse than no tag, so the repository
+		// and the token are checked first
+		var target githubTarget
+		if releaseGitHub {
+			target = githubReleaseCheck()
+		}
+		if !confirmRelease(next) {
+			color.Yellow("%s", tr("commit.cancelled"))
+			return
+		}
+		if err := createTag(next); err != nil {
+			color.Red("%s", tr("release.tag_failed", next, err))
+			os.Exit(1)
+		}
+		color.Green("%s", tr("release.tagged", next))
+
+		if releaseGitHub {
+			publishGitHubRelease(target, next, version.Pre != "")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(releaseCmd)
+
+	releaseCmd.Flags().StringVar(
+		&releaseScope,
+		"scope",
+		"",
+		"Release one package of a monorepo, e.g. pkg/auth",
+	)
+
+	releaseCmd.Flags().StringVar(
+		&releasePre,
+		"pre",
+		"",
+		"Create a pre-release on this channel, e.g. alpha, beta or rc",
+	)
+
+	releaseCmd.Flags().StringVar(
+		&releaseBuild,
+		"build",
+		"",
+		"Build metadata to append, e.g. a commit count or CI run",
+	)
+
+	releaseCmd.Flags().BoolVar(
+		&releaseGitHub,
+		"github",
+		false,
+		"Push the tag and create a GitHub release with the generated notes",
+	)
+
+	releaseCmd.Flags().BoolVar(
+		&releaseDraft,
+		"draft",
+		false,
+		"Create the GitHub release as a draft",
+	)
+
+	releaseCmd.Flags().BoolVar(
+		&releaseLatest,
+		"latest",
+		true,
+		"Mark the GitHub release as the latest release (never for pre-releases)",
+	)
+}
+
+// createTag creates an annotated tag summarizing the release, or a
+// lightweight tag when release.tag is "lightweight".
+func createTag(tag string) error {
+	if strings.EqualFold(cfg.Release.Tag, tagLightweight) {
+		return gitRun("tag", tag)
+	}
+	message, err := tagMessage(tag)
+	if err != nil {
+		return err
+	}
+	return gitRun("tag", "--annotate", "--cleanup=verbatim", "--message", message, tag)
+}
+
+// tagMessage lists the release's changes by section in plain text, since
+// tag messages are read in a terminal rather than rendered.
+func tagMessage(tag string) (string, error) {
+	data, err := buildChangelog(releaseBase(tag, "HEAD"), "HEAD", releaseScope)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString(tag + "\n")
+	line := func(c historyCommit, text string) {
+		if c.Scope != "" {
+			text = c.Scope + ": " + text
+		}
+		b.WriteString("- " + text + "\n")
+	}
+
+	if len(data.Breaking) > 0 {
+		b.WriteString("\n" + tr("release.tag_breaking") + "\n")
+		for _, c := range data.Breaking {
+			line(c, c.BreakingNote)
+		}
+	}
+	for _, s := range data.Sections {
+		b.WriteString("\n" + s.Title + "\n")
+		for _, c := range s.Commits {
+			line(c, c.Subject)
+		}
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// releaseBase is the tag the changes of release tag at rev are counted
+// from. A final release covers everything since the previous final
+// version, like the version bump, so pre-releases in between are skipped.
+func releaseBase(tag, rev string) string {
+	prefix := tagPrefix(releaseScope)
+	v, _ := parseSemver(strings.TrimPrefix(tag, prefix))
+	return previousTag(rev, prefix, v.Pre == "")
+}
+
+// releaseNotes renders the changelog of release tag at rev for its
+// release.
+func releaseNotes(tag, rev string) (string, error) {
+	data, err := buildChangelog(releaseBase(tag, rev), rev, releaseScope)
+	if err != nil {
+		return "", err
+	}
+	return renderChangelog(data)
+}
+
+func previewReleaseNotes(tag string) {
+	notes, err := releaseNotes(tag, "HEAD")
+	if err != nil {
+		color.Red("%s", tr("changelog.error", err))
+		os.Exit(1)
+	}
+	fmt.Println()
+	fmt.Println(tr("release.notes_preview"))
+	fmt.Print(notes)
+}
+
+// githubReleaseCheck returns where the release of --github goes, or exits
+// before anything is tagged when it cannot be created.
+func githubReleaseCheck() githubTarget {
+	if skipOffline("offline.github_release") {
+		fmt.Println(tr("release.offline_hint"))
+		os.Exit(1)
+	}
+	target, err := githubReleaseTarget()
+	if err != nil {
+		color.Red("%s", tr("github.release_failed", err))
+		fmt.Println(tr("release.not_tagged"))
+		os.Exit(1)
+	}
+	return target
+}
+
+// publishGitHubRelease pushes the tag and creates its GitHub release.
+func publishGitHubRelease(target githubTarget, tag string, prerelease bool) {
+	notes, err := releaseNotes(tag, tag)
+	if err != nil {
+		color.Red("%s", tr("changelog.error", err))
+		os.Exit(1)
+	}
+
+	// The release is created where the links point, so the tag goes there
+	remote := canonicalRemote()
+	if remote == "" {
+		remote = "origin"
+	}
+	if err := gitRun("push", remote, "refs/tags/"+tag); err != nil {
+		color.Red("%s", tr("release.push_failed", tag, remote, err))
+		os.Exit(1)
+	}
+
+	latest := "false"
+	if releaseLatest && !prerelease && !releaseDraft {
+		latest = "true"
+	}
+	url, err := createGitHubRelease(target, githubRelease{
+		TagName:    tag,
+		Name:       tag,
+		Body:       notes,
+		Draft:      releaseDraft,
+		Prerelease: prerelease,
+		MakeLatest: latest,
+	})
+	if err != nil {
+		color.Red("%s", tr("github.release_failed", err))
+		fmt.Println(tr("github.release_hint", tag))
+		os.Exit(1)
+	}
+	color.Green("%s", tr("github.release_created", url))
+}
+
+// releasePlan is the next version of the repository or of one package.
+type releasePlan struct {
+	// Tag is the latest version tag, or "" when there is none
+	Tag     string
+	Next    string
+	Version semver
+	Kind    int
+	Commits []historyCommit
+}
+
+// planRelease works out the next version from the commits since the
+// latest final version of the scope. Kind is bumpNone when there is
+// nothing to release.
+func planRelease(scope, channel, build string) (releasePlan, error) {
+	prefix := tagPrefix(scope)
+	final, finalTag, latest, tag := latestVersions(prefix)
+	plan := releasePlan{Tag: tag}
+
+	// Pre-releases do not count: a release after rc.2 covers everything
+	// since the last final version
+	commits, err := scopedCommits(finalTag, "HEAD", scope)
+	if err != nil {
+		return plan, errors.New(tr("changelog.error", err))
+	}
+	plan.Commits = commits
+	plan.Kind = versionBump(commits)
+	if plan.Kind == bumpNone {
+		return plan, nil
+	}
+
+	plan.Version, err = nextVersion(final, latest, plan.Kind, channel, build)
+	if err != nil {
+		return plan, err
+	}
+	plan.Next = prefix + "v" + plan.Version.String()
+	return plan, nil
+}
+
+// showReleasePlan prints the current and the next version. It reports
+// false when there is nothing to release, and exits on errors.
+func showReleasePlan(scope, channel, build string) (releasePlan, bool) {
+	plan, err := planRelease(scope, channel, build)
+	if err != nil {
+		color.Red("%s", err)
+		os.Exit(1)
+	}
+	if plan.Kind == bumpNone {
+		color.Yellow("%s", tr("release.nothing", displayTag(plan.Tag)))
+		return plan, false
+	}
+	fmt.Println(tr("release.current", displayTag(plan.Tag)))
+	fmt.Println(tr("release.next", color.GreenString("%s", plan.Next), tr(fmt.Sprintf("release.bump_%d", plan.Kind)), len(plan.Commits)))
+	return plan, true
+}
+
+// tagPrefix returns the tag prefix of a monorepo package: "auth/" for
+// pkg/auth, or "" for the whole repository.
+func tagPrefix(scope string) string {
+	scope = strings.Trim(scope, "/")
+	if scope == "" {
+		return ""
+	}
+	return path.Base(scope) + "/"
+}
+
+func displayTag(tag string) string {
+	if tag == "" {
+		return tr("release.no_tag")
+	}
+	return tag
+}
+
+// latestVersions finds the highest final and the highest overall version
+// tags with the prefix that are reachable from HEAD. Missing versions are
+// 0.0.0 with an empty tag.
+func latestVersions(prefix string) (final semver, finalTag string, latest semver, latestTag string) {
+	out, err := gitOutput("tag", "--list", prefix+"v*", "--merged", "HEAD")
+	if err != nil {
+		return
+	}
+
+	for _, tag := range strings.Fields(string(out)) {
+		v, ok := parseSemver(strings.TrimPrefix(tag, prefix))
+		if !ok {
+			continue
+		}
+		if latestTag == "" || latest.less(v) {
+			latest, latestTag = v, tag
+		}
+		if v.Pre == "" && (finalTag == "" || final.less(v)) {
+			final, finalTag = v, tag
+		}
+	}
+	return
+}
+
+// versionBump picks the largest bump the commits call for.
+func versionBump(commits []historyCommit) int {
+	kind := bumpNone
+	for _, c := range commits {
+		switch {
+		case c.Breaking:
+			kind = max(kind, bumpMajor)
+		case c.Type == "feat":
+			kind = max(kind, bumpMinor)
+		case c.Type == "fix", c.Type == "perf":
+			kind = max(kind, bumpPatch)
+		}
+	}
+	return kind
+}
+
+// scopedCommits returns the commits in from..to. With a scope, only commits
+// touching that path or using the package name as their scope are kept.
+func scopedCommits(from, to, scope string) ([]historyCommit, error) {
+	commits, err := historyCommits(from, to)
+	if err != nil || strings.Trim(scope, "/") == "" {
+		return commits, err
+	}
+
+	revRange := to
+	if from != "" {
+		revRange = from + ".." + to
+	}
+	out, err := gitOutput("log", "--format=%H", revRange, "--", strings.Trim(scope, "/"))
+	if err != nil {
+		return nil, err
+	}
+	touched := strings.Fields(string(out))
+	sort.Strings(touched)
+
+	name := path.Base(strings.Trim(scope, "/"))
+	var kept []historyCommit
+	for _, c := range commits {
+		i := sort.SearchStrings(touched, c.Hash)
+		if (i < len(touched) && touched[i] == c.Hash) || strings.EqualFold(c.Scope, name) {
+			kept = append(kept, c)
+		}
+	}
+	return kept, nil
+}
+
+func confirmRelease(tag string) bool {
+	if assumeYes {
+		return true
+	}
+	if interactive {
+		prompt := promptui.Prompt{
+			Label:     tr("release.confirm", tag),
+			IsConfirm: true,
+		}
+		result, err := runPrompt(prompt)
+		return err == nil && isYes(result)
+	}
+
+	fmt.Print(tr("release.confirm_plain", tag))
+	answer, _ := readLine()
+	return isYes(answer) || strings.TrimSpace(answer) == ""
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReleaseBase(t *testing.T) {
+	commit := func(message string) []string {
+		return []string{"commit", "-q", "--allow-empty", "-m", message}
+	}
+	testRepo(t,
+		commit("feat: first"),
+		[]string{"tag", "v1.0.0"},
+		commit("feat: before the rc"),
+		[]string{"tag", "v1.1.0-rc.1"},
+		commit("fix: after the rc"),
+	)
+
+	tests := []struct {
+		tag, rev, want string
+	}{
+		// A final release covers the pre-releases before it
+		{"v1.1.0", "HEAD", "v1.0.0"},
+		// A pre-release continues from the previous one
+		{"v1.1.0-rc.2", "HEAD", "v1.1.0-rc.1"},
+		{"v1.1.0-rc.1", "v1.1.0-rc.1", "v1.0.0"},
+	}
+	for _, tt := range tests {
+		if got := releaseBase(tt.tag, tt.rev); got != tt.want {
+			t.Errorf("releaseBase(%q, %q) = %q, want %q", tt.tag, tt.rev, got, tt.want)
+		}
+	}
+
+	// Once tagged, the notes of the final release still start at v1.0.0
+	if err := gitRun("tag", "v1.1.0"); err != nil {
+		t.Fatal(err)
+	}
+	if got := releaseBase("v1.1.0", "v1.1.0"); got != "v1.0.0" {
+		t.Errorf("releaseBase(v1.1.0) after tagging = %q, want v1.0.0", got)
+	}
+}
+
+func TestNextVersionChannel(t *testing.T) {
+	final := semver{Major: 1}
+	tests := []struct {
+		latest, channel, want string
+		err                   bool
+	}{
+		{"1.1.0-rc.1", "rc", "1.1.0-rc.2", false},
+		{"1.1.0-alpha.2", "beta", "1.1.0-beta.1", false},
+		{"1.1.0-rc.1", "alpha", "", true},
+		{"1.1.0-rc.1", "", "1.1.0", false},
+		{"1.0.0", "alpha", "1.1.0-alpha.1", false},
+	}
+	for _, tt := range tests {
+		latest, _ := parseSemver(tt.latest)
+		got, err := nextVersion(final, latest, bumpMinor, tt.channel, "")
+		if (err != nil) != tt.err || (err == nil && got.String() != tt.want) {
+			t.Errorf("nextVersion(%s, %q) = %s, %v; want %q, error %v", tt.latest, tt.channel, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestPlanReleaseScope(t *testing.T) {
+	repo := testRepo(t,
+		[]string{"commit", "-q", "--allow-empty", "-m", "feat: first"},
+		[]string{"tag", "auth/v1.0.0"},
+		[]string{"tag", "v2.0.0"},
+	)
+	for _, dir := range []string{"pkg/auth", "pkg/web"} {
+		if err := os.MkdirAll(filepath.Join(repo, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit := func(path, message string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, path), []byte(message+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := gitRun("add", "."); err != nil {
+			t.Fatal(err)
+		}
+		if err := gitRun("commit", "-q", "-m", message); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commit("pkg/auth/login.go", "fix: handle expired sessions")
+	commit("pkg/web/page.go", "feat: add the landing page")
+
+	plan, err := planRelease("pkg/auth", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Tag != "auth/v1.0.0" || plan.Next != "auth/v1.0.1" || len(plan.Commits) != 1 {
+		t.Errorf("planRelease(pkg/auth) = %s -> %s with %d commit(s), want auth/v1.0.0 -> auth/v1.0.1 with 1", plan.Tag, plan.Next, len(plan.Commits))
+	}
+
+	plan, err = planRelease("", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Next != "v2.1.0" {
+		t.Errorf("planRelease() = %s, want v2.1.0", plan.Next)
+	}
+}
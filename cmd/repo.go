@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+)
+
+// ensureRepository stops early with a specific explanation when commitz is
+// not run inside a usable git repository, instead of a generic diff error.
+func ensureRepository(interactive bool) {
+	_, err := repoRoot()
+	if err == nil {
+		return
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		if errors.Is(err, exec.ErrNotFound) {
+			color.Red("%s", tr("repo.git_missing"))
+		} else {
+			color.Red("%s", tr("repo.error", err))
+		}
+		os.Exit(1)
+	}
+
+	stderr := string(exitErr.Stderr)
+	cwd, _ := os.Getwd()
+
+	switch {
+	case strings.Contains(stderr, "dubious ownership"):
+		color.Red("%s", tr("repo.dubious"))
+		fmt.Println(tr("repo.dubious_hint", cwd))
+		os.Exit(1)
+	case strings.Contains(stderr, "not a git repository"):
+		color.Red("%s", tr("repo.not_found"))
+		if nearest := nearestRepository(cwd); nearest != "" {
+			// Git stops searching at filesystem boundaries
+			fmt.Println(tr("repo.nearest", nearest))
+			os.Exit(1)
+		}
+		if offerGitInit(interactive) {
+			os.Exit(0)
+		}
+		os.Exit(1)
+	default:
+		color.Red("%s", tr("repo.error", strings.TrimSpace(stderr)))
+		os.Exit(1)
+	}
+}
+
+// nearestRepository walks up from dir looking for a .git entry.
+func nearestRepository(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// offerGitInit asks before creating a repository and reports whether one
+// was created.
+func offerGitInit(interactive bool) bool {
+	if assumeYes {
+		fmt.Println(tr("repo.not_found_hint"))
+		return false
+	}
+
+	var accepted bool
+	if interactive {
+		prompt := promptui.Prompt{
+			Label:     tr("repo.init_prompt"),
+			IsConfirm: true,
+		}
+		result, err := runPrompt(prompt)
+		accepted = err == nil && isYes(result)
+	} else {
+		fmt.Print(tr("repo.init_prompt_plain"))
+		answer, _ := readLine()
+		accepted = isYes(answer)
+	}
+
+	if !accepted {
+		fmt.Println(tr("repo.not_found_hint"))
+		return false
+	}
+
+	if err := gitRun("init"); err != nil {
+		color.Red("%s", tr("repo.init_failed", err))
+		return false
+	}
+	color.Green("%s", tr("repo.initialized"))
+	fmt.Println(tr("diff.empty_hint"))
+	return true
+}
+
+// resetRepoCaches forgets what was read about the repository, as a new
+// commitz process would. Long-running commands call it before looking at
+// the repository again.
+func resetRepoCaches() {
+	repoRoot = sync.OnceValues(findRepoRoot)
+	currentBranch = sync.OnceValues(readCurrentBranch)
+	gitConfig = sync.OnceValue(loadGitConfig)
+	repoLearning = sync.OnceValue(loadLearning)
+	recentSubjects = sync.OnceValue(loadRecentSubjects)
+	repoHosting = sync.OnceValues(loadRepoHosting)
+	repoClone = sync.OnceValue(loadCloneInfo)
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// commitResult describes the commit just made.
+type commitResult struct {
+	Hash      string
+	Subject   string
+	Branch    string
+	Files     int
+	Additions int
+	Deletions int
+}
+
+// lastCommitResult reads the hash, subject and line counts of HEAD.
+func lastCommitResult() (commitResult, error) {
+	// --numstat rather than --shortstat, whose wording git translates
+	out, err := gitOutput("show", "--numstat", "--format=%h%x00%s", "HEAD")
+	if err != nil {
+		return commitResult{}, err
+	}
+
+	header, stats, _ := strings.Cut(string(out), "\n")
+	hash, subject, _ := strings.Cut(header, "\x00")
+	result := commitResult{Hash: hash, Subject: subject}
+	result.Branch, _ = currentBranch()
+	for _, line := range strings.Split(stats, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			continue
+		}
+		result.Files++
+		added, _ := strconv.Atoi(fields[0])
+		removed, _ := strconv.Atoi(fields[1])
+		result.Additions += added
+		result.Deletions += removed
+	}
+	return result, nil
+}
+
+// displayCommitResult shows what was committed and what to do next.
+func displayCommitResult() {
+	result, err := lastCommitResult()
+	if err != nil {
+		color.Red("%s", tr("commit.result_error", err))
+		return
+	}
+
+	fmt.Println()
+	if result.Branch == "" {
+		color.Green("%s", tr("result.committed_detached", result.Hash, result.Subject))
+	} else {
+		color.Green("%s", tr("result.committed", result.Hash, result.Branch, result.Subject))
+	}
+	fmt.Printf("  %s, %s, %s\n", tr("result.files", result.Files),
+		color.GreenString("+%d", result.Additions), color.RedString("-%d", result.Deletions))
+
+	for _, hint := range nextActions(result.Branch) {
+		fmt.Printf("  %s\n", hint)
+	}
+}
+
+// nextActions suggests pushing the branch and, off the default branch,
+// opening a pull request.
+func nextActions(branch string) []string {
+	if branch == "" {
+		return nil
+	}
+	remote := pushRemote()
+	if remote == "" {
+		return nil
+	}
+
+	var hints []string
+	if ahead, _, ok := aheadBehind(); ok {
+		if ahead == 0 {
+			return nil
+		}
+		hints = append(hints, tr("result.next_push", ahead))
+	} else {
+		hints = append(hints, tr("result.next_push_upstream", remote, branch))
+	}
+
+	if branch == defaultBranch(remote) || protectedBranch(branch) {
+		return hints
+	}
+	host, ok := repoHosting()
+	if remote != canonicalRemote() {
+		// A pull request starts from the fork the branch is pushed to
+		host, ok = remoteHosting(remote)
+	}
+	if ok {
+		if url := host.NewPullRequestURL(branch); url != "" {
+			hints = append(hints, tr("result.next_pr", url))
+		}
+	}
+	return hints
+}
+
+// pushRemote is origin, or the only remote, or "" when it is unclear
+// where to push.
+func pushRemote() string {
+	out, err := gitOutput("remote")
+	if err != nil {
+		return ""
+	}
+	remotes := strings.Fields(string(out))
+	switch {
+	case contains(remotes, "origin"):
+		return "origin"
+	case len(remotes) == 1:
+		return remotes[0]
+	}
+	return ""
+}
+
+// defaultBranch is the branch the remote's HEAD points to, or main.
+func defaultBranch(remote string) string {
+	out, err := gitOutput("symbolic-ref", "--short", "refs/remotes/"+remote+"/HEAD")
+	if err != nil {
+		return "main"
+	}
+	return strings.TrimPrefix(strings.TrimSpace(string(out)), remote+"/")
+}
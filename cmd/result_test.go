@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewPullRequestURL(t *testing.T) {
+	tests := []struct {
+		host hostingRepo
+		want string
+	}{
+		{hostingRepo{hostGitHub, "https://github.com/o/r"}, "https://github.com/o/r/pull/new/feature/x"},
+		{hostingRepo{hostGitLab, "https://gitlab.com/o/r"}, "https://gitlab.com/o/r/-/merge_requests/new?merge_request%5Bsource_branch%5D=feature%2Fx"},
+		{hostingRepo{hostBitbucket, "https://bitbucket.org/o/r"}, "https://bitbucket.org/o/r/pull-requests/new?source=feature%2Fx"},
+		{hostingRepo{hostGitea, "https://codeberg.org/o/r"}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.host.NewPullRequestURL("feature/x"); got != tt.want {
+			t.Errorf("%s: NewPullRequestURL = %q, want %q", tt.host.Kind, got, tt.want)
+		}
+	}
+}
+
+func TestLastCommitResult(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	isolateGit(t)
+	fixture, err := filepath.Abs(filepath.Join("testdata", "golden", "config-timeout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stageFixture(t, fixture)
+	if err := gitRun("commit", "-q", "-m", "fix: raise the timeout"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := lastCommitResult()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Subject != "fix: raise the timeout" || result.Branch != "main" || result.Hash == "" {
+		t.Errorf("lastCommitResult() = %+v", result)
+	}
+	if result.Files != 1 || result.Additions != 2 || result.Deletions != 1 {
+		t.Errorf("stats = %d files, +%d -%d, want 1 file, +2 -1", result.Files, result.Additions, result.Deletions)
+	}
+}
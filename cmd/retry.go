@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Retry defaults, unless network.retries and network.max_wait are set.
+const (
+	defaultRetries = 3
+	defaultMaxWait = 30 * time.Second
+)
+
+// retryBaseDelay is the first backoff step; tests shorten it.
+var retryBaseDelay = 500 * time.Millisecond
+
+// retryTransport retries requests that failed for a reason that may pass:
+// rate limits, overloaded or restarting servers and dropped connections.
+// Only rate limited requests are retried for every method, since the
+// server turned them away before doing anything; a POST that hit a server
+// error may have been applied already.
+type retryTransport struct {
+	next    http.RoundTripper
+	retries int
+	maxWait time.Duration
+}
+
+func newRetryTransport(next http.RoundTripper) http.RoundTripper {
+	retries := cfg.Network.Retries
+	if retries == 0 {
+		retries = defaultRetries
+	}
+	if retries < 0 {
+		return next
+	}
+	return retryTransport{next, retries, commandTimeout(cfg.Network.MaxWait, defaultMaxWait)}
+}
+
+func (t retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		wait, retry := retryDelay(req, resp, err, attempt)
+		if !retry || attempt >= t.retries || wait > t.maxWait || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+		if req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		logger.Info("retrying request", "url", req.URL.Redacted(), "attempt", attempt+1, "wait", wait, "status", responseStatus(resp), "error", err)
+		if wait >= time.Second {
+			fmt.Fprintln(os.Stderr, color.YellowString("%s", tr("network.retrying", req.URL.Host, wait.Round(time.Second))))
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryDelay decides whether a request is worth another try and how long
+// to wait first. A server's own estimate beats the exponential backoff.
+func retryDelay(req *http.Request, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	idempotent := req.Method == http.MethodGet || req.Method == http.MethodHead
+	if err != nil {
+		// A certificate that does not verify will not verify next time
+		var certErr *tls.CertificateVerificationError
+		return backoff(attempt), idempotent && req.Context().Err() == nil && !errors.As(err, &certErr)
+	}
+
+	switch code := resp.StatusCode; {
+	case code == http.StatusTooManyRequests,
+		// GitHub answers 403 when the hourly quota is used up
+		code == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0":
+		if wait, ok := rateLimitWait(resp.Header, time.Now()); ok {
+			return wait, true
+		}
+		return backoff(attempt), true
+	case code >= 500 && code != http.StatusNotImplemented && idempotent:
+		if wait, ok := rateLimitWait(resp.Header, time.Now()); ok {
+			return wait, true
+		}
+		return backoff(attempt), true
+	}
+	return 0, false
+}
+
+// rateLimitWait reads Retry-After, in seconds or as a date, or the reset
+// time GitHub (X-RateLimit-Reset) and GitLab (RateLimit-Reset) send.
+func rateLimitWait(header http.Header, now time.Time) (time.Duration, bool) {
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if at, err := http.ParseTime(value); err == nil {
+			return max(at.Sub(now), 0), true
+		}
+	}
+	for _, name := range []string{"X-RateLimit-Reset", "RateLimit-Reset"} {
+		if epoch, err := strconv.ParseInt(header.Get(name), 10, 64); err == nil {
+			return max(time.Unix(epoch, 0).Sub(now), 0), true
+		}
+	}
+	return 0, false
+}
+
+// backoff doubles with every attempt; the jitter in the upper half keeps
+// several clients from retrying in step.
+func backoff(attempt int) time.Duration {
+	d := retryBaseDelay << attempt
+	return d/2 + rand.N(d/2+1)
+}
+
+func responseStatus(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
@@ -4,50 +4,48 @@ Copyright © 2026 NAME HERE <barisdilekci@outlook.com.tr>
 package cmd
 
 import (
-	"bufio"
 	"fmt"
 	"os"
-	"os/exec"
-	"strings"
 
+	"github.com/barisdilekci/commitz/internal/detect"
 	"github.com/fatih/color"
-	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
 )
 
 var (
 	commitType  string
 	useEmoji    bool
+	useGitmoji  bool
 	dryRun      bool
 	interactive bool
 	commitScope string
+	showWhy     bool
+	statOnly    bool
+	assumeYes   bool
+	accessible  bool
+	quiet       bool
 )
 
-type CommitType struct {
-	Type        string
-	Emoji       string
-	Description string
-}
-
-var commitTypes = []CommitType{
-	{"feat", "✨", "A new feature"},
-	{"fix", "🐛", "A bug fix"},
-	{"docs", "📝", "Documentation only changes"},
-	{"style", "💄", "Changes that don't affect code meaning"},
-	{"refactor", "♻️", "Code change that neither fixes a bug nor adds a feature"},
-	{"perf", "⚡", "Performance improvements"},
-	{"test", "✅", "Adding or correcting tests"},
-	{"build", "🔨", "Changes to build system or dependencies"},
-	{"ci", "👷", "Changes to CI configuration"},
-	{"chore", "🧹", "Other changes that don't modify src or test files"},
-}
-
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "commitz",
 	Short: "Smart commit message generator",
 	Long: `Commitz helps you create well-formatted conventional commits.
-It can auto-detect commit types or guide you through an interactive process.`,
+It can auto-detect commit types or guide you through an interactive process.
+
+Run "commitz examples" to see common invocations and the messages they
+produce.`,
+	Example: `  # Detect everything and commit without asking
+  commitz -y
+
+  # Preview the message and why it was chosen
+  commitz -d --why
+
+  # Choose the type, scope and summary from menus
+  commitz -i -e
+
+  # Set the type and scope yourself
+  commitz -t fix -s api`,
 	Run: func(cmd *cobra.Command, args []string) {
 		generateCommitMessage()
 	},
@@ -55,13 +53,58 @@ It can auto-detect commit types or guide you through an interactive process.`,
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
-	err := rootCmd.Execute()
+	setupInterrupts()
+	err := rootCmd.ExecuteContext(rootCtx)
 	if err != nil {
 		os.Exit(1)
 	}
 }
 
 func init() {
+	cobra.OnInitialize(initLogging, initConfig, initLocale, initMessageLanguage, initQuiet, initGitmoji)
+
+	rootCmd.PersistentFlags().StringVar(
+		&cfgFile,
+		"config",
+		"",
+		"Config file (default: .commitz.yaml in the repository root)",
+	)
+
+	rootCmd.PersistentFlags().BoolVar(
+		&verbose,
+		"verbose",
+		false,
+		"Log detection decisions and config resolution",
+	)
+
+	rootCmd.PersistentFlags().BoolVar(
+		&debug,
+		"debug",
+		false,
+		"Log every git command in addition to --verbose output",
+	)
+
+	rootCmd.PersistentFlags().StringVar(
+		&logFile,
+		"log-file",
+		"",
+		"Write logs as JSON to this file instead of stderr",
+	)
+
+	rootCmd.PersistentFlags().StringVar(
+		&remoteFlag,
+		"remote",
+		"",
+		"Remote whose platform gets links and API calls (default: hosting.remote, upstream or origin)",
+	)
+
+	rootCmd.PersistentFlags().BoolVar(
+		&offlineFlag,
+		"offline",
+		false,
+		"Turn off issue lookups, update checks, GitHub releases and other network features",
+	)
+
 	rootCmd.PersistentFlags().StringVarP(
 		&commitType,
 		"type",
@@ -86,6 +129,13 @@ func init() {
 		"Add emoji to commit message",
 	)
 
+	rootCmd.PersistentFlags().BoolVar(
+		&useGitmoji,
+		"gitmoji",
+		false,
+		"Pick from the full gitmoji catalogue; implies --emoji",
+	)
+
 	rootCmd.PersistentFlags().BoolVarP(
 		&dryRun,
 		"dry-run",
@@ -101,260 +151,199 @@ func init() {
 		false,
 		"Enable interactive commit mode",
 	)
-}
-
-func generateCommitMessage() {
-	// Get staged changes
-	diffBytes, err := exec.Command("git", "diff", "--cached").Output()
-	if err != nil {
-		color.Red("Error getting git diff: %v", err)
-		fmt.Println("Make sure you are in a git repository and have staged changes.")
-		os.Exit(1)
-	}
-
-	diffStr := string(diffBytes)
-	if len(diffStr) == 0 {
-		color.Yellow("No staged changes found.")
-		fmt.Println("Please stage your changes with 'git add' before generating a commit message.")
-		os.Exit(0)
-	}
 
-	var selectedType string
-	var selectedScope string
-	var selectedEmoji string
+	rootCmd.PersistentFlags().BoolVar(
+		&accessible,
+		"accessible",
+		false,
+		"Use numbered plain-text prompts that work with screen readers",
+	)
 
-	// Interactive mode
-	if interactive {
-		selectedType, selectedEmoji = selectCommitTypeInteractive()
-		selectedScope = selectScopeInteractive()
-	} else {
-		// Auto-detect or use provided flags
-		selectedType = detectCommitType(diffStr)
-		if commitType != "" {
-			selectedType = commitType
-		}
+	rootCmd.PersistentFlags().BoolVarP(
+		&quiet,
+		"quiet",
+		"q",
+		false,
+		"Print only the new commit's hash and subject, without colors or prompts",
+	)
 
-		selectedScope = extractScopeFromBranch()
-		if commitScope != "" {
-			selectedScope = commitScope
-		}
+	rootCmd.PersistentFlags().BoolVarP(
+		&assumeYes,
+		"yes",
+		"y",
+		false,
+		"Commit the suggested message without asking for a description or confirmation",
+	)
 
-		selectedEmoji = getEmojiForType(selectedType)
-	}
+	rootCmd.Flags().BoolVar(
+		&statOnly,
+		"stat-only",
+		false,
+		"Analyze only file names and line counts, for very large diffs",
+	)
 
-	// Generate summary with smart suggestion
-	summary := generateSummaryInteractive(interactive, diffStr, selectedType)
+	addDiffFlags(rootCmd)
 
-	// Build commit message
-	message := buildCommitMessage(selectedEmoji, selectedType, selectedScope, summary)
+	rootCmd.Flags().BoolVar(
+		&perScope,
+		"per-scope",
+		false,
+		"Create one commit per scope when the staged files span several",
+	)
 
-	// Display suggested message
-	displaySuggestedMessage(message)
+	rootCmd.Flags().BoolVar(
+		&deselect,
+		"deselect",
+		false,
+		"Pick staged files to unstage before the message is suggested",
+	)
 
-	// Add optional description
-	message = addDescriptionInteractive(message, interactive)
+	rootCmd.Flags().BoolVar(
+		&skipChecks,
+		"skip-checks",
+		false,
+		"Do not run the configured checks commands",
+	)
 
-	// Handle dry-run
-	if dryRun {
-		color.Yellow("\n[DRY RUN] Commit not created")
-		fmt.Println("\nProposed commit message:")
-		fmt.Println(color.CyanString(message))
-		return
-	}
+	rootCmd.Flags().BoolVar(
+		&profileStartup,
+		"profile-startup",
+		false,
+		"Print how long each startup phase took",
+	)
 
-	// Confirm and commit
-	if confirmCommitInteractive(interactive) {
-		executeCommit(message)
-	} else {
-		color.Yellow("Commit cancelled.")
-	}
+	rootCmd.Flags().BoolVar(
+		&showWhy,
+		"why",
+		false,
+		"Explain why the commit type and scope were chosen",
+	)
 }
 
-func selectCommitTypeInteractive() (string, string) {
-	templates := &promptui.SelectTemplates{
-		Label:    "{{ . }}?",
-		Active:   "▸ {{ .Emoji }} {{ .Type | cyan }} - {{ .Description }}",
-		Inactive: "  {{ .Emoji }} {{ .Type | cyan }} - {{ .Description }}",
-		Selected: "{{ .Emoji }} {{ .Type | cyan }}",
+func generateCommitMessage() {
+	if quiet && interactive {
+		color.Red("%s", tr("quiet.interactive"))
+		os.Exit(1)
 	}
+	quietOutput()
+	ensureRepository(interactive)
+	restoreLeftoverPatch()
 
-	prompt := promptui.Select{
-		Label:     "Select commit type",
-		Items:     commitTypes,
-		Templates: templates,
-		Size:      10,
-	}
+	// Run independent git queries while the diff streams in
+	prefetchGitState()
+	checkGitIdentity()
 
-	idx, _, err := prompt.Run()
+	// Get staged changes
+	files, err := loadStagedFiles()
 	if err != nil {
-		color.Red("Selection cancelled")
-		os.Exit(0)
+		color.Red("%s", tr("diff.error", err))
+		fmt.Println(tr("diff.error_hint"))
+		os.Exit(1)
 	}
 
-	selected := commitTypes[idx]
-	emoji := ""
-	if useEmoji {
-		emoji = selected.Emoji + " "
+	if len(files) == 0 {
+		color.Yellow("%s", tr("diff.empty"))
+		fmt.Println(tr("diff.empty_hint"))
+		os.Exit(0)
 	}
 
-	return selected.Type, emoji
-}
-
-func selectScopeInteractive() string {
-	// Try to extract scope from branch first
-	branchScope := extractScopeFromBranch()
-
-	// Get common scopes from project structure
-	commonScopes := getCommonScopes()
-
-	// Add branch scope if available
-	if branchScope != "" {
-		commonScopes = append([]string{branchScope + " (from branch)"}, commonScopes...)
+	markStartup("diff")
+	files = deselectFiles(files, interactive)
+	classifyFiles(files)
+	markStartup("classify")
+	scanned, err := scannedFiles(files)
+	if err != nil {
+		color.Red("%s", tr("diff.error", err))
+		fmt.Println(tr("diff.error_hint"))
+		os.Exit(1)
 	}
 
-	// Add "no scope" option
-	commonScopes = append(commonScopes, "Skip (no scope)")
-
-	prompt := promptui.Select{
-		Label: "Select scope (optional)",
-		Items: commonScopes,
-		Size:  8,
+	// Block before the diff reaches a summary generator
+	checkSecrets(scanned)
+	if perScope && commitPerScope(files, scanned) {
+		return
 	}
 
-	_, result, err := prompt.Run()
-	if err != nil || strings.Contains(result, "Skip") {
-		return ""
+	// Ignored paths must not influence any suggestion
+	if len(cfg.Analysis.Ignore) > 0 {
+		files = filterIgnoredFiles(files)
 	}
-
-	// Remove "(from branch)" suffix if present
-	result = strings.TrimSuffix(result, " (from branch)")
-	return result
-}
-
-func getCommonScopes() []string {
-	scopes := []string{}
-
-	// Check for common directories
-	dirs := []string{"cmd", "pkg", "internal", "api", "web", "docs", "test", "config", "auth", "db", "ui"}
-
-	for _, dir := range dirs {
-		if _, err := os.Stat(dir); err == nil {
-			scopes = append(scopes, dir)
-		}
+	diffStr := detect.DiffText(files)
+	if interactive {
+		displayStatusHeader(files)
+	}
+
+	// Pick up a message left behind by an interrupted run
+	message, restored := restoreDraft(interactive)
+	if !restored {
+		message = composeMessage(diffStr, files)
+	}
+	// A restored draft skips detection
+	reportStartupProfile()
+	message = requireFooters(message, interactive)
+	message = requireTicket(message, interactive)
+	message = checkSpellingInteractive(message, interactive)
+	message = enforceMessageRules(message, interactive)
+	saveDraft(message)
+	displayQualityScore(scoreMessage(message, changedLineCount(files)))
+
+	// Catch debug leftovers and conflict markers before they reach history
+	displayContentWarnings(scanContentWarnings(scanned), interactive)
+	displayLargeFiles(findLargeFiles(scanned))
+	session.Size = measureCommit(files)
+	displayCommitSize(session.Size, files)
+	checkDuplicateSubject(message)
+	displayStrippedLines(message)
+	modified, untracked := worktreeChanges()
+	displayWorktreeChanges(modified, untracked)
+	guardProtectedBranch(message, interactive)
+
+	// Only the header goes public in repositories that are mirrored
+	message, private := redactMessage(message)
+	if private != "" {
+		fmt.Println(tr("redact.redacted"))
 	}
 
-	// Add generic options
-	scopes = append(scopes, "core", "deps", "ci")
-
-	return scopes
-}
-
-func generateSmartSummary(diff string, commitType string) string {
-	diffLower := strings.ToLower(diff)
-
-	// Extract file names from diff
-	lines := strings.Split(diff, "\n")
-	var modifiedFiles []string
-	var addedContent []string
-
-	for _, line := range lines {
-		// Check for file changes
-		if strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---") {
-			parts := strings.Fields(line)
-			if len(parts) >= 2 && parts[1] != "/dev/null" {
-				fileName := strings.TrimPrefix(parts[1], "b/")
-				fileName = strings.TrimPrefix(fileName, "a/")
-				if fileName != "" && !contains(modifiedFiles, fileName) {
-					modifiedFiles = append(modifiedFiles, fileName)
-				}
-			}
-		}
-
-		// Look for added lines with meaningful content
-		if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") {
-			content := strings.TrimPrefix(line, "+")
-			content = strings.TrimSpace(content)
-			if len(content) > 10 && !strings.HasPrefix(content, "//") && !strings.HasPrefix(content, "/*") {
-				addedContent = append(addedContent, content)
-			}
+	// Handle dry-run
+	if dryRun {
+		recordUsage(outcomeDryRun)
+		if quiet {
+			fmt.Fprintln(resultOutput, message)
+			return
 		}
+		color.Yellow("%s", tr("message.dry_run"))
+		fmt.Println(tr("message.proposed"))
+		fmt.Println(color.CyanString("%s", message))
+		return
 	}
 
-	// Generate smart summary based on commit type and changes
-	switch commitType {
-	case "feat":
-		if strings.Contains(diffLower, "interactive") {
-			return "add interactive mode"
-		}
-		if strings.Contains(diffLower, "api") {
-			return "add API endpoints"
-		}
-		if len(modifiedFiles) > 0 {
-			baseName := getBaseName(modifiedFiles[0])
-			return fmt.Sprintf("add %s functionality", baseName)
-		}
-		return "add new feature"
-
-	case "fix":
-		if strings.Contains(diffLower, "bug") || strings.Contains(diffLower, "error") {
-			return "fix bug in error handling"
-		}
-		if len(modifiedFiles) > 0 {
-			baseName := getBaseName(modifiedFiles[0])
-			return fmt.Sprintf("fix issue in %s", baseName)
-		}
-		return "fix bug"
-
-	case "docs":
-		if strings.Contains(diffLower, "readme") {
-			return "update README documentation"
-		}
-		return "update documentation"
-
-	case "refactor":
-		if len(modifiedFiles) > 0 {
-			baseName := getBaseName(modifiedFiles[0])
-			return fmt.Sprintf("refactor %s", baseName)
-		}
-		return "refactor code structure"
-
-	case "test":
-		return "add/update tests"
-
-	case "style":
-		return "improve code formatting"
-
-	case "perf":
-		return "improve performance"
-
-	case "build":
-		if strings.Contains(diffLower, "go.mod") || strings.Contains(diffLower, "go.sum") {
-			return "update dependencies"
-		}
-		return "update build configuration"
-
-	case "ci":
-		return "update CI configuration"
-
-	case "chore":
-		if strings.Contains(diffLower, "cleanup") {
-			return "cleanup code"
+	// Confirm and commit
+	if !runChecks() {
+		os.Exit(1)
+	}
+	if confirmCommitInteractive(interactive) {
+		patch := setAsideUnstaged(interactive, modified)
+		restore := onInterrupt(func() {
+			if patch != "" {
+				restoreUnstaged(patch)
+			}
+		})
+		committed := commitWithRetry(message, interactive)
+		restore()
+		if !committed {
+			os.Exit(1)
 		}
-		return "update project files"
+		clearDraft()
+		storePrivateMessage(private)
+		recordUsage(outcomeCommitted)
+		recordLearning(files)
+		recordNote()
+		notifyUpdate()
+	} else {
+		clearDraft()
+		recordUsage(outcomeCancelled)
+		color.Yellow("%s", tr("commit.cancelled"))
 	}
-
-	return "update changes"
-}
-
-func getBaseName(filePath string) string {
-	// Remove extension and get base name
-	parts := strings.Split(filePath, "/")
-	fileName := parts[len(parts)-1]
-	fileName = strings.TrimSuffix(fileName, ".go")
-	fileName = strings.TrimSuffix(fileName, ".js")
-	fileName = strings.TrimSuffix(fileName, ".ts")
-	fileName = strings.TrimSuffix(fileName, ".md")
-	return fileName
 }
 
 func contains(slice []string, item string) bool {
@@ -365,175 +354,3 @@ func contains(slice []string, item string) bool {
 	}
 	return false
 }
-
-func generateSummaryInteractive(interactive bool, diff string, commitType string) string {
-	// Generate smart suggestion
-	suggestion := generateSmartSummary(diff, commitType)
-
-	if !interactive {
-		return suggestion
-	}
-
-	validate := func(input string) error {
-		if len(input) < 3 {
-			return fmt.Errorf("summary must be at least 3 characters")
-		}
-		if len(input) > 72 {
-			return fmt.Errorf("summary should be under 72 characters")
-		}
-		return nil
-	}
-
-	prompt := promptui.Prompt{
-		Label:    fmt.Sprintf("Commit summary (suggestion: %s)", color.CyanString(suggestion)),
-		Default:  suggestion,
-		Validate: validate,
-	}
-
-	result, err := prompt.Run()
-	if err != nil {
-		color.Red("Input cancelled")
-		os.Exit(0)
-	}
-
-	return strings.TrimSpace(result)
-}
-
-func addDescriptionInteractive(message string, interactive bool) string {
-	if interactive {
-		prompt := promptui.Prompt{
-			Label:     "Add detailed description? (y/N)",
-			IsConfirm: true,
-		}
-
-		result, err := prompt.Run()
-		if err != nil || strings.ToLower(result) != "y" {
-			return message
-		}
-	}
-
-	fmt.Println("\n" + color.CyanString("Enter description (press Enter twice to finish):"))
-
-	scanner := bufio.NewScanner(os.Stdin)
-	var bodyLines []string
-	emptyLineCount := 0
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "" {
-			emptyLineCount++
-			if emptyLineCount >= 2 || (len(bodyLines) > 0 && emptyLineCount >= 1) {
-				break
-			}
-		} else {
-			emptyLineCount = 0
-			bodyLines = append(bodyLines, line)
-		}
-	}
-
-	body := strings.TrimSpace(strings.Join(bodyLines, "\n"))
-	if body != "" {
-		return message + "\n\n" + body
-	}
-
-	return message
-}
-
-func confirmCommitInteractive(interactive bool) bool {
-	if !interactive {
-		fmt.Print("\nProceed with commit? [Y/n]: ")
-		var confirm string
-		fmt.Scanln(&confirm)
-		confirm = strings.ToLower(strings.TrimSpace(confirm))
-		return confirm == "y" || confirm == ""
-	}
-
-	prompt := promptui.Prompt{
-		Label:     "Proceed with commit",
-		IsConfirm: true,
-	}
-
-	result, err := prompt.Run()
-	if err != nil {
-		return false
-	}
-
-	return strings.ToLower(result) == "y" || result == ""
-}
-
-func detectCommitType(diff string) string {
-	diffLower := strings.ToLower(diff)
-
-	if strings.Contains(diff, "test/") || strings.Contains(diff, "_test.go") {
-		return "test"
-	}
-	if strings.Contains(diff, "README") || strings.Contains(diff, ".md") || strings.Contains(diff, "docs/") {
-		return "docs"
-	}
-	if strings.Contains(diffLower, "fix") || strings.Contains(diffLower, "bug") {
-		return "fix"
-	}
-	if strings.Contains(diffLower, "feat") || strings.Contains(diffLower, "add ") || strings.Contains(diffLower, "new ") {
-		return "feat"
-	}
-
-	return "chore"
-}
-
-func extractScopeFromBranch() string {
-	branchBytes, err := exec.Command("git", "branch", "--show-current").Output()
-	if err != nil {
-		return ""
-	}
-
-	branchName := strings.TrimSpace(string(branchBytes))
-	if strings.Contains(branchName, "/") {
-		parts := strings.SplitN(branchName, "/", 2)
-		if len(parts) > 1 {
-			return strings.TrimSpace(parts[0])
-		}
-	}
-
-	return ""
-}
-
-func getEmojiForType(commitType string) string {
-	if !useEmoji {
-		return ""
-	}
-
-	for _, ct := range commitTypes {
-		if ct.Type == commitType {
-			return ct.Emoji + " "
-		}
-	}
-
-	return ""
-}
-
-func buildCommitMessage(emoji, commitType, scope, summary string) string {
-	if scope != "" {
-		return fmt.Sprintf("%s%s(%s): %s", emoji, commitType, scope, summary)
-	}
-	return fmt.Sprintf("%s%s: %s", emoji, commitType, summary)
-}
-
-func displaySuggestedMessage(message string) {
-	fmt.Println()
-	color.Green("Suggested commit message:")
-	fmt.Printf("  %s\n", color.GreenString(message))
-}
-
-func executeCommit(message string) {
-	commitCmd := exec.Command("git", "commit", "-F", "-")
-	commitCmd.Stdin = strings.NewReader(message)
-	commitCmd.Stdout = os.Stdout
-	commitCmd.Stderr = os.Stderr
-
-	if err := commitCmd.Run(); err != nil {
-		color.Red("Commit failed: %v", err)
-		os.Exit(1)
-	}
-
-	color.Green("✓ Commit successful! 🎉")
-}
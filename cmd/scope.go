@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Scope casing modes.
+const (
+	scopeCasePreserve = "preserve"
+	scopeCaseLower    = "lower"
+	scopeCaseKebab    = "kebab"
+)
+
+// ticketPrefixPattern matches a leading ticket key such as "PROJ-123-".
+var ticketPrefixPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*-\d+(?:[-_.]|$)`)
+
+// normalizeScope applies scopes.case to every part of a scope, so Auth, auth
+// and AUTH end up as one changelog section.
+func normalizeScope(scope string) string {
+	mode := strings.ToLower(cfg.Scopes.Case)
+	if mode != scopeCaseLower && mode != scopeCaseKebab {
+		return scope
+	}
+
+	parts := splitScopes(scope)
+	for i, part := range parts {
+		if mode == scopeCaseKebab {
+			parts[i] = kebabCase(part)
+		} else {
+			parts[i] = strings.ToLower(part)
+		}
+	}
+	return strings.Join(parts, scopeDelimiter())
+}
+
+// scopeDelimiter joins multiple scopes, "," unless scopes.delimiter says
+// otherwise.
+func scopeDelimiter() string {
+	if cfg.Scopes.Delimiter != "" {
+		return cfg.Scopes.Delimiter
+	}
+	return ","
+}
+
+// splitScopes splits a scope like "api,auth" into its parts.
+func splitScopes(scope string) []string {
+	if strings.TrimSpace(scope) == "" {
+		return nil
+	}
+	// ", " also splits "api,auth", but a delimiter of only spaces is kept
+	delimiter := scopeDelimiter()
+	if trimmed := strings.TrimSpace(delimiter); trimmed != "" {
+		delimiter = trimmed
+	}
+	var parts []string
+	for _, part := range strings.Split(scope, delimiter) {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// multipleScopesAllowed reports whether a commit may name several scopes,
+// which scopes.multiple set to "deny" forbids.
+func multipleScopesAllowed() bool {
+	return !strings.EqualFold(cfg.Scopes.Multiple, "deny")
+}
+
+// kebabCase turns "AuthService", "auth_service" and "Auth Service" into
+// "auth-service".
+func kebabCase(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == ' ' || r == '-':
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "-") {
+				b.WriteByte('-')
+			}
+		case unicode.IsUpper(r):
+			// Start a word at lower-to-upper and at the end of an acronym
+			startsWord := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])))
+			if startsWord && !strings.HasSuffix(b.String(), "-") {
+				b.WriteByte('-')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+// cleanBranchScope cleans up a scope taken from the branch name, dropping a
+// leading ticket key when scopes.strip_ticket is set.
+func cleanBranchScope(scope string) string {
+	if cfg.Scopes.StripTicket {
+		scope = ticketPrefixPattern.ReplaceAllString(scope, "")
+	}
+	return normalizeScope(scope)
+}
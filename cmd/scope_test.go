@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/barisdilekci/commitz/internal/config"
+)
+
+func TestSplitScopes(t *testing.T) {
+	defer func(c config.ScopesConfig) { cfg.Scopes = c }(cfg.Scopes)
+
+	tests := []struct {
+		delimiter, scope string
+		want             []string
+	}{
+		{"", "api,auth", []string{"api", "auth"}},
+		{", ", "api, auth", []string{"api", "auth"}},
+		{", ", "api,auth", []string{"api", "auth"}},
+		{" ", "api auth", []string{"api", "auth"}},
+		{" ", "api  auth", []string{"api", "auth"}},
+		{"/", "api", []string{"api"}},
+		{"", " ", nil},
+	}
+	for _, tt := range tests {
+		cfg.Scopes.Delimiter = tt.delimiter
+		if got := splitScopes(tt.scope); !slices.Equal(got, tt.want) {
+			t.Errorf("splitScopes(%q) with delimiter %q = %q, want %q", tt.scope, tt.delimiter, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/barisdilekci/commitz/internal/message"
+	"github.com/fatih/color"
+)
+
+// largeDiffLines is the number of changed lines above which a commit is
+// expected to explain itself in a body.
+const largeDiffLines = 100
+
+// ticketPattern matches issue references like ABC-123, #42 or (#42).
+var ticketPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9]+-\d+\b|(^|[\s(])#\d+\b`)
+
+// vagueWords make a subject say little about the change.
+var vagueWords = []string{
+	"update", "updates", "updated", "change", "changes", "changed", "fix", "fixes",
+	"stuff", "things", "misc", "various", "minor", "wip", "files", "code", "tweak", "tweaks",
+}
+
+// scorePart is one criterion of the quality score.
+type scorePart struct {
+	Name   string
+	Points int
+	Max    int
+	Hint   string
+}
+
+type qualityScore struct {
+	Total int
+	Parts []scorePart
+}
+
+// scoreMessage rates a commit message from 0 to 100 on specificity,
+// imperative mood, subject length, a body for large changes and a ticket
+// reference. changedLines < 0 means the diff size is unknown.
+func scoreMessage(msg string, changedLines int) qualityScore {
+	header, body, _ := strings.Cut(msg, "\n")
+	subject := header
+	if h, ok := message.ParseHeader(header); ok {
+		subject = h.Subject
+	}
+
+	parts := []scorePart{
+		scoreSpecificity(subject),
+		scoreMood(subject),
+		scoreLength(header),
+		scoreBody(strings.TrimSpace(body), changedLines),
+		scoreTicket(msg),
+	}
+
+	score := qualityScore{Parts: parts}
+	for _, p := range parts {
+		score.Total += p.Points
+	}
+	return score
+}
+
+func scoreSpecificity(subject string) scorePart {
+	part := scorePart{Name: "specificity", Max: 25}
+
+	words := strings.Fields(strings.ToLower(subject))
+	vague := 0
+	for _, w := range words {
+		if contains(vagueWords, strings.Trim(w, ".,;:")) {
+			vague++
+		}
+	}
+
+	switch {
+	case len(words) < 2:
+		part.Hint = tr("score.hint_specific")
+	case len(words)-vague < 2:
+		part.Points = 10
+		part.Hint = tr("score.hint_specific")
+	case len(words) < 3:
+		part.Points = 18
+	default:
+		part.Points = 25
+	}
+	return part
+}
+
+func scoreMood(subject string) scorePart {
+	part := scorePart{Name: "imperative", Max: 20}
+
+	first, _, _ := strings.Cut(strings.TrimSpace(subject), " ")
+	if message.IsImperative(first) {
+		part.Points = part.Max
+	} else {
+		part.Hint = tr("score.hint_imperative", first)
+	}
+	return part
+}
+
+func scoreLength(header string) scorePart {
+	part := scorePart{Name: "length", Max: 20}
+
+	n := message.Width(header)
+	switch {
+	case n > 72:
+		part.Hint = tr("score.hint_too_long", n)
+	case n > 50:
+		part.Points = 12
+		part.Hint = tr("score.hint_long", n)
+	case n < 15:
+		part.Points = 10
+		part.Hint = tr("score.hint_short")
+	default:
+		part.Points = part.Max
+	}
+	return part
+}
+
+func scoreBody(body string, changedLines int) scorePart {
+	part := scorePart{Name: "body", Max: 20, Points: 20}
+	if body == "" && changedLines > largeDiffLines {
+		part.Points = 0
+		part.Hint = tr("score.hint_body", changedLines)
+	}
+	return part
+}
+
+func scoreTicket(message string) scorePart {
+	part := scorePart{Name: "ticket", Max: 15}
+	if ticketPattern.MatchString(message) {
+		part.Points = part.Max
+	} else {
+		part.Hint = tr("score.hint_ticket")
+	}
+	return part
+}
+
+// changedLineCount sums the added and removed lines of the staged files.
+func changedLineCount(files []git.File) int {
+	total := 0
+	for _, f := range files {
+		total += f.Additions + f.Deletions
+	}
+	return total
+}
+
+// displayQualityScore prints the score on one line with the top hints.
+func displayQualityScore(score qualityScore) {
+	if quiet {
+		return
+	}
+	var hints []string
+	for _, p := range score.Parts {
+		if p.Hint != "" {
+			hints = append(hints, p.Hint)
+		}
+	}
+
+	line := tr("score.line", scoreColor(score.Total))
+	if len(hints) > 0 {
+		line += " — " + strings.Join(hints, "; ")
+	}
+	fmt.Println()
+	fmt.Println(line)
+}
+
+// displayScoreBreakdown prints every criterion of the score.
+func displayScoreBreakdown(score qualityScore) {
+	printScoreBreakdown(os.Stdout, score)
+}
+
+func printScoreBreakdown(w io.Writer, score qualityScore) {
+	fmt.Fprintln(w, tr("score.line", scoreColor(score.Total)))
+	for _, p := range score.Parts {
+		fmt.Fprintf(w, "  %-12s %2d/%-2d  %s\n", p.Name, p.Points, p.Max, p.Hint)
+	}
+}
+
+func scoreColor(total int) string {
+	text := fmt.Sprintf("%d/100", total)
+	switch {
+	case total >= 80:
+		return color.GreenString("%s", text)
+	case total >= 50:
+		return color.YellowString("%s", text)
+	}
+	return color.RedString("%s", text)
+}
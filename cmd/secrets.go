@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/fatih/color"
+)
+
+// Secret scanning modes for the secrets.mode config value.
+const (
+	secretsBlock = "block"
+	secretsWarn  = "warn"
+	secretsOff   = "off"
+)
+
+// secretRule is a named pattern for a likely credential.
+type secretRule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// secretRules follow the common gitleaks rules for well-known token formats.
+var secretRules = []secretRule{
+	{"AWS access key", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"AWS secret key", regexp.MustCompile(`(?i)aws.{0,20}['"][0-9a-zA-Z/+]{40}['"]`)},
+	{"GitHub token", regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{60,})\b`)},
+	{"GitLab token", regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}\b`)},
+	{"Slack token", regexp.MustCompile(`\bxox[baprs]-[0-9A-Za-z-]{10,}\b`)},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{"Stripe key", regexp.MustCompile(`\b[sr]k_live_[0-9a-zA-Z]{24,}\b`)},
+	{"API secret key", regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{32,}\b`)},
+	{"private key", regexp.MustCompile(`-----BEGIN ([A-Z]+ )?PRIVATE KEY-----`)},
+	{"JSON web token", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\b`)},
+	{"hard-coded credential", regexp.MustCompile(`(?i)\b(api[_-]?key|secret|token|passw(or)?d)\b\s*[:=]\s*['"][^'"\s]{12,}['"]`)},
+}
+
+// secretFinding is a likely credential in an added line.
+type secretFinding struct {
+	Path  string
+	Line  int
+	Rule  string
+	Match string
+}
+
+// scanSecrets checks added lines for likely credentials.
+func scanSecrets(files []git.File) []secretFinding {
+	var allowed []*regexp.Regexp
+	for _, pattern := range cfg.Secrets.Allow {
+		if re, err := regexp.Compile(pattern); err == nil {
+			allowed = append(allowed, re)
+		}
+	}
+
+	var findings []secretFinding
+	for _, f := range files {
+		if f.Binary || f.Deleted {
+			continue
+		}
+		for i, line := range f.Added {
+			for _, rule := range secretRules {
+				match := rule.Pattern.FindString(line)
+				if match == "" || matchesAny(allowed, match) {
+					continue
+				}
+				findings = append(findings, secretFinding{Path: f.Path, Line: f.AddedAt[i], Rule: rule.Name, Match: match})
+				break
+			}
+		}
+	}
+
+	return findings
+}
+
+// checkSecrets reports likely credentials and aborts in block mode unless
+// nothing is going to be committed.
+func checkSecrets(files []git.File) {
+	mode := strings.ToLower(cfg.Secrets.Mode)
+	if mode == secretsOff {
+		return
+	}
+
+	findings := scanSecrets(files)
+	if len(findings) == 0 {
+		return
+	}
+
+	fmt.Println()
+	color.Red("%s", tr("secrets.title"))
+	for _, f := range findings {
+		fmt.Printf("  %s  %s: %s\n", color.CyanString("%s:%d", f.Path, f.Line), f.Rule, redact(f.Match))
+	}
+
+	if mode == secretsWarn || dryRun {
+		return
+	}
+
+	fmt.Println(tr("secrets.blocked"))
+	os.Exit(1)
+}
+
+// redact keeps only a short prefix of a matched secret.
+func redact(secret string) string {
+	runes := []rune(secret)
+	if len(runes) <= 8 {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:6]) + strings.Repeat("*", len(runes)-6)
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/config"
+	"github.com/barisdilekci/commitz/internal/detect"
+	"github.com/barisdilekci/commitz/internal/git"
+)
+
+// defaultSecurityScope is suggested for security-sensitive changes unless
+// security.scope says otherwise.
+const defaultSecurityScope = "security"
+
+// securityPathWords mark directories and files holding auth, crypto,
+// permission and input validation code.
+var securityPathWords = []string{
+	"auth", "authn", "authz", "oauth", "oidc", "saml", "sso", "login", "session",
+	"crypto", "cipher", "encrypt", "tls", "cert", "certs", "jwt", "token",
+	"permission", "permissions", "acl", "rbac", "policy", "policies",
+	"security", "sanitize", "sanitizer", "validation", "validator", "validators", "csrf",
+}
+
+// securityLinePattern finds security-relevant code in changed lines.
+var securityLinePattern = regexp.MustCompile(`(?i)"crypto/|x/crypto|\bbcrypt\b|\bargon2|\bscrypt\b|\bhmac\b|\bjwt\b|\bcsrf\b|\bxss\b|sanitiz|authori[sz]|authenticat|permission|\brbac\b|InsecureSkipVerify|tls\.Config|\bpassword\b`)
+
+// securitySensitive reports whether the change touches security-sensitive
+// code and returns what gave it away, a path or a matched word.
+func securitySensitive(files []git.File) (bool, string) {
+	if strings.EqualFold(cfg.Security.Mode, "off") {
+		return false, ""
+	}
+
+	relevant, _ := detect.SplitNoise(files)
+	for _, f := range relevant {
+		for _, pattern := range cfg.Security.Paths {
+			if config.MatchGlob(pattern, f.Path) {
+				return true, f.Path
+			}
+		}
+		if git.IsTestFile(f.Path) {
+			continue
+		}
+		for _, part := range strings.Split(filepath.ToSlash(f.Path), "/") {
+			name := strings.ToLower(strings.TrimSuffix(part, filepath.Ext(part)))
+			if contains(securityPathWords, name) {
+				return true, f.Path
+			}
+		}
+	}
+
+	for _, f := range relevant {
+		if git.IsTestFile(f.Path) || !git.IsSourceFile(f.Path) {
+			continue
+		}
+		for _, lines := range [][]string{f.Added, f.Removed} {
+			for _, line := range lines {
+				if m := securityLinePattern.FindString(line); m != "" {
+					return true, strings.Trim(m, `"`)
+				}
+			}
+		}
+	}
+	return false, ""
+}
+
+// securityScope is the scope suggested for security-sensitive changes.
+func securityScope() string {
+	if cfg.Security.Scope != "" {
+		return normalizeScope(cfg.Security.Scope)
+	}
+	return defaultSecurityScope
+}
+
+// availableCommitTypes lists the commit types types allows and those
+// plugins add, plus security.type when it is not one of them.
+func availableCommitTypes() []CommitType {
+	types := commitTypes
+	if len(cfg.Types) > 0 {
+		types = slices.DeleteFunc(slices.Clone(commitTypes), func(ct CommitType) bool {
+			return !contains(cfg.Types, ct.Type)
+		})
+	}
+	for _, ct := range pluginCommitTypes() {
+		if !slices.ContainsFunc(types, func(t CommitType) bool { return t.Type == ct.Type }) {
+			types = append(slices.Clone(types), ct)
+		}
+	}
+	custom := cfg.Security.Type
+	if custom == "" || slices.ContainsFunc(types, func(ct CommitType) bool { return ct.Type == custom }) {
+		return types
+	}
+	return append(slices.Clone(types), CommitType{custom, "🔒", "Security fixes and hardening"})
+}
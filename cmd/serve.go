@@ -0,0 +1,351 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+)
+
+// serveDebounce lets git finish writing the index before it is read
+// again; a single git add can replace it several times.
+const serveDebounce = 200 * time.Millisecond
+
+// Events sent by /watch. The data of a suggestion event is the JSON of
+// commitz suggest --format json.
+const (
+	eventSuggestion = "suggestion"
+	eventEmpty      = "empty"
+	eventError      = "error"
+)
+
+var (
+	serveAddr   string
+	serveSocket string
+)
+
+// serveHandshake is the first line commitz serve prints on stdout. An
+// editor reads it to learn where to connect and which token to send.
+type serveHandshake struct {
+	Version int    `json:"version"`
+	URL     string `json:"url,omitempty"`
+	Socket  string `json:"socket,omitempty"`
+	Token   string `json:"token"`
+}
+
+// serveEvent is the suggestion for one state of the index.
+type serveEvent struct {
+	Name string
+	Data []byte
+}
+
+// suggestionFeed keeps the latest event and hands every new one to the
+// connected /watch clients.
+type suggestionFeed struct {
+	mu          sync.Mutex
+	latest      serveEvent
+	subscribers map[chan serveEvent]struct{}
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve live suggestions for the staged changes to an editor",
+	Long: `Runs a local server for editor integrations. It watches the index and
+composes a new suggestion whenever the staged content or the branch
+changes, so an editor sidebar can show a live commit message preview
+without polling.
+
+The first line on stdout is a JSON handshake with the address and a token
+to send as "Authorization: Bearer <token>". GET /suggestion returns the
+current suggestion; GET /watch streams one as server-sent events each time
+it changes. See docs/editor-integration.md.
+
+By default the server listens on a free port on 127.0.0.1; --socket
+listens on a Unix socket instead.`,
+	Example: `  commitz serve
+  commitz serve --socket /tmp/commitz.sock`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if interactive {
+			color.Red("%s", tr("suggest.interactive"))
+			os.Exit(1)
+		}
+		runServe()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVar(
+		&serveAddr,
+		"addr",
+		"127.0.0.1:0",
+		"Address to listen on; port 0 picks a free port",
+	)
+
+	serveCmd.Flags().StringVar(
+		&serveSocket,
+		"socket",
+		"",
+		"Listen on this Unix socket instead of a TCP address",
+	)
+}
+
+func runServe() {
+	// Like commitz suggest, nothing is asked, committed or kept as a draft
+	quiet, dryRun = true, true
+	initQuiet()
+	quietOutput()
+	ensureRepository(false)
+
+	indexPath, err := gitDirPath("index")
+	if err != nil {
+		color.Red("%s", tr("serve.error", err))
+		os.Exit(1)
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err == nil {
+		err = watcher.Add(filepath.Dir(indexPath))
+	}
+	if err != nil {
+		color.Red("%s", tr("serve.watch_error", err))
+		os.Exit(1)
+	}
+	defer watcher.Close()
+
+	listener, handshake, err := serveListen()
+	if err != nil {
+		color.Red("%s", tr("serve.error", err))
+		os.Exit(1)
+	}
+	token, err := serveToken()
+	if err != nil {
+		color.Red("%s", tr("serve.error", err))
+		os.Exit(1)
+	}
+	handshake.Token = token
+
+	feed := &suggestionFeed{subscribers: map[chan serveEvent]struct{}{}}
+	feed.publish(composeServeEvent())
+
+	server := &http.Server{Handler: serveHandler(feed, token)}
+	// Ctrl+C waits for this, so the socket file is removed
+	runningCommands.Add(1)
+	go func() {
+		defer runningCommands.Done()
+		<-rootCtx.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), interruptGracePeriod/2)
+		defer cancel()
+		server.Shutdown(ctx)
+	}()
+	go watchIndex(watcher, filepath.Base(indexPath), feed)
+
+	line, _ := json.Marshal(handshake)
+	fmt.Fprintln(resultOutput, string(line))
+	logger.Info("serving suggestions", "url", handshake.URL, "socket", handshake.Socket)
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		color.Red("%s", tr("serve.error", err))
+		os.Exit(1)
+	}
+}
+
+// serveListen opens the socket or TCP address to serve on.
+func serveListen() (net.Listener, serveHandshake, error) {
+	handshake := serveHandshake{Version: suggestProtocolVersion}
+	if serveSocket != "" {
+		listener, err := net.Listen("unix", serveSocket)
+		if err != nil {
+			return nil, handshake, err
+		}
+		// Only this user may connect
+		if err := os.Chmod(serveSocket, 0o600); err != nil {
+			listener.Close()
+			return nil, handshake, err
+		}
+		handshake.Socket = serveSocket
+		return listener, handshake, nil
+	}
+
+	listener, err := net.Listen("tcp", serveAddr)
+	if err != nil {
+		return nil, handshake, err
+	}
+	handshake.URL = "http://" + listener.Addr().String()
+	return listener, handshake, nil
+}
+
+// serveToken is a random secret clients must send, so other local users
+// and web pages cannot read the staged changes.
+func serveToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func serveHandler(feed *suggestionFeed, token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /suggestion", func(w http.ResponseWriter, r *http.Request) {
+		event := feed.current()
+		switch event.Name {
+		case eventEmpty:
+			w.WriteHeader(http.StatusNoContent)
+			return
+		case eventError:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+		}
+		w.Write(event.Data)
+	})
+	mux.HandleFunc("GET /watch", func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+		events, latest := feed.subscribe()
+		defer feed.unsubscribe(events)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		writeServeEvent(w, latest)
+		flusher.Flush()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case event := <-events:
+				writeServeEvent(w, event)
+				flusher.Flush()
+			}
+		}
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			http.Error(w, "missing or wrong token", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func writeServeEvent(w http.ResponseWriter, event serveEvent) {
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Name, event.Data)
+}
+
+// watchIndex composes a new suggestion after the index or HEAD, and so
+// the staged content or the branch, changed. Suggestions are composed
+// only here, one at a time, as they share commitz's global state.
+func watchIndex(watcher *fsnotify.Watcher, index string, feed *suggestionFeed) {
+	var pending <-chan time.Time
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			name := filepath.Base(event.Name)
+			if (name == index || name == "HEAD") && !event.Has(fsnotify.Chmod) {
+				pending = time.After(serveDebounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			logger.Info("watching the index failed", "error", err)
+		case <-pending:
+			pending = nil
+			resetRepoCaches()
+			feed.publish(composeServeEvent())
+		}
+	}
+}
+
+// composeServeEvent suggests a message for what is staged now.
+func composeServeEvent() serveEvent {
+	s, ok, err := stagedSuggestion()
+	switch {
+	case err != nil:
+		data, _ := json.Marshal(map[string]string{"error": err.Error()})
+		return serveEvent{eventError, data}
+	case !ok:
+		return serveEvent{eventEmpty, []byte("{}")}
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		data, _ = json.Marshal(map[string]string{"error": err.Error()})
+		return serveEvent{eventError, data}
+	}
+	return serveEvent{eventSuggestion, data}
+}
+
+// publish hands event to the clients unless it repeats the latest one,
+// as happens when the index is rewritten without changes.
+func (f *suggestionFeed) publish(event serveEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if event.Name == f.latest.Name && bytes.Equal(event.Data, f.latest.Data) {
+		return
+	}
+	f.latest = event
+	for events := range f.subscribers {
+		// A slow client skips the event it has not read yet; only the
+		// latest matters
+		select {
+		case <-events:
+		default:
+		}
+		events <- event
+	}
+}
+
+func (f *suggestionFeed) current() serveEvent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.latest
+}
+
+func (f *suggestionFeed) subscribe() (chan serveEvent, serveEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	events := make(chan serveEvent, 1)
+	f.subscribers[events] = struct{}{}
+	return events, f.latest
+}
+
+func (f *suggestionFeed) unsubscribe(events chan serveEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.subscribers, events)
+}
+
+// gitDirPath returns the absolute path of a file in the git directory.
+func gitDirPath(name string) (string, error) {
+	out, err := gitOutput("rev-parse", "--git-path", name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(strings.TrimSpace(string(out)))
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServeHandler(t *testing.T) {
+	feed := &suggestionFeed{subscribers: map[chan serveEvent]struct{}{}}
+	feed.publish(serveEvent{eventEmpty, []byte("{}")})
+	server := httptest.NewServer(serveHandler(feed, "secret"))
+	defer server.Close()
+
+	get := func(path, token string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	for _, token := range []string{"", "wrong"} {
+		resp := get("/suggestion", token)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("token %q: status %d, want 401", token, resp.StatusCode)
+		}
+	}
+	resp := get("/suggestion", "secret")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("nothing staged: status %d, want 204", resp.StatusCode)
+	}
+
+	watch := get("/watch", "secret")
+	defer watch.Body.Close()
+	events := bufio.NewReader(watch.Body)
+	next := func() string {
+		t.Helper()
+		var lines []string
+		for {
+			line, err := events.ReadString('\n')
+			if err != nil {
+				t.Fatal(err)
+			}
+			if line == "\n" {
+				return strings.Join(lines, "")
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	if got, want := next(), "event: empty\ndata: {}\n"; got != want {
+		t.Errorf("first event = %q, want %q", got, want)
+	}
+	suggested := serveEvent{eventSuggestion, []byte(`{"type":"feat"}`)}
+	feed.publish(suggested)
+	if got, want := next(), "event: suggestion\ndata: {\"type\":\"feat\"}\n"; got != want {
+		t.Errorf("second event = %q, want %q", got, want)
+	}
+	// Repeats are not sent again
+	feed.publish(suggested)
+	feed.publish(serveEvent{eventEmpty, []byte("{}")})
+	if got, want := next(), "event: empty\ndata: {}\n"; got != want {
+		t.Errorf("third event = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/barisdilekci/commitz/internal/message"
+	"github.com/fatih/color"
+)
+
+// sessionLogFile is kept in the git directory, next to the learning file.
+const sessionLogFile = "commitz-sessions.jsonl"
+
+// defaultSessionLogEntries bounds the log unless session_log.max_entries
+// is set.
+const defaultSessionLogEntries = 1000
+
+// Size classes of the suggestion stats, by changed lines.
+const (
+	smallCommitLines  = 50
+	mediumCommitLines = 300
+)
+
+// sessionEntry is one run in the session log: what commitz suggested on
+// its own, what was committed and how large the change was.
+type sessionEntry struct {
+	Time        time.Time  `json:"time"`
+	Outcome     string     `json:"outcome"`
+	Interactive bool       `json:"interactive"`
+	Type        string     `json:"type"`
+	Scope       string     `json:"scope,omitempty"`
+	Summary     string     `json:"summary"`
+	Suggested   string     `json:"suggested"`
+	Final       string     `json:"final,omitempty"`
+	Size        commitSize `json:"size"`
+}
+
+// suggestionTally counts how much of the suggestions was kept.
+type suggestionTally struct {
+	Committed, Unchanged, Header, Type, Scope, Summary int
+}
+
+func sessionLogPath() string {
+	out, err := gitOutput("rev-parse", "--git-path", sessionLogFile)
+	if err != nil {
+		return sessionLogFile
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// recordSession appends this run to the session log when
+// session_log.enabled is set. Failures are only logged.
+func recordSession(outcome string) {
+	if !cfg.SessionLog.Enabled || session.SuggestedMessage == "" {
+		return
+	}
+
+	entry := sessionEntry{
+		Time:        time.Now().UTC(),
+		Outcome:     outcome,
+		Interactive: interactive,
+		Type:        session.DetectedType,
+		Scope:       normalizeScope(session.DetectedScope),
+		Summary:     session.SuggestedSummary,
+		Suggested:   session.SuggestedMessage,
+		Size:        session.Size,
+	}
+	if outcome == outcomeCommitted {
+		if out, err := gitOutput("log", "-1", "--format=%B"); err == nil {
+			entry.Final = strings.TrimSpace(string(out))
+		}
+	}
+
+	limit := cfg.SessionLog.MaxEntries
+	if limit <= 0 {
+		limit = defaultSessionLogEntries
+	}
+	entries := append(loadSessionLog(), entry)
+	entries = entries[max(0, len(entries)-limit):]
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			logger.Info("session not logged", "error", err)
+			return
+		}
+	}
+	if err := writeFileAtomic(sessionLogPath(), buf.Bytes()); err != nil {
+		logger.Info("session not logged", "error", err)
+	}
+}
+
+// loadSessionLog reads the session log, skipping lines it cannot parse.
+func loadSessionLog() []sessionEntry {
+	data, err := os.ReadFile(sessionLogPath())
+	if err != nil {
+		return nil
+	}
+
+	var entries []sessionEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		var e sessionEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			logger.Info("skipping unreadable session", "error", err)
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// add compares a committed entry's final message with the suggestion.
+func (t *suggestionTally) add(e sessionEntry) {
+	t.Committed++
+	if e.Final == e.Suggested {
+		t.Unchanged++
+	}
+	suggested, _, _ := strings.Cut(e.Suggested, "\n")
+	final, _, _ := strings.Cut(e.Final, "\n")
+	if final == suggested {
+		t.Header++
+	}
+
+	h, ok := message.ParseHeader(final)
+	if !ok {
+		return
+	}
+	if h.Type == e.Type {
+		t.Type++
+	}
+	if h.Scope == e.Scope {
+		t.Scope++
+	}
+	if h.Subject == e.Summary {
+		t.Summary++
+	}
+}
+
+// sizeClass names the size class of a change for the suggestion stats.
+func sizeClass(size commitSize) string {
+	switch {
+	case size.Lines <= smallCommitLines:
+		return tr("stats.size_small", smallCommitLines)
+	case size.Lines <= mediumCommitLines:
+		return tr("stats.size_medium", mediumCommitLines)
+	}
+	return tr("stats.size_large", mediumCommitLines)
+}
+
+// displaySuggestionStats shows how much of the suggestions in the session
+// log was committed unchanged, overall, by detected type and by size.
+func displaySuggestionStats() {
+	if !cfg.SessionLog.Enabled {
+		color.Yellow("%s", tr("stats.session_log_off"))
+	}
+	entries := loadSessionLog()
+	if len(entries) == 0 {
+		fmt.Println(tr("stats.suggestions_empty"))
+		return
+	}
+
+	var total suggestionTally
+	byType := map[string]*suggestionTally{}
+	bySize := map[string]*suggestionTally{}
+	var edited []sessionEntry
+	for _, e := range entries {
+		if e.Outcome != outcomeCommitted || e.Final == "" {
+			continue
+		}
+		for _, t := range []*suggestionTally{&total, tally(byType, e.Type), tally(bySize, sizeClass(e.Size))} {
+			t.add(e)
+		}
+		if e.Final != e.Suggested {
+			edited = append(edited, e)
+		}
+	}
+
+	color.Cyan("%s", tr("stats.suggestions_title", len(entries), total.Committed))
+	if total.Committed == 0 {
+		return
+	}
+	fmt.Println(tr("stats.suggestions_unchanged", percent(total.Unchanged, total.Committed)))
+	fmt.Println(tr("stats.suggestions_header", percent(total.Header, total.Committed)))
+	fmt.Println(tr("stats.suggestions_parts",
+		percent(total.Type, total.Committed),
+		percent(total.Scope, total.Committed),
+		percent(total.Summary, total.Committed)))
+
+	fmt.Println()
+	fmt.Println(tr("stats.suggestions_by_type"))
+	displayTallies(byType)
+	fmt.Println()
+	fmt.Println(tr("stats.suggestions_by_size"))
+	displayTallies(bySize)
+
+	if n := len(edited); n > 0 {
+		fmt.Println()
+		fmt.Println(tr("stats.suggestions_edits"))
+		for _, e := range edited[max(0, n-5):] {
+			suggested, _, _ := strings.Cut(e.Suggested, "\n")
+			final, _, _ := strings.Cut(e.Final, "\n")
+			fmt.Printf("  %s → %s\n", color.New(color.Faint).Sprint(suggested), final)
+		}
+	}
+}
+
+func tally(tallies map[string]*suggestionTally, key string) *suggestionTally {
+	if tallies[key] == nil {
+		tallies[key] = &suggestionTally{}
+	}
+	return tallies[key]
+}
+
+// displayTallies prints one line per key, most commits first.
+func displayTallies(tallies map[string]*suggestionTally) {
+	keys := make([]string, 0, len(tallies))
+	for key := range tallies {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if tallies[keys[i]].Committed != tallies[keys[j]].Committed {
+			return tallies[keys[i]].Committed > tallies[keys[j]].Committed
+		}
+		return keys[i] < keys[j]
+	})
+
+	for _, key := range keys {
+		t := tallies[key]
+		fmt.Printf("  %-18s %5d  %s\n", key, t.Committed, tr("stats.suggestions_tally",
+			percent(t.Type, t.Committed), percent(t.Header, t.Committed)))
+	}
+}
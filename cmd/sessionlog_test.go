@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/barisdilekci/commitz/internal/config"
+)
+
+func TestSuggestionTally(t *testing.T) {
+	var tally suggestionTally
+	suggested := "feat(api): add endpoint\n\n- add api/user.go"
+	for _, final := range []string{
+		suggested,
+		"feat(api): add endpoint\n\nExplain why.",
+		"fix(api): add endpoint",
+		"feat: add the user endpoint",
+	} {
+		tally.add(sessionEntry{Type: "feat", Scope: "api", Summary: "add endpoint", Suggested: suggested, Final: final})
+	}
+
+	want := suggestionTally{Committed: 4, Unchanged: 1, Header: 2, Type: 3, Scope: 3, Summary: 3}
+	if tally != want {
+		t.Errorf("tally = %+v, want %+v", tally, want)
+	}
+}
+
+func TestRecordSession(t *testing.T) {
+	testRepo(t, []string{"commit", "-q", "--allow-empty", "-m", "feat(api): add the user endpoint"})
+
+	defer func(c config.SessionLogConfig) { cfg.SessionLog = c }(cfg.SessionLog)
+	cfg.SessionLog = config.SessionLogConfig{Enabled: true, MaxEntries: 2}
+	session.DetectedType, session.DetectedScope = "feat", "api"
+	session.SuggestedSummary, session.SuggestedMessage = "add endpoint", "feat(api): add endpoint"
+	session.Size = commitSize{Files: 1, Lines: 12, Packages: 1}
+	defer func() { session.SuggestedMessage, session.Size = "", commitSize{} }()
+
+	recordSession(outcomeDryRun)
+	recordSession(outcomeCancelled)
+	recordSession(outcomeCommitted)
+
+	entries := loadSessionLog()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want the last 2", len(entries))
+	}
+	last := entries[1]
+	if last.Outcome != outcomeCommitted || last.Final != "feat(api): add the user endpoint" || last.Size.Lines != 12 {
+		t.Errorf("last entry = %+v", last)
+	}
+	if entries[0].Final != "" {
+		t.Errorf("cancelled run has final message %q", entries[0].Final)
+	}
+}
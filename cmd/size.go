@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/fatih/color"
+)
+
+// Defaults for size.max_files, size.max_lines and size.max_packages.
+const (
+	defaultMaxFiles    = 25
+	defaultMaxLines    = 800
+	defaultMaxPackages = 5
+)
+
+// commitSize measures staged changes, or holds the limits for them.
+type commitSize struct {
+	Files int `json:"files"`
+	Lines int `json:"lines"`
+	// Packages counts the directories with changes, which are the packages
+	// in Go and close to them elsewhere.
+	Packages int `json:"packages"`
+}
+
+// measureCommit measures the staged files.
+func measureCommit(files []git.File) commitSize {
+	dirs := map[string]bool{}
+	for _, f := range files {
+		dirs[path.Dir(f.Path)] = true
+	}
+	return commitSize{Files: len(files), Lines: changedLineCount(files), Packages: len(dirs)}
+}
+
+// sizeLimits returns the configured limits or their defaults. Trunk mode
+// has its own, smaller file and line limits. A negative limit is off.
+func sizeLimits() commitSize {
+	limits := commitSize{cfg.Size.MaxFiles, cfg.Size.MaxLines, cfg.Size.MaxPackages}
+	defaults := commitSize{defaultMaxFiles, defaultMaxLines, defaultMaxPackages}
+	if trunkMode() {
+		limits.Files, limits.Lines = cfg.Trunk.MaxFiles, cfg.Trunk.MaxLines
+		defaults.Files, defaults.Lines = defaultTrunkMaxFiles, defaultTrunkMaxLines
+	}
+
+	if limits.Files == 0 {
+		limits.Files = defaults.Files
+	}
+	if limits.Lines == 0 {
+		limits.Lines = defaults.Lines
+	}
+	if limits.Packages == 0 {
+		limits.Packages = defaults.Packages
+	}
+	return limits
+}
+
+// exceeds reports whether s is above any limit that is on.
+func (s commitSize) exceeds(limits commitSize) bool {
+	return limits.Files > 0 && s.Files > limits.Files ||
+		limits.Lines > 0 && s.Lines > limits.Lines ||
+		limits.Packages > 0 && s.Packages > limits.Packages
+}
+
+// displayCommitSize warns when the staged changes are above the size
+// limits and suggests splitting them.
+func displayCommitSize(size commitSize, files []git.File) {
+	if !size.exceeds(sizeLimits()) {
+		return
+	}
+
+	fmt.Println()
+	color.Yellow("%s", tr("size.large", size.Files, size.Packages, size.Lines))
+	if n := len(groupByScope(files)); n > 1 {
+		fmt.Println(tr("size.split_hint_scopes", n))
+	} else {
+		fmt.Println(tr("size.split_hint"))
+	}
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/barisdilekci/commitz/internal/config"
+	"github.com/barisdilekci/commitz/internal/git"
+)
+
+func TestMeasureCommit(t *testing.T) {
+	files := []git.File{
+		{Path: "cmd/root.go", Additions: 10, Deletions: 2},
+		{Path: "cmd/size.go", Additions: 40},
+		{Path: "internal/git/git.go", Deletions: 3},
+		{Path: "README.md", Additions: 5},
+	}
+	want := commitSize{Files: 4, Lines: 60, Packages: 3}
+	if got := measureCommit(files); got != want {
+		t.Errorf("measureCommit() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSizeLimits(t *testing.T) {
+	defer func(s config.SizeConfig, trunk config.TrunkConfig) { cfg.Size, cfg.Trunk = s, trunk }(cfg.Size, cfg.Trunk)
+
+	tests := []struct {
+		size  config.SizeConfig
+		trunk config.TrunkConfig
+		want  commitSize
+	}{
+		{config.SizeConfig{}, config.TrunkConfig{}, commitSize{defaultMaxFiles, defaultMaxLines, defaultMaxPackages}},
+		{config.SizeConfig{MaxFiles: 40, MaxPackages: -1}, config.TrunkConfig{}, commitSize{40, defaultMaxLines, -1}},
+		{config.SizeConfig{MaxFiles: 40}, config.TrunkConfig{Enabled: true, MaxLines: 100}, commitSize{defaultTrunkMaxFiles, 100, defaultMaxPackages}},
+	}
+	for _, tt := range tests {
+		cfg.Size, cfg.Trunk = tt.size, tt.trunk
+		if got := sizeLimits(); got != tt.want {
+			t.Errorf("sizeLimits() with %+v, %+v = %+v, want %+v", tt.size, tt.trunk, got, tt.want)
+		}
+	}
+}
+
+func TestCommitSizeExceeds(t *testing.T) {
+	limits := commitSize{Files: 10, Lines: -1, Packages: 3}
+	tests := []struct {
+		size commitSize
+		want bool
+	}{
+		{commitSize{10, 5000, 3}, false},
+		{commitSize{11, 10, 1}, true},
+		{commitSize{2, 10, 4}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.size.exceeds(limits); got != tt.want {
+			t.Errorf("%+v.exceeds(%+v) = %v, want %v", tt.size, limits, got, tt.want)
+		}
+	}
+}
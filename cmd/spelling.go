@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/client9/misspell"
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+)
+
+// spellingIssue is a likely typo with its suggested correction.
+type spellingIssue struct {
+	Word       string
+	Suggestion string
+}
+
+// newSpellChecker builds the offline replacer, dropping words from the
+// project dictionary and adding project-specific corrections.
+func newSpellChecker() *misspell.Replacer {
+	r := misspell.New()
+	if len(cfg.Spelling.Words) > 0 {
+		r.RemoveRule(cfg.Spelling.Words)
+	}
+
+	var additions []string
+	for wrong, right := range cfg.Spelling.Corrections {
+		additions = append(additions, wrong, right)
+	}
+	if len(additions) > 0 {
+		r.AddRuleList(additions)
+	}
+
+	r.Compile()
+	return r
+}
+
+// checkSpelling returns the likely typos in text.
+func checkSpelling(text string) []spellingIssue {
+	var issues []spellingIssue
+	_, diffs := newSpellChecker().Replace(text)
+	for _, d := range diffs {
+		if containsFold(cfg.Spelling.Words, d.Original) {
+			continue
+		}
+		issues = append(issues, spellingIssue{Word: d.Original, Suggestion: d.Corrected})
+	}
+	return issues
+}
+
+// checkSpellingInteractive highlights typos in the message and, in
+// interactive mode, offers to apply the suggested fixes.
+func checkSpellingInteractive(message string, interactive bool) string {
+	if cfg.Spelling.Disabled {
+		return message
+	}
+
+	issues := checkSpelling(message)
+	if len(issues) == 0 {
+		return message
+	}
+
+	fmt.Println()
+	color.Yellow("%s", tr("spelling.title"))
+	for _, issue := range issues {
+		fmt.Printf("  %s → %s\n", color.RedString("%s", issue.Word), color.GreenString("%s", issue.Suggestion))
+	}
+
+	if !interactive {
+		return message
+	}
+
+	prompt := promptui.Prompt{
+		Label:     tr("prompt.apply_spelling"),
+		IsConfirm: true,
+	}
+	if result, err := runPrompt(prompt); err != nil || !isYes(result) {
+		return message
+	}
+
+	fixed, _ := newSpellChecker().Replace(message)
+	return fixed
+}
+
+func containsFold(slice []string, item string) bool {
+	for _, s := range slice {
+		if strings.EqualFold(s, item) {
+			return true
+		}
+	}
+	return false
+}
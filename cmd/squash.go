@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/message"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// squashTypeRank orders the types that decide a squashed subject; other
+// types win by how often they occur.
+var squashTypeRank = []string{"feat", "fix", "perf"}
+
+var squashCmd = &cobra.Command{
+	Use:   "squash-msg <base..head>",
+	Short: "Compose one conventional message for a branch's commits",
+	Long: `Combines the commits in a range into a single conventional message for
+git merge --squash or a pull request's squash title and body. The subject
+comes from the most significant change (a feature over a fix over other
+types), the body lists each commit, and breaking changes are kept.
+Merge and fixup!/squash! commits are left out.
+
+A single revision means that revision..HEAD.`,
+	Example: `  git merge --squash feature/login
+  commitz squash-msg main..feature/login | git commit -F -
+
+  commitz squash-msg origin/main`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		from, to, ok := strings.Cut(args[0], "...")
+		if !ok {
+			from, to, _ = strings.Cut(args[0], "..")
+		}
+		if to == "" {
+			to = "HEAD"
+		}
+
+		commits, err := historyCommits(from, to)
+		if err != nil {
+			color.Red("%s", tr("changelog.error", err))
+			os.Exit(1)
+		}
+		message, ok := squashMessage(commits)
+		if !ok {
+			color.Yellow("%s", tr("squash.empty", args[0]))
+			os.Exit(1)
+		}
+		fmt.Println(message)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(squashCmd)
+}
+
+// squashMessage builds the combined message, or reports false when no
+// commit is left to combine.
+func squashMessage(commits []historyCommit) (string, bool) {
+	var kept []historyCommit
+	for _, c := range commits {
+		if strings.HasPrefix(c.Subject, "Merge ") || hasFixupPrefix(c.Subject) {
+			continue
+		}
+		kept = append(kept, c)
+	}
+	if len(kept) == 0 {
+		return "", false
+	}
+
+	lead := dominantCommit(kept)
+	commitType, scope := lead.Type, lead.Scope
+	if commitType == "" {
+		commitType = "chore"
+	}
+	for _, c := range kept {
+		if c.Type == lead.Type && c.Scope != scope {
+			scope = ""
+		}
+	}
+
+	var header strings.Builder
+	header.WriteString(commitType)
+	if scope != "" {
+		header.WriteString("(" + scope + ")")
+	}
+	var notes []string
+	for _, c := range kept {
+		if c.Breaking && !contains(notes, c.BreakingNote) {
+			notes = append(notes, c.BreakingNote)
+		}
+	}
+	if len(notes) > 0 {
+		header.WriteString("!")
+	}
+	header.WriteString(": " + message.Imperative(lead.Subject))
+
+	message := header.String()
+	if len(kept) > 1 {
+		var body []string
+		for _, c := range kept {
+			body = append(body, "- "+squashEntry(c))
+		}
+		message += "\n\n" + strings.Join(body, "\n")
+	}
+	for _, note := range notes {
+		message += "\n\nBREAKING CHANGE: " + note
+	}
+	return message, true
+}
+
+// dominantCommit picks the commit whose subject stands for the range: the
+// first one of the highest ranked type, or of the most frequent type.
+func dominantCommit(commits []historyCommit) historyCommit {
+	for _, t := range squashTypeRank {
+		for _, c := range commits {
+			if c.Type == t {
+				return c
+			}
+		}
+	}
+
+	counts := map[string]int{}
+	lead := commits[0]
+	for _, c := range commits {
+		if c.Type == "" {
+			continue
+		}
+		counts[c.Type]++
+		if lead.Type == "" || counts[c.Type] > counts[lead.Type] {
+			lead = firstOfType(commits, c.Type)
+		}
+	}
+	return lead
+}
+
+func firstOfType(commits []historyCommit, commitType string) historyCommit {
+	for _, c := range commits {
+		if c.Type == commitType {
+			return c
+		}
+	}
+	return commits[0]
+}
+
+// squashEntry is a body line: the commit's header without the breaking
+// marker.
+func squashEntry(c historyCommit) string {
+	if c.Type == "" {
+		return c.Subject
+	}
+	return message.Build(message.Header{Type: c.Type, Scope: c.Scope, Subject: c.Subject}, message.Emoji{})
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	processStart   = time.Now()
+	profileStartup bool
+	profileMu      sync.Mutex
+	profileMarks   []profileMark
+	profileOnce    sync.Once
+)
+
+type profileMark struct {
+	Name string
+	At   time.Duration
+}
+
+// repoRoot returns the top-level directory of the current repository.
+var repoRoot = sync.OnceValues(findRepoRoot)
+
+func findRepoRoot() (string, error) {
+	out, err := gitOutput("rev-parse", "--show-toplevel")
+	return strings.TrimSpace(string(out)), err
+}
+
+// currentBranch returns the name of the checked out branch.
+var currentBranch = sync.OnceValues(readCurrentBranch)
+
+func readCurrentBranch() (string, error) {
+	out, err := gitOutput("branch", "--show-current")
+	return strings.TrimSpace(string(out)), err
+}
+
+// prefetchGitState starts the git queries the commit flow needs later, so
+// they run while the staged diff is being read.
+func prefetchGitState() {
+	go currentBranch()
+	go recentSubjects()
+	go gitConfig()
+}
+
+// markStartup records how long startup took up to the named phase.
+func markStartup(name string) {
+	if !profileStartup {
+		return
+	}
+
+	profileMu.Lock()
+	defer profileMu.Unlock()
+	profileMarks = append(profileMarks, profileMark{Name: name, At: time.Since(processStart)})
+}
+
+// reportStartupProfile prints the recorded phases once, after detection
+// and right before the type prompt or suggestion is shown.
+func reportStartupProfile() {
+	if !profileStartup {
+		return
+	}
+
+	profileOnce.Do(func() {
+		markStartup("first output")
+
+		profileMu.Lock()
+		defer profileMu.Unlock()
+
+		fmt.Fprintln(os.Stderr, tr("profile.title"))
+		var previous time.Duration
+		for _, m := range profileMarks {
+			fmt.Fprintf(os.Stderr, "  %-14s %8s  (+%s)\n", m.Name, m.At.Round(time.Microsecond), (m.At - previous).Round(time.Microsecond))
+			previous = m.At
+		}
+	})
+}
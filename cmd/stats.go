@@ -0,0 +1,304 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// maxRecordedEdits bounds how many summary edits are kept for tuning.
+const maxRecordedEdits = 50
+
+// Outcomes of a run, recorded in the personal stats.
+const (
+	outcomeCommitted = "committed"
+	outcomeDryRun    = "dry_run"
+	outcomeCancelled = "cancelled"
+)
+
+// session collects what was suggested and what the user chose during this
+// run. It stays empty when a draft was restored.
+var session struct {
+	DetectedType     string
+	SelectedType     string
+	TypeReason       string
+	SuggestedSummary string
+	Summary          string
+	// ScopeCandidates are the scopes offered, DetectedScope the one taken
+	// from the branch.
+	ScopeCandidates []string
+	DetectedScope   string
+	Scope           string
+	ScopeReason     string
+	// SuggestedMessage is what commitz would have committed without any
+	// choices or edits.
+	SuggestedMessage string
+	// Size measures the staged changes.
+	Size commitSize
+}
+
+// personalStats is stored only on this machine and never sent anywhere.
+type personalStats struct {
+	Runs            int            `json:"runs"`
+	Commits         int            `json:"commits"`
+	DryRuns         int            `json:"dry_runs"`
+	Cancelled       int            `json:"cancelled"`
+	Types           map[string]int `json:"types"`
+	TypeAccepted    int            `json:"type_accepted"`
+	TypeChanged     int            `json:"type_changed"`
+	SummaryAccepted int            `json:"summary_accepted"`
+	SummaryEdited   int            `json:"summary_edited"`
+	Edits           []summaryEdit  `json:"edits,omitempty"`
+	// Sizes sums the sizes of the measured commits, and Oversized counts
+	// those that were above the size limits.
+	Measured  int        `json:"measured"`
+	Sizes     commitSize `json:"sizes"`
+	Oversized int        `json:"oversized"`
+}
+
+type summaryEdit struct {
+	Suggested string `json:"suggested"`
+	Final     string `json:"final"`
+}
+
+var (
+	statsPersonal    bool
+	statsSuggestions bool
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show commit type statistics",
+	Long: `Shows how often each commit type was used in this repository. With
+--personal, shows your local usage statistics instead: types used, how
+often suggestions were accepted or edited and how large your commits are.
+These are stored only in ~/.local/share/commitz and never leave your
+machine.
+
+With --suggestions, reads the session log kept with session_log.enabled
+and shows how often the suggested message, header, type, scope and
+summary were committed unchanged, by detected type and by size.`,
+	Example: `  commitz stats
+  commitz stats --personal
+  commitz stats --suggestions`,
+	Run: func(cmd *cobra.Command, args []string) {
+		switch {
+		case statsSuggestions:
+			displaySuggestionStats()
+		case statsPersonal:
+			displayPersonalStats()
+		default:
+			displayRepositoryStats()
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().BoolVar(
+		&statsPersonal,
+		"personal",
+		false,
+		"Show your local usage statistics",
+	)
+
+	statsCmd.Flags().BoolVar(
+		&statsSuggestions,
+		"suggestions",
+		false,
+		"Show how much of the logged suggestions was committed unchanged",
+	)
+
+	addHistoryFilterFlags(statsCmd)
+}
+
+func statsPath() (string, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "stats.json"), nil
+}
+
+func loadPersonalStats() personalStats {
+	stats := personalStats{Types: map[string]int{}}
+
+	path, err := statsPath()
+	if err != nil {
+		return stats
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return stats
+	}
+	if err := json.Unmarshal(data, &stats); err != nil {
+		logger.Info("ignoring unreadable stats", "path", path, "error", err)
+		return personalStats{Types: map[string]int{}}
+	}
+	if stats.Types == nil {
+		stats.Types = map[string]int{}
+	}
+	return stats
+}
+
+// recordUsage adds the outcome of this run to the personal stats. Failures
+// are only logged; stats must never get in the way of committing.
+func recordUsage(outcome string) {
+	stats := loadPersonalStats()
+	stats.Runs++
+
+	switch outcome {
+	case outcomeCommitted:
+		stats.Commits++
+		if session.Size.Files > 0 {
+			stats.Measured++
+			stats.Sizes.Files += session.Size.Files
+			stats.Sizes.Lines += session.Size.Lines
+			stats.Sizes.Packages += session.Size.Packages
+			if session.Size.exceeds(sizeLimits()) {
+				stats.Oversized++
+			}
+		}
+	case outcomeDryRun:
+		stats.DryRuns++
+	case outcomeCancelled:
+		stats.Cancelled++
+	}
+
+	if session.SelectedType != "" {
+		stats.Types[session.SelectedType]++
+		if session.SelectedType == session.DetectedType {
+			stats.TypeAccepted++
+		} else {
+			stats.TypeChanged++
+		}
+	}
+
+	if session.Summary != "" {
+		if session.Summary == session.SuggestedSummary {
+			stats.SummaryAccepted++
+		} else {
+			stats.SummaryEdited++
+			stats.Edits = append(stats.Edits, summaryEdit{session.SuggestedSummary, session.Summary})
+			if len(stats.Edits) > maxRecordedEdits {
+				stats.Edits = stats.Edits[len(stats.Edits)-maxRecordedEdits:]
+			}
+		}
+	}
+
+	path, err := statsPath()
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0o755)
+	}
+	if err == nil {
+		var data []byte
+		data, err = json.MarshalIndent(stats, "", "  ")
+		if err == nil {
+			err = writeFileAtomic(path, data)
+		}
+	}
+	if err != nil {
+		logger.Info("stats not saved", "error", err)
+	}
+	recordSession(outcome)
+}
+
+func displayPersonalStats() {
+	stats := loadPersonalStats()
+	if stats.Runs == 0 {
+		fmt.Println(tr("stats.personal_empty"))
+		return
+	}
+
+	color.Cyan("%s", tr("stats.personal_title"))
+	fmt.Println(tr("stats.runs", stats.Runs, stats.Commits, stats.DryRuns, stats.Cancelled))
+	fmt.Println(tr("stats.type_acceptance", percent(stats.TypeAccepted, stats.TypeAccepted+stats.TypeChanged)))
+	fmt.Println(tr("stats.summary_acceptance", percent(stats.SummaryAccepted, stats.SummaryAccepted+stats.SummaryEdited)))
+	if n := stats.Measured; n > 0 {
+		fmt.Println(tr("stats.size", stats.Sizes.Files/n, stats.Sizes.Packages/n, stats.Sizes.Lines/n, percent(stats.Oversized, n)))
+	}
+
+	displayTypeCounts(stats.Types)
+
+	if n := len(stats.Edits); n > 0 {
+		fmt.Println()
+		fmt.Println(tr("stats.recent_edits"))
+		for _, e := range stats.Edits[max(0, n-5):] {
+			fmt.Printf("  %s → %s\n", color.New(color.Faint).Sprint(e.Suggested), e.Final)
+		}
+	}
+}
+
+// displayRepositoryStats counts conventional commit types in the history.
+func displayRepositoryStats() {
+	checkHistoryDepth()
+	out, err := gitOutput(historyFilters.logArgs("log", "--no-merges", "--format=%s")...)
+	if err != nil {
+		color.Red("%s", tr("stats.log_error", err))
+		os.Exit(1)
+	}
+
+	types := map[string]int{}
+	for _, subject := range strings.Split(string(out), "\n") {
+		if t := subjectType(subject); t != "" {
+			types[t]++
+		}
+	}
+
+	if len(types) == 0 {
+		fmt.Println(tr("stats.repo_empty"))
+		return
+	}
+	color.Cyan("%s", tr("stats.repo_title"))
+	displayTypeCounts(types)
+}
+
+// subjectType returns the conventional type of a subject, ignoring a
+// leading emoji, or "" for other subjects.
+func subjectType(subject string) string {
+	prefix := conventionalPrefix.FindString(subject)
+	if prefix == "" {
+		return ""
+	}
+	prefix = strings.TrimLeftFunc(prefix, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z')
+	})
+	end := strings.IndexAny(prefix, "(!:")
+	if end <= 0 {
+		return ""
+	}
+	return strings.ToLower(prefix[:end])
+}
+
+func displayTypeCounts(types map[string]int) {
+	names := make([]string, 0, len(types))
+	total := 0
+	for name, count := range types {
+		names = append(names, name)
+		total += count
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if types[names[i]] != types[names[j]] {
+			return types[names[i]] > types[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	for _, name := range names {
+		fmt.Printf("  %-10s %5d  %s\n", name, types[name], percent(types[name], total))
+	}
+}
+
+func percent(part, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d%%", part*100/total)
+}
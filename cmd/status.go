@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/fatih/color"
+)
+
+// hookFrameworks maps a file or directory in the repository root to the
+// hook manager it belongs to.
+var hookFrameworks = []struct {
+	Marker string
+	Name   string
+}{
+	{".husky", "husky"},
+	{"lefthook.yml", "lefthook"},
+	{"lefthook.yaml", "lefthook"},
+	{".lefthook.yml", "lefthook"},
+	{".pre-commit-config.yaml", "pre-commit"},
+	{".overcommit.yml", "overcommit"},
+}
+
+// displayStatusHeader prints where the commit will land before the
+// interactive prompts: branch, ahead/behind counts, staged files and the
+// hook manager that will run.
+func displayStatusHeader(files []git.File) {
+	var parts []string
+
+	branch, err := currentBranch()
+	if err != nil || branch == "" {
+		branch = tr("status.detached")
+	}
+	parts = append(parts, "⎇ "+branch)
+	if ahead, behind, ok := aheadBehind(); ok {
+		parts = append(parts, fmt.Sprintf("↑%d ↓%d", ahead, behind))
+	} else {
+		parts = append(parts, tr("status.no_upstream"))
+	}
+
+	parts = append(parts, tr("status.staged", len(files)))
+	if framework := hookFramework(); framework != "" {
+		parts = append(parts, tr("status.hooks", framework))
+	} else {
+		parts = append(parts, tr("status.no_hooks"))
+	}
+
+	fmt.Println(color.HiBlackString("%s", strings.Join(parts, " · ")))
+}
+
+// aheadBehind counts the commits HEAD is ahead of and behind its upstream.
+// The last result is false without an upstream.
+func aheadBehind() (int, int, bool) {
+	out, err := gitOutput("rev-list", "--left-right", "--count", "HEAD...@{upstream}")
+	if err != nil {
+		return 0, 0, false
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 {
+		return 0, 0, false
+	}
+	ahead, err1 := strconv.Atoi(fields[0])
+	behind, err2 := strconv.Atoi(fields[1])
+	return ahead, behind, err1 == nil && err2 == nil
+}
+
+// hookFramework names the hook manager set up in the repository, or the
+// plain git hooks that are installed, or "" when there are none.
+func hookFramework() string {
+	if root, err := repoRoot(); err == nil {
+		for _, f := range hookFrameworks {
+			if _, err := os.Stat(filepath.Join(root, f.Marker)); err == nil {
+				return f.Name
+			}
+		}
+	}
+	if _, hooks, _ := installedHooks(); len(hooks) > 0 {
+		return strings.Join(hooks, ", ")
+	}
+	return ""
+}
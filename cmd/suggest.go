@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/detect"
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/barisdilekci/commitz/internal/message"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// suggestProtocolVersion is raised only when a field of suggestion changes
+// meaning or is removed. New fields do not raise it.
+const suggestProtocolVersion = 1
+
+// exitNothingStaged is the exit status of commitz suggest when there is
+// nothing to suggest a message for.
+const exitNothingStaged = 2
+
+var suggestFormat string
+
+// suggestion is the JSON output of commitz suggest, read by editor
+// extensions. See docs/editor-integration.md.
+type suggestion struct {
+	Version  int    `json:"version"`
+	Type     string `json:"type"`
+	Scope    string `json:"scope"`
+	Subject  string `json:"subject"`
+	Body     string `json:"body"`
+	Breaking bool   `json:"breaking"`
+	Message  string `json:"message"`
+	Score    int    `json:"score"`
+	Reasons  struct {
+		Type  string `json:"type"`
+		Scope string `json:"scope"`
+	} `json:"reasons"`
+	Files []suggestedFile `json:"files"`
+}
+
+type suggestedFile struct {
+	Path      string `json:"path"`
+	OldPath   string `json:"old_path,omitempty"`
+	Status    string `json:"status"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+var suggestCmd = &cobra.Command{
+	Use:   "suggest",
+	Short: "Print the suggested message for the staged changes without committing",
+	Long: `Analyzes the staged changes and prints the message commitz would
+suggest, without prompting or committing. With --format json it prints the
+type, scope, subject, body and the reasons for them as one JSON object.
+
+This is the entry point for editor integrations: only the result goes to
+stdout, warnings go to stderr, and the exit status is 2 when nothing is
+staged. The --type, --scope and --emoji flags apply as for commitz itself.`,
+	Example: `  commitz suggest
+  commitz suggest --format json
+  commitz suggest -t fix --format json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if suggestFormat != "text" && suggestFormat != "json" {
+			color.Red("%s", tr("suggest.format", suggestFormat))
+			os.Exit(1)
+		}
+		if interactive {
+			color.Red("%s", tr("suggest.interactive"))
+			os.Exit(1)
+		}
+		runSuggest()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(suggestCmd)
+
+	suggestCmd.Flags().StringVar(
+		&suggestFormat,
+		"format",
+		"text",
+		"Output format: text or json",
+	)
+}
+
+func runSuggest() {
+	// Nothing is committed, asked or kept as a draft
+	quiet, dryRun = true, true
+	initQuiet()
+	quietOutput()
+	ensureRepository(false)
+
+	s, ok, err := stagedSuggestion()
+	if err != nil {
+		color.Red("%s", tr("diff.error", err))
+		os.Exit(1)
+	}
+	if !ok {
+		color.Yellow("%s", tr("diff.empty"))
+		os.Exit(exitNothingStaged)
+	}
+
+	if suggestFormat == "text" {
+		fmt.Fprintln(resultOutput, s.Message)
+		return
+	}
+	encoder := json.NewEncoder(resultOutput)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(s); err != nil {
+		color.Red("%s", tr("suggest.write_error", err))
+		os.Exit(1)
+	}
+}
+
+// stagedSuggestion composes a message for the staged changes without
+// asking anything. It reports false when nothing is staged.
+func stagedSuggestion() (suggestion, bool, error) {
+	files, err := loadStagedFiles()
+	if err != nil || len(files) == 0 {
+		return suggestion{}, false, err
+	}
+
+	classifyFiles(files)
+	if len(cfg.Analysis.Ignore) > 0 {
+		files = filterIgnoredFiles(files)
+	}
+	message := composeMessage(detect.DiffText(files), files)
+	return newSuggestion(message, files), true, nil
+}
+
+// newSuggestion describes message. Type, scope and subject come from its
+// header, or from what was detected when a template changed the header.
+func newSuggestion(msg string, files []git.File) suggestion {
+	s := suggestion{
+		Version: suggestProtocolVersion,
+		Type:    session.SelectedType,
+		Scope:   session.Scope,
+		Message: msg,
+		Score:   scoreMessage(msg, changedLineCount(files)).Total,
+		Files:   []suggestedFile{},
+	}
+	s.Reasons.Type = session.TypeReason
+	s.Reasons.Scope = session.ScopeReason
+
+	header, body, _ := strings.Cut(msg, "\n")
+	s.Subject = header
+	s.Body = strings.TrimSpace(body)
+	if h, ok := message.ParseHeader(header); ok {
+		s.Type, s.Scope, s.Subject, s.Breaking = h.Type, h.Scope, h.Subject, h.Breaking
+	}
+	if len(message.FooterValues(message.ParseFooters(msg), "BREAKING CHANGE")) > 0 {
+		s.Breaking = true
+	}
+
+	for _, f := range files {
+		file := suggestedFile{Path: f.Path, Status: "modified", Additions: f.Additions, Deletions: f.Deletions}
+		switch {
+		case f.IsNew:
+			file.Status = "added"
+		case f.Deleted:
+			file.Status = "deleted"
+		case f.OldPath != "" && f.OldPath != f.Path:
+			file.Status, file.OldPath = "renamed", f.OldPath
+		}
+		s.Files = append(s.Files, file)
+	}
+	return s
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/barisdilekci/commitz/internal/git"
+)
+
+func TestNewSuggestion(t *testing.T) {
+	message := "✨ feat(api): drop the v1 routes\n\n- remove api/v1.go\n\nBREAKING CHANGE: v1 clients must move to v2"
+	files := []git.File{
+		{Path: "api/v1.go", OldPath: "api/v1.go", Deleted: true, Deletions: 40},
+		{Path: "api/routes.go", OldPath: "api/router.go", Additions: 2, Deletions: 1},
+		{Path: "api/v2.go", IsNew: true, Additions: 12},
+	}
+
+	s := newSuggestion(message, files)
+	if s.Type != "feat" || s.Scope != "api" || s.Subject != "drop the v1 routes" {
+		t.Errorf("header = %q %q %q, want feat api %q", s.Type, s.Scope, s.Subject, "drop the v1 routes")
+	}
+	if !s.Breaking {
+		t.Error("BREAKING CHANGE footer not reported as breaking")
+	}
+	if want := "- remove api/v1.go\n\nBREAKING CHANGE: v1 clients must move to v2"; s.Body != want {
+		t.Errorf("body = %q, want %q", s.Body, want)
+	}
+
+	statuses := []string{"deleted", "renamed", "added"}
+	for i, f := range s.Files {
+		if f.Status != statuses[i] {
+			t.Errorf("%s: status %q, want %q", f.Path, f.Status, statuses[i])
+		}
+	}
+	if s.Files[0].OldPath != "" || s.Files[1].OldPath != "api/router.go" {
+		t.Errorf("old paths = %q, %q", s.Files[0].OldPath, s.Files[1].OldPath)
+	}
+}
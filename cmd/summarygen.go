@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/barisdilekci/commitz/internal/message"
+	"github.com/barisdilekci/commitz/internal/summary"
+	"github.com/fatih/color"
+)
+
+// summaryCommandTimeout bounds a summary.command run.
+const summaryCommandTimeout = 30 * time.Second
+
+// summaryGenerators are the generators summary.generators can name. New
+// providers register here; the command code only calls resolveSummary.
+var summaryGenerators = map[string]func() summary.Generator{
+	"heuristic": func() summary.Generator { return summary.Heuristic{Git: gitOutput} },
+	"template":  func() summary.Generator { return summary.Template{Text: cfg.Summary.Template} },
+	"command":   func() summary.Generator { return commandGenerator{command: cfg.Summary.Command} },
+}
+
+// summaryChain returns the configured generators in order, always ending
+// with the heuristics so there is a suggestion.
+func summaryChain() []summary.Generator {
+	var chain []summary.Generator
+	for _, name := range cfg.Summary.Generators {
+		factory, ok := summaryGenerators[strings.ToLower(name)]
+		if !ok {
+			if generator, ok := pluginSummaryGenerator(name); ok {
+				chain = append(chain, generator)
+				continue
+			}
+			color.Yellow("%s", tr("summary.unknown_generator", name))
+			continue
+		}
+		// The command may well call a hosted model
+		if strings.EqualFold(name, "command") && skipOffline("offline.summary_command") {
+			continue
+		}
+		chain = append(chain, factory())
+	}
+	if len(chain) == 0 || chain[len(chain)-1].Name() != "heuristic" {
+		chain = append(chain, summary.Heuristic{Git: gitOutput})
+	}
+	return chain
+}
+
+// resolveSummary asks each generator in turn and returns the first
+// summary. Failing generators are logged and skipped. A diff with likely
+// credentials never leaves the process: only the built-in generators see
+// it.
+func resolveSummary(diff string, files []git.File, commitType string) string {
+	req := summary.Request{Diff: diff, Files: files, Type: commitType}
+	chain := summaryChain()
+	if strings.ToLower(cfg.Secrets.Mode) != secretsOff && len(scanSecrets(files)) > 0 {
+		chain = builtinGenerators(chain)
+	}
+	for _, generator := range chain {
+		suggested, err := generator.Summary(req)
+		if err != nil {
+			logger.Info("summary generator failed", "generator", generator.Name(), "error", err)
+			continue
+		}
+		if suggested = strings.TrimSpace(suggested); suggested != "" {
+			logger.Info("summary generated", "generator", generator.Name(), "summary", suggested)
+			return suggested
+		}
+	}
+	return message.Text("summary.default")
+}
+
+// builtinGenerators drops the generators that hand the diff to another
+// program.
+func builtinGenerators(chain []summary.Generator) []summary.Generator {
+	var builtin []summary.Generator
+	for _, generator := range chain {
+		switch generator.(type) {
+		case summary.Heuristic, summary.Template:
+			builtin = append(builtin, generator)
+		default:
+			logger.Info("summary generator skipped for likely secrets", "generator", generator.Name())
+		}
+	}
+	return builtin
+}
+
+// commandGenerator runs summary.command with the staged diff on stdin and
+// takes the first line it prints, like a plugin.
+type commandGenerator struct {
+	command string
+}
+
+func (commandGenerator) Name() string { return "command" }
+
+func (g commandGenerator) Summary(req summary.Request) (string, error) {
+	if g.command == "" {
+		return "", nil
+	}
+	if !trustCommand(g.command) {
+		return "", errors.New(tr("template.untrusted", g.command))
+	}
+
+	dir, err := repoRoot()
+	if err != nil {
+		dir = "."
+	}
+	ctx, cancel := context.WithTimeout(rootCtx, summaryCommandTimeout)
+	defer cancel()
+	cmd := shellCommand(ctx, g.command)
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(req.Diff)
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "COMMITZ_TYPE="+req.Type)
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", g.command, err)
+	}
+	line, _, _ := strings.Cut(string(out), "\n")
+	return strings.TrimSpace(line), nil
+}
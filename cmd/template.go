@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/barisdilekci/commitz/internal/message"
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+)
+
+// templateCommandTimeout bounds each {{ cmd }} call.
+const templateCommandTimeout = 10 * time.Second
+
+// templateCacheFile keeps {{ cmd }} output between runs. It lives in the git
+// directory, so the cache is per repository.
+const templateCacheFile = "commitz-template-cache.json"
+
+// messageTemplateData is what template.text can refer to.
+type messageTemplateData struct {
+	// Message is the generated message, including body and description.
+	Message string
+	Header  string
+	Body    string
+	Type    string
+	Scope   string
+	Subject string
+	Branch  string
+	// Ticket is the ticket key the branch names, if any.
+	Ticket string
+}
+
+type cachedOutput struct {
+	Output string    `json:"output"`
+	Saved  time.Time `json:"saved"`
+}
+
+// templateOutputs caches {{ cmd }} output for the current run.
+var templateOutputs = map[string]string{}
+
+// applyMessageTemplate renders template.text with the generated message. On
+// any error the message is returned unchanged.
+func applyMessageTemplate(msg string) string {
+	text := cfg.Template.Text
+	if text == "" {
+		return msg
+	}
+
+	tmpl, err := template.New("template.text").
+		Option("missingkey=error").
+		Funcs(template.FuncMap{"cmd": templateCommand}).
+		Parse(text)
+	if err != nil {
+		color.Yellow("%s", tr("template.error", err))
+		return msg
+	}
+
+	header, body, _ := strings.Cut(msg, "\n")
+	data := messageTemplateData{
+		Message: msg,
+		Header:  header,
+		Body:    strings.TrimSpace(body),
+	}
+	if h, ok := message.ParseHeader(header); ok {
+		data.Type, data.Scope, data.Subject = h.Type, h.Scope, h.Subject
+	}
+	data.Branch, _ = currentBranch()
+	data.Ticket, _ = branchTicketKey()
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		color.Yellow("%s", tr("template.error", err))
+		return msg
+	}
+	return strings.TrimSpace(out.String())
+}
+
+// templateCommand runs a shell command from the template and returns its
+// output without the trailing newline. Commands run from the repository
+// root, only after the user trusted them, and their output is reused for
+// template.cache_ttl.
+func templateCommand(command string) (string, error) {
+	if output, ok := templateOutputs[command]; ok {
+		return output, nil
+	}
+
+	ttl, err := parseCacheTTL()
+	if err != nil {
+		return "", err
+	}
+	cache := loadTemplateCache()
+	if c, ok := cache[command]; ok && ttl > 0 && time.Since(c.Saved) < ttl {
+		logger.Debug("template command cached", "command", command)
+		templateOutputs[command] = c.Output
+		return c.Output, nil
+	}
+
+	if !trustCommand(command) {
+		return "", errors.New(tr("template.untrusted", command))
+	}
+
+	dir, err := repoRoot()
+	if err != nil {
+		dir = "."
+	}
+	ctx, cancel := context.WithTimeout(rootCtx, templateCommandTimeout)
+	defer cancel()
+	cmd := shellCommand(ctx, command)
+	cmd.Dir = dir
+	cmd.Stderr = os.Stderr
+
+	start := time.Now()
+	out, err := cmd.Output()
+	logger.Debug("template command", "command", command, "duration", time.Since(start), "error", err)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", command, err)
+	}
+
+	output := strings.TrimRight(string(out), "\r\n")
+	templateOutputs[command] = output
+	if ttl > 0 {
+		cache[command] = cachedOutput{Output: output, Saved: time.Now()}
+		saveTemplateCache(cache)
+	}
+	return output, nil
+}
+
+func parseCacheTTL() (time.Duration, error) {
+	if cfg.Template.CacheTTL == "" {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(cfg.Template.CacheTTL)
+	if err != nil {
+		return 0, fmt.Errorf("template.cache_ttl: %w", err)
+	}
+	return ttl, nil
+}
+
+func templateCachePath() string {
+	out, err := gitOutput("rev-parse", "--git-path", templateCacheFile)
+	if err != nil {
+		return templateCacheFile
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func loadTemplateCache() map[string]cachedOutput {
+	cache := map[string]cachedOutput{}
+	data, err := os.ReadFile(templateCachePath())
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		logger.Info("ignoring unreadable template cache", "error", err)
+		return map[string]cachedOutput{}
+	}
+	return cache
+}
+
+func saveTemplateCache(cache map[string]cachedOutput) {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := writeFileAtomic(templateCachePath(), data); err != nil {
+		logger.Info("template cache not saved", "error", err)
+	}
+}
+
+// trustCommand asks once per repository before running a command from the
+// config, since a cloned repository's .commitz.yaml could run anything.
+// Answers are remembered in the data directory. With --yes, untrusted
+// commands are refused rather than trusted.
+func trustCommand(command string) bool {
+	root, err := repoRoot()
+	if err != nil {
+		root = "."
+	}
+	trusted := loadTrustedCommands()
+	if contains(trusted[root], command) {
+		return true
+	}
+	if assumeYes {
+		return false
+	}
+
+	var accepted bool
+	fmt.Println()
+	color.Yellow("%s", tr("template.trust_title"))
+	fmt.Printf("  %s\n", color.CyanString("%s", command))
+	if interactive {
+		prompt := promptui.Prompt{
+			Label:     tr("template.trust_prompt"),
+			IsConfirm: true,
+		}
+		result, err := runPrompt(prompt)
+		accepted = err == nil && isYes(result)
+	} else {
+		fmt.Print(tr("template.trust_prompt_plain"))
+		answer, _ := readLine()
+		accepted = isYes(answer)
+	}
+	if !accepted {
+		return false
+	}
+
+	trusted[root] = append(trusted[root], command)
+	saveTrustedCommands(trusted)
+	return true
+}
+
+func trustedCommandsPath() (string, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "trusted-commands.json"), nil
+}
+
+// loadTrustedCommands returns the trusted commands by repository root.
+func loadTrustedCommands() map[string][]string {
+	trusted := map[string][]string{}
+	path, err := trustedCommandsPath()
+	if err != nil {
+		return trusted
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return trusted
+	}
+	if err := json.Unmarshal(data, &trusted); err != nil {
+		logger.Info("ignoring unreadable trusted commands", "path", path, "error", err)
+		return map[string][]string{}
+	}
+	return trusted
+}
+
+func saveTrustedCommands(trusted map[string][]string) {
+	path, err := trustedCommandsPath()
+	if err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(trusted, "", "  ")
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0o755)
+	}
+	if err == nil {
+		err = writeFileAtomic(path, data)
+	}
+	if err != nil {
+		logger.Info("trusted commands not saved", "path", path, "error", err)
+	}
+}
@@ -0,0 +1,4 @@
+package cache
+
+// Legacy is kept for old callers.
+func Legacy() {}
@@ -0,0 +1,17 @@
+package cache
+
+import "sync"
+
+// Cache holds values by key.
+type Cache struct {
+	mu    sync.Mutex
+	items map[string]string
+}
+
+// Get returns the value stored for key.
+func (c *Cache) Get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.items[key]
+	return v, ok
+}
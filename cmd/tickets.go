@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/message"
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+)
+
+// defaultTicketFooter is the footer a missing ticket is added as.
+const defaultTicketFooter = "Refs"
+
+var (
+	// ticketKeyPattern matches a whole tracker key such as PROJ-123.
+	ticketKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]+-\d+$`)
+	// ticketNumberPattern matches a whole issue number such as #42 or 42.
+	ticketNumberPattern = regexp.MustCompile(`^#?(\d+)$`)
+)
+
+// ticketRequiredBranch returns the current branch when tickets.required
+// covers it.
+func ticketRequiredBranch() (string, bool) {
+	if len(cfg.Tickets.Required) == 0 {
+		return "", false
+	}
+	branch, err := currentBranch()
+	if err != nil || branch == "" {
+		return "", false
+	}
+	for _, pattern := range cfg.Tickets.Required {
+		if ok, _ := path.Match(pattern, branch); ok {
+			return branch, true
+		}
+	}
+	return "", false
+}
+
+// ticketIssues checks that a message committed on a branch covered by
+// tickets.required references a ticket.
+func ticketIssues(message string) []lintIssue {
+	if exemptionMode(message) == exemptSkip {
+		return nil
+	}
+	branch, ok := ticketRequiredBranch()
+	if !ok || ticketPattern.MatchString(message) {
+		return nil
+	}
+	return []lintIssue{{Rule: "ticket-required", Message: tr("tickets.missing", branch)}}
+}
+
+// requireTicket adds the ticket the branch is named after when a message
+// on a covered branch references none, or else asks for one. Without
+// prompts the commit is refused instead.
+func requireTicket(msg string, interactive bool) string {
+	branch, ok := ticketRequiredBranch()
+	if !ok || ticketPattern.MatchString(msg) {
+		return msg
+	}
+
+	key := cfg.Tickets.Footer
+	if key == "" {
+		key = defaultTicketFooter
+	}
+	if ticket, ok := branchTicketKey(); ok {
+		if ref := ticketReference(ticket); ref != "" {
+			fmt.Println(color.GreenString("%s", tr("tickets.added", key, ref)))
+			return message.SetFooter(msg, key, ref)
+		}
+	}
+
+	if assumeYes {
+		displayLintIssues(tr("tickets.title"), ticketIssues(msg))
+		os.Exit(1)
+	}
+
+	ticket, ok := askTicket(branch, interactive)
+	if !ok {
+		color.Yellow("%s", tr("commit.cancelled"))
+		os.Exit(1)
+	}
+	return message.SetFooter(msg, key, ticket)
+}
+
+func askTicket(branch string, interactive bool) (string, bool) {
+	validate := func(input string) error {
+		if ticketReference(input) == "" {
+			return errors.New(tr("tickets.invalid"))
+		}
+		return nil
+	}
+
+	if interactive {
+		prompt := promptui.Prompt{
+			Label:    tr("tickets.prompt", branch),
+			Validate: validate,
+		}
+		result, err := runPrompt(prompt)
+		return ticketReference(result), err == nil
+	}
+
+	for {
+		fmt.Print(tr("tickets.prompt_plain", branch))
+		answer, err := readLine()
+		if err != nil {
+			return "", false
+		}
+		if err := validate(answer); err != nil {
+			color.Yellow("%s", err.Error())
+			continue
+		}
+		return ticketReference(answer), true
+	}
+}
+
+// ticketReference writes a ticket the way ticketPattern finds it in a
+// message: keys in upper case, as branch names are often lowercased, and
+// issue numbers with "#". It returns "" for anything else.
+func ticketReference(ticket string) string {
+	ticket = strings.TrimSpace(ticket)
+	if m := ticketNumberPattern.FindStringSubmatch(ticket); m != nil {
+		return "#" + m[1]
+	}
+	if key := strings.ToUpper(ticket); ticketKeyPattern.MatchString(key) {
+		return key
+	}
+	return ""
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/barisdilekci/commitz/internal/config"
+)
+
+func TestTicketReference(t *testing.T) {
+	tests := map[string]string{
+		"PROJ-123":   "PROJ-123",
+		" proj-42 ":  "PROJ-42",
+		"#42":        "#42",
+		"42":         "#42",
+		"PROJ":       "",
+		"#":          "",
+		"see PROJ-1": "",
+	}
+	for input, want := range tests {
+		if got := ticketReference(input); got != want {
+			t.Errorf("ticketReference(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestTicketIssues(t *testing.T) {
+	defer func(required []string, b func() (string, error)) {
+		cfg.Tickets.Required, currentBranch = required, b
+	}(cfg.Tickets.Required, currentBranch)
+	cfg.Tickets.Required = []string{"release/*", "main"}
+
+	tests := []struct {
+		branch, message string
+		want            bool
+	}{
+		{"release/2.0", "fix: correct the version", true},
+		{"release/2.0", "fix: correct the version\n\nRefs: PROJ-12", false},
+		{"release/2.0", "fix: correct the version (#12)", false},
+		{"main", "docs: update the readme", true},
+		{"feature/login", "feat: add login", false},
+		{"release/2.0", "Merge branch 'main' into release/2.0", false},
+	}
+	for _, tt := range tests {
+		currentBranch = func() (string, error) { return tt.branch, nil }
+		if got := len(ticketIssues(tt.message)) > 0; got != tt.want {
+			t.Errorf("%s %q: issue %v, want %v", tt.branch, tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestRequireTicketFromBranch(t *testing.T) {
+	defer func(tickets config.TicketsConfig, branch config.BranchConfig, b func() (string, error)) {
+		cfg.Tickets, cfg.Branch, currentBranch = tickets, branch, b
+	}(cfg.Tickets, cfg.Branch, currentBranch)
+	cfg.Tickets = config.TicketsConfig{Required: []string{"release/*"}, Footer: "Issue"}
+	cfg.Branch.Patterns = []string{`^release/(?P<ticket>[a-z]+-\d+)-`}
+	currentBranch = func() (string, error) { return "release/proj-7-hotfix", nil }
+
+	got := requireTicket("fix: restore login", false)
+	if want := "fix: restore login\n\nIssue: PROJ-7"; got != want {
+		t.Errorf("requireTicket() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/git"
+)
+
+// Defaults for trunk.max_files and trunk.max_lines, which replace the
+// size limits in trunk mode.
+const (
+	defaultTrunkMaxFiles = 10
+	defaultTrunkMaxLines = 300
+)
+
+// trunkMode reports whether trunk.enabled is set. On a shared main branch
+// the branch name says nothing, so the scope comes from the staged paths.
+func trunkMode() bool {
+	return cfg.Trunk.Enabled
+}
+
+// explainScopeFromPaths suggests the scope most staged files share and
+// returns the scopes of all of them, most files first.
+func explainScopeFromPaths(files []git.File) (string, string, []string) {
+	groups := groupByScope(files)
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i].Files) > len(groups[j].Files)
+	})
+
+	var top scopeGroup
+	var scopes []string
+	for _, g := range groups {
+		if g.Scope == "" {
+			continue
+		}
+		if scopes == nil {
+			top = g
+		}
+		scopes = append(scopes, g.Scope)
+	}
+
+	switch {
+	case scopes == nil:
+		return "", tr("why.paths_no_scope"), nil
+	case len(top.Files) == len(files):
+		return top.Scope, tr("why.paths_scope", top.Scope), scopes
+	}
+	return top.Scope, tr("why.paths_scope_most", len(top.Files), len(files), top.Scope), scopes
+}
+
+// askScopePlain asks for the scope outside interactive mode, which trunk
+// mode always does as no branch suggests one. Enter keeps the suggestion
+// and "-" means no scope.
+func askScopePlain(suggested string, scopes []string) string {
+	if len(scopes) > 1 {
+		fmt.Println(tr("trunk.scope_candidates", strings.Join(scopes, ", ")))
+	}
+	if suggested == "" {
+		fmt.Print(tr("trunk.scope_prompt_plain_none"))
+	} else {
+		fmt.Print(tr("trunk.scope_prompt_plain", suggested))
+	}
+
+	answer, err := readLine()
+	answer = strings.TrimSpace(answer)
+	switch {
+	case err != nil || answer == "":
+		return suggested
+	case answer == "-":
+		return ""
+	}
+	return answer
+}
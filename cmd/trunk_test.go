@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/barisdilekci/commitz/internal/git"
+)
+
+func TestExplainScopeFromPaths(t *testing.T) {
+	tests := []struct {
+		paths  []string
+		scope  string
+		scopes []string
+	}{
+		{[]string{"internal/cache/cache.go", "internal/cache/lru.go"}, "cache", []string{"cache"}},
+		{[]string{"docs/a.md", "api/user.go", "api/order.go", "README.md"}, "api", []string{"api", "docs"}},
+		{[]string{"README.md", "go.mod"}, "", nil},
+	}
+	for _, tt := range tests {
+		var files []git.File
+		for _, p := range tt.paths {
+			files = append(files, git.File{Path: p})
+		}
+		scope, _, scopes := explainScopeFromPaths(files)
+		if scope != tt.scope || !slices.Equal(scopes, tt.scopes) {
+			t.Errorf("explainScopeFromPaths(%v) = %q, %v; want %q, %v", tt.paths, scope, scopes, tt.scope, tt.scopes)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package cmd
+
+// CommitType is a conventional commit type offered in the type selector.
+type CommitType struct {
+	Type        string
+	Emoji       string
+	Description string
+}
+
+var commitTypes = []CommitType{
+	{"feat", "✨", "A new feature"},
+	{"fix", "🐛", "A bug fix"},
+	{"docs", "📝", "Documentation only changes"},
+	{"style", "💄", "Changes that don't affect code meaning"},
+	{"refactor", "♻️", "Code change that neither fixes a bug nor adds a feature"},
+	{"perf", "⚡", "Performance improvements"},
+	{"test", "✅", "Adding or correcting tests"},
+	{"build", "🔨", "Changes to build system or dependencies"},
+	{"ci", "👷", "Changes to CI configuration"},
+	{"chore", "🧹", "Other changes that don't modify src or test files"},
+}
+
+// localizedCommitTypes returns the commit types with translated descriptions.
+func localizedCommitTypes() []CommitType {
+	available := availableCommitTypes()
+	types := make([]CommitType, len(available))
+	for i, ct := range available {
+		types[i] = ct
+		if description := tr("type." + ct.Type); description != "type."+ct.Type {
+			types[i].Description = description
+		}
+	}
+	return types
+}
+
+func commitTypeIndex(types []CommitType, commitType string) int {
+	for i, ct := range types {
+		if ct.Type == commitType {
+			return i
+		}
+	}
+	return 0
+}
+
+func getEmojiForType(commitType string) string {
+	if !useEmoji {
+		return ""
+	}
+	if useGitmoji {
+		if g, ok := typeGitmoji(commitType); ok {
+			return g.Emoji + " "
+		}
+	}
+
+	for _, ct := range availableCommitTypes() {
+		if ct.Type == commitType {
+			return ct.Emoji + " "
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	runtimedebug "runtime/debug"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Set at build time, for example with
+//
+//	go build -ldflags "-X github.com/barisdilekci/commitz/cmd.version=v1.2.0
+//	  -X github.com/barisdilekci/commitz/cmd.commit=$(git rev-parse HEAD)
+//	  -X github.com/barisdilekci/commitz/cmd.buildDate=$(date -u +%FT%TZ)"
+//
+// Builds without them, such as go install, fall back to the module and VCS
+// information Go embeds.
+var (
+	version   = "dev"
+	commit    = ""
+	buildDate = ""
+)
+
+const (
+	// latestReleaseURL is the GitHub API endpoint of the newest release.
+	latestReleaseURL = "https://api.github.com/repos/BarisDilekci/commitz/releases/latest"
+	// updateCheckFile caches the last update check in the data directory.
+	updateCheckFile = "update-check.json"
+	// updateCheckInterval is how often update_check asks GitHub.
+	updateCheckInterval = 24 * time.Hour
+	// updateCheckTimeout keeps the check after a commit from holding it up.
+	updateCheckTimeout = 3 * time.Second
+)
+
+var (
+	versionCheck bool
+	versionScope string
+)
+
+// buildDetails describes the running binary.
+type buildDetails struct {
+	Version   string
+	Commit    string
+	Date      string
+	GoVersion string
+	Platform  string
+}
+
+// updateCheck is the result of the last update check.
+type updateCheck struct {
+	Checked time.Time `json:"checked"`
+	Latest  string    `json:"latest"`
+	URL     string    `json:"url"`
+}
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Show the version, commit and build date",
+	Long: `Shows the commitz version, the commit and date it was built from and the
+Go version. With --check, also asks GitHub whether a newer release exists.
+
+With --scope, shows the current and the next version of a monorepo
+package instead, as commitz release --scope calculates them: only commits
+touching that path or using its name as their scope count, and its tags
+look like auth/v1.3.0. Nothing is tagged.
+
+Set update_check: true in the config to be told about new releases after
+a commit, checking at most once a day.`,
+	Example: `  commitz version
+  commitz version --check
+  commitz version --scope pkg/auth
+  commitz --version`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if versionScope != "" {
+			checkHistoryDepth()
+			showReleasePlan(versionScope, "", "")
+			return
+		}
+		displayVersion()
+		if versionCheck {
+			checkLatestVersion()
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+	rootCmd.Version = buildInfo().Version
+
+	versionCmd.Flags().BoolVar(
+		&versionCheck,
+		"check",
+		false,
+		"Check GitHub for a newer release",
+	)
+
+	versionCmd.Flags().StringVar(
+		&versionScope,
+		"scope",
+		"",
+		"Show the current and next version of a monorepo package, e.g. pkg/auth",
+	)
+}
+
+// buildInfo combines the values set at build time with what Go recorded
+// in the binary.
+func buildInfo() buildDetails {
+	details := buildDetails{
+		Version:   version,
+		Commit:    commit,
+		Date:      buildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	info, ok := runtimedebug.ReadBuildInfo()
+	if !ok {
+		return details
+	}
+	if details.Version == "dev" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		details.Version = info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		switch {
+		case setting.Key == "vcs.revision" && details.Commit == "":
+			details.Commit = setting.Value
+		case setting.Key == "vcs.time" && details.Date == "":
+			details.Date = setting.Value
+		case setting.Key == "vcs.modified" && setting.Value == "true" && details.Commit != "":
+			details.Commit += "-dirty"
+		}
+	}
+	return details
+}
+
+func displayVersion() {
+	details := buildInfo()
+	unknown := tr("version.unknown")
+	orUnknown := func(s string) string {
+		if s == "" {
+			return unknown
+		}
+		return s
+	}
+
+	fmt.Printf("commitz %s\n", details.Version)
+	fmt.Printf("  %-9s %s\n", "commit", orUnknown(details.Commit))
+	fmt.Printf("  %-9s %s\n", "built", orUnknown(details.Date))
+	fmt.Printf("  %-9s %s\n", "go", details.GoVersion)
+	fmt.Printf("  %-9s %s\n", "platform", details.Platform)
+}
+
+// checkLatestVersion asks GitHub for the newest release and says whether
+// this build is older.
+func checkLatestVersion() {
+	if skipOffline("offline.update_check") {
+		os.Exit(1)
+	}
+	ctx, cancel := context.WithTimeout(rootCtx, githubTimeout)
+	defer cancel()
+	latest, url, err := latestRelease(ctx)
+	if err != nil {
+		color.Red("%s", tr("version.check_failed", err))
+		os.Exit(1)
+	}
+	saveUpdateCheck(updateCheck{Checked: time.Now(), Latest: latest, URL: url})
+
+	fmt.Println()
+	current := buildInfo().Version
+	switch {
+	case newerVersion(latest, current):
+		color.Yellow("%s", tr("version.update_available", latest, current, url))
+	case !isRelease(current):
+		fmt.Println(tr("version.dev_build", latest))
+	default:
+		color.Green("%s", tr("version.up_to_date"))
+	}
+}
+
+// notifyUpdate tells the user about a newer release when update_check is
+// on. GitHub is asked at most once per updateCheckInterval; failures are
+// only logged.
+func notifyUpdate() {
+	current := buildInfo().Version
+	if !cfg.UpdateCheck || !isRelease(current) {
+		return
+	}
+
+	last := loadUpdateCheck()
+	if time.Since(last.Checked) >= updateCheckInterval && !skipOffline("offline.update_check") {
+		ctx, cancel := context.WithTimeout(rootCtx, updateCheckTimeout)
+		defer cancel()
+		latest, url, err := latestRelease(ctx)
+		if err != nil {
+			logger.Info("update check failed", "error", err)
+			return
+		}
+		last = updateCheck{Checked: time.Now(), Latest: latest, URL: url}
+		saveUpdateCheck(last)
+	}
+
+	if newerVersion(last.Latest, current) {
+		fmt.Println()
+		color.Yellow("%s", tr("version.update_available", last.Latest, current, last.URL))
+	}
+}
+
+// latestRelease returns the tag and web URL of the newest commitz release.
+func latestRelease(ctx context.Context) (string, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, latestReleaseURL, nil)
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	client, err := httpClient()
+	if err != nil {
+		return "", "", err
+	}
+	logger.Info("checking for a newer release", "url", latestReleaseURL)
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("%s", resp.Status)
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&release); err != nil {
+		return "", "", err
+	}
+	return release.TagName, release.HTMLURL, nil
+}
+
+// isRelease reports whether v is a released version rather than a
+// development build.
+func isRelease(v string) bool {
+	parsed, ok := parseSemver(v)
+	return ok && parsed.Pre == ""
+}
+
+// newerVersion reports whether latest is a higher version than current.
+// Versions that do not parse, such as "dev", are never out of date.
+func newerVersion(latest, current string) bool {
+	l, ok := parseSemver(latest)
+	if !ok {
+		return false
+	}
+	c, ok := parseSemver(current)
+	return ok && c.less(l)
+}
+
+func updateCheckPath() (string, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, updateCheckFile), nil
+}
+
+func loadUpdateCheck() updateCheck {
+	var last updateCheck
+	path, err := updateCheckPath()
+	if err != nil {
+		return last
+	}
+	data, err := os.ReadFile(path)
+	if err == nil {
+		json.Unmarshal(data, &last)
+	}
+	return last
+}
+
+func saveUpdateCheck(last updateCheck) {
+	path, err := updateCheckPath()
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(last)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		logger.Info("cannot save update check", "error", err)
+	}
+}
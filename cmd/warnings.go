@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+)
+
+// maxWarningsShown caps the warning list so it stays readable.
+const maxWarningsShown = 15
+
+// contentWarning points at a suspicious added line in the staged diff.
+type contentWarning struct {
+	Path    string
+	Line    int
+	Message string
+}
+
+var debugPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\bfmt\.Print(ln|f)?\(`),
+	regexp.MustCompile(`\bconsole\.(log|debug|trace)\(`),
+	regexp.MustCompile(`^\s*debugger;?\s*$`),
+	regexp.MustCompile(`\b(pdb|ipdb)\.set_trace\(`),
+	regexp.MustCompile(`\bbreakpoint\(\)`),
+	regexp.MustCompile(`\b(var_dump|print_r|dd)\(`),
+}
+
+var todoPattern = regexp.MustCompile(`\b(TODO|FIXME|XXX)\b`)
+
+// scanContentWarnings looks for debug prints, TODOs, conflict markers and
+// trailing whitespace in added lines.
+func scanContentWarnings(files []git.File) []contentWarning {
+	var warnings []contentWarning
+
+	for _, f := range files {
+		if f.Kind != "" || f.Deleted {
+			continue
+		}
+
+		markdown := isMarkdownFile(f.Path)
+		for i, line := range f.Added {
+			add := func(message string) {
+				warnings = append(warnings, contentWarning{Path: f.Path, Line: f.AddedAt[i], Message: message})
+			}
+
+			switch {
+			case strings.HasPrefix(line, "<<<<<<< "), strings.HasPrefix(line, ">>>>>>> "),
+				line == "=======" && !markdown:
+				add(tr("warnings.conflict_marker"))
+				continue
+			}
+
+			if !git.IsTestFile(f.Path) && git.IsSourceFile(f.Path) && matchesAny(debugPatterns, line) {
+				add(tr("warnings.debug_output", strings.TrimSpace(line)))
+			}
+			if m := todoPattern.FindString(line); m != "" {
+				add(tr("warnings.todo", m, strings.TrimSpace(line)))
+			}
+			if !markdown && line != strings.TrimRight(line, " \t") {
+				add(tr("warnings.trailing_whitespace"))
+			}
+		}
+	}
+
+	return warnings
+}
+
+// displayContentWarnings prints the warnings found in the staged changes.
+// In interactive mode the commit can be aborted here; the message is kept
+// as a draft for the next run.
+func displayContentWarnings(warnings []contentWarning, interactive bool) {
+	if len(warnings) == 0 {
+		return
+	}
+
+	fmt.Println()
+	color.Yellow("%s", tr("warnings.title", len(warnings)))
+	for i, w := range warnings {
+		if i == maxWarningsShown {
+			fmt.Println(tr("warnings.more", len(warnings)-maxWarningsShown))
+			break
+		}
+		fmt.Printf("  %s  %s\n", color.CyanString("%s:%d", w.Path, w.Line), truncate(w.Message, 80))
+	}
+	if !interactive || dryRun {
+		return
+	}
+
+	prompt := promptui.Prompt{
+		Label:     tr("warnings.continue"),
+		IsConfirm: true,
+	}
+	if result, err := runPrompt(prompt); err == nil && isYes(result) {
+		return
+	}
+	recordUsage(outcomeCancelled)
+	color.Yellow("%s", tr("warnings.aborted"))
+	os.Exit(1)
+}
+
+func isMarkdownFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown", ".rst", ".txt":
+		return true
+	}
+	return false
+}
+
+func matchesAny(patterns []*regexp.Regexp, line string) bool {
+	for _, p := range patterns {
+		if p.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-3]) + "..."
+}
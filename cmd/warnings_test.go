@@ -0,0 +1,18 @@
+package cmd
+
+import "testing"
+
+func TestContentWarningsConfirm(t *testing.T) {
+	warnings := []contentWarning{{Path: "main.go", Line: 3, Message: "debug output: fmt.Println(x)"}}
+
+	// Plain mode only lists the warnings
+	useScript(t)
+	displayContentWarnings(warnings, false)
+
+	useScript(t, promptStep{"Commit anyway", "y"})
+	displayContentWarnings(warnings, true)
+
+	// Nothing to confirm without warnings
+	useScript(t)
+	displayContentWarnings(nil, true)
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// defaultWipMessage is used when wip.message is not configured.
+const defaultWipMessage = "chore: wip"
+
+var wipPop bool
+
+var wipCmd = &cobra.Command{
+	Use:   "wip",
+	Short: "Create or pop a work-in-progress commit",
+	Long: `Stages all changes and commits them as a work-in-progress commit without
+any prompts. Use --pop to undo the latest WIP commit and get its changes back.`,
+	Example: `  commitz wip
+  commitz wip --pop`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if wipPop {
+			popWipCommit()
+		} else {
+			createWipCommit()
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(wipCmd)
+
+	wipCmd.Flags().BoolVar(
+		&wipPop,
+		"pop",
+		false,
+		"Soft-reset the latest WIP commit back into the working tree",
+	)
+}
+
+func wipMessage() string {
+	if cfg.Wip.Message != "" {
+		return cfg.Wip.Message
+	}
+	return defaultWipMessage
+}
+
+func createWipCommit() {
+	quietOutput()
+	if err := gitRun("add", "-A"); err != nil {
+		color.Red("%s", tr("wip.stage_error", err))
+		os.Exit(1)
+	}
+
+	if err := gitRun("diff", "--cached", "--quiet"); err == nil {
+		color.Yellow("%s", tr("wip.nothing"))
+		return
+	}
+
+	commitCmd := gitCommand("commit", "-q", "-m", wipMessage())
+	commitCmd.Stderr = os.Stderr
+	if err := commitCmd.Run(); err != nil {
+		color.Red("%s", tr("wip.failed", err))
+		os.Exit(1)
+	}
+
+	if quiet {
+		printCommitResult()
+		return
+	}
+	color.Green("%s", tr("wip.saved", wipMessage()))
+	fmt.Println(tr("wip.pop_hint"))
+}
+
+func popWipCommit() {
+	out, err := gitOutput("log", "-1", "--format=%s")
+	if err != nil {
+		color.Red("%s", tr("wip.log_error", err))
+		os.Exit(1)
+	}
+
+	subject := strings.TrimSpace(string(out))
+	if subject != wipMessage() {
+		color.Yellow("%s", tr("wip.not_wip", subject))
+		os.Exit(1)
+	}
+
+	if err := gitRun("reset", "--soft", "HEAD~1"); err != nil {
+		color.Red("%s", tr("wip.reset_error", err))
+		os.Exit(1)
+	}
+
+	color.Green("%s", tr("wip.restored", subject))
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+)
+
+// maxWorktreePathsShown limits how many unstaged paths are listed.
+const maxWorktreePathsShown = 5
+
+// unstagedPatch holds changes set aside while hooks run. It lives in the git
+// directory so it survives a crash.
+const unstagedPatch = "commitz-unstaged.patch"
+
+// worktreeChanges returns modified tracked files that are not staged and
+// untracked files.
+func worktreeChanges() (modified, untracked []string) {
+	out, err := gitOutput("status", "--porcelain=v1", "-z", "--untracked-files=normal")
+	if err != nil {
+		return nil, nil
+	}
+
+	entries := strings.Split(string(out), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+
+		x, y, path := entry[0], entry[1], entry[3:]
+		switch {
+		case x == '?' && y == '?':
+			untracked = append(untracked, path)
+		case y != ' ':
+			modified = append(modified, path)
+		}
+		// Renames and copies are followed by their source path
+		if x == 'R' || x == 'C' {
+			i++
+		}
+	}
+	return modified, untracked
+}
+
+// displayWorktreeChanges warns that the commit will not include changes that
+// are only in the working tree.
+func displayWorktreeChanges(modified, untracked []string) {
+	if len(modified)+len(untracked) == 0 {
+		return
+	}
+
+	fmt.Println()
+	color.Yellow("%s", tr("worktree.title", len(modified), len(untracked)))
+	paths := append(append([]string{}, modified...), untracked...)
+	for i, path := range paths {
+		if i == maxWorktreePathsShown {
+			fmt.Println(tr("warnings.more", len(paths)-maxWorktreePathsShown))
+			break
+		}
+		fmt.Printf("  %s\n", path)
+	}
+}
+
+// setAsideUnstaged offers to remove unstaged changes from the working tree
+// while the pre-commit hook runs, so the hook checks exactly what is
+// committed. It saves them as a patch rather than a stash, since popping a
+// --keep-index stash conflicts on newly added files. It returns the patch
+// path, or "" when nothing was set aside.
+func setAsideUnstaged(interactive bool, modified []string) string {
+	if len(modified) == 0 || assumeYes {
+		return ""
+	}
+	if _, hooks, _ := installedHooks(); !contains(hooks, "pre-commit") {
+		return ""
+	}
+	path, err := gitDirPath(unstagedPatch)
+	if err != nil {
+		return ""
+	}
+	// Never replace changes an earlier run could not restore
+	if _, err := os.Stat(path); err == nil {
+		color.Red("%s", tr("worktree.patch_exists", path))
+		return ""
+	}
+
+	var accepted bool
+	if interactive {
+		prompt := promptui.Prompt{
+			Label:     tr("worktree.stash_prompt"),
+			IsConfirm: true,
+		}
+		result, err := runPrompt(prompt)
+		accepted = err == nil && isYes(result)
+	} else {
+		fmt.Print(tr("worktree.stash_prompt_plain"))
+		answer, _ := readLine()
+		accepted = isYes(answer)
+	}
+	if !accepted {
+		return ""
+	}
+
+	root, err := repoRoot()
+	if err != nil {
+		return ""
+	}
+
+	patch, err := gitOutput("-C", root, "diff", "--binary", "--no-color", "--no-ext-diff")
+	if err == nil {
+		err = writeFileAtomic(path, patch)
+	}
+	if err == nil {
+		err = gitRun("-C", root, "checkout", "--", ".")
+	}
+	if err != nil {
+		color.Red("%s", tr("worktree.stash_failed", err))
+		return ""
+	}
+	return path
+}
+
+// restoreLeftoverPatch applies the changes a crashed run set aside and
+// could not restore. A patch that no longer applies is left for the user.
+func restoreLeftoverPatch() {
+	path, err := gitDirPath(unstagedPatch)
+	if err != nil {
+		return
+	}
+	if _, err := os.Stat(path); err != nil {
+		return
+	}
+	color.Yellow("%s", tr("worktree.leftover"))
+	restoreUnstaged(path)
+}
+
+// restoreUnstaged applies the patch saved by setAsideUnstaged. It also runs
+// after Ctrl+C, so git is not tied to the canceled root context.
+func restoreUnstaged(path string) {
+	root, err := repoRoot()
+	if err == nil {
+		var out []byte
+		out, err = exec.Command(gitExecutable(), "-C", root, "apply", "--whitespace=nowarn", path).CombinedOutput()
+		if err != nil {
+			err = fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+		}
+	}
+	if err != nil {
+		color.Red("%s", tr("worktree.pop_failed", path, err))
+		return
+	}
+	os.Remove(path)
+	fmt.Println(tr("worktree.restored"))
+}
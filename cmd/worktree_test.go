@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUnstagedPatchSurvivesCrash(t *testing.T) {
+	repo := testRepo(t)
+	file := filepath.Join(repo, "main.go")
+	if err := os.WriteFile(file, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun("add", ".")
+	gitRun("commit", "-q", "-m", "feat: add main")
+	hook := filepath.Join(repo, ".git", "hooks", "pre-commit")
+	if err := os.MkdirAll(filepath.Dir(hook), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	const unstaged = "package main\n\n// unstaged\n"
+	if err := os.WriteFile(file, []byte(unstaged), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	useScript(t, promptStep{Answer: "y"})
+	patch := setAsideUnstaged(false, []string{"main.go"})
+	if patch == "" {
+		t.Fatal("setAsideUnstaged() set nothing aside")
+	}
+
+	// A crashed run leaves the patch; the next one must not replace it
+	if err := os.WriteFile(file, []byte("package main\n\n// other\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	useScript(t)
+	if again := setAsideUnstaged(false, []string{"main.go"}); again != "" {
+		t.Fatalf("setAsideUnstaged() replaced the leftover patch with %s", again)
+	}
+
+	gitRun("checkout", "--", ".")
+	restoreLeftoverPatch()
+	if data, _ := os.ReadFile(file); string(data) != unstaged {
+		t.Errorf("main.go after restoreLeftoverPatch() = %q, want %q", data, unstaged)
+	}
+	if _, err := os.Stat(patch); err == nil {
+		t.Error("restored patch was not removed")
+	}
+}
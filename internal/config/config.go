@@ -0,0 +1,463 @@
+// Package config defines the settings commitz reads from its config files.
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the settings read from commitz config files.
+type Config struct {
+	// Language selects the UI language, e.g. "en" or "tr".
+	Language string `yaml:"language"`
+	// MessageLanguage selects the language of generated commit messages.
+	MessageLanguage string `yaml:"message_language"`
+	// Accessible replaces selectors with numbered plain-text prompts, as
+	// --accessible does.
+	Accessible bool `yaml:"accessible"`
+	// UpdateCheck asks GitHub once a day, after a commit, whether a newer
+	// release exists.
+	UpdateCheck bool `yaml:"update_check"`
+	// Offline turns off every feature that uses the network, as --offline
+	// does.
+	Offline bool `yaml:"offline"`
+	// Preset is a built-in convention, e.g. "angular", that the rest of the
+	// config adjusts.
+	Preset string `yaml:"preset"`
+	// Types limits the commit types to these built-in ones.
+	Types []string `yaml:"types"`
+	// Plugins are the enabled commitz-<name> plugins.
+	Plugins []PluginConfig `yaml:"plugins"`
+
+	Analysis   AnalysisConfig   `yaml:"analysis"`
+	Secrets    SecretsConfig    `yaml:"secrets"`
+	LargeFiles LargeFilesConfig `yaml:"large_files"`
+	Size       SizeConfig       `yaml:"size"`
+	Wip        WipConfig        `yaml:"wip"`
+	Protected  ProtectedConfig  `yaml:"protected"`
+	Trunk      TrunkConfig      `yaml:"trunk"`
+	Template   TemplateConfig   `yaml:"template"`
+	Scopes     ScopesConfig     `yaml:"scopes"`
+	Branch     BranchConfig     `yaml:"branch"`
+	Lint       LintConfig       `yaml:"lint"`
+	Changelog  ChangelogConfig  `yaml:"changelog"`
+	Hosting    HostingConfig    `yaml:"hosting"`
+	Issues     IssuesConfig     `yaml:"issues"`
+	Tickets    TicketsConfig    `yaml:"tickets"`
+	Release    ReleaseConfig    `yaml:"release"`
+	Notes      NotesConfig      `yaml:"notes"`
+	SessionLog SessionLogConfig `yaml:"session_log"`
+	Redact     RedactConfig     `yaml:"redact"`
+	Audit      AuditConfig      `yaml:"audit"`
+	Security   SecurityConfig   `yaml:"security"`
+	Body       BodyConfig       `yaml:"body"`
+	Emoji      EmojiConfig      `yaml:"emoji"`
+	Keys       KeysConfig       `yaml:"keys"`
+	Summary    SummaryConfig    `yaml:"summary"`
+
+	// Checks are shell commands that must pass before committing.
+	Checks []string `yaml:"checks"`
+	// Footers validates footers such as "Refs:" and can require them.
+	Footers []FooterRule `yaml:"footers"`
+
+	Timeouts TimeoutsConfig `yaml:"timeouts"`
+	Network  NetworkConfig  `yaml:"network"`
+	Spelling SpellingConfig `yaml:"spelling"`
+}
+
+// AnalysisConfig controls which staged files are analyzed.
+type AnalysisConfig struct {
+	// Ignore lists glob patterns of paths that never influence suggestions.
+	Ignore []string `yaml:"ignore"`
+}
+
+// SecretsConfig controls scanning staged changes for credentials.
+type SecretsConfig struct {
+	// Mode is "block" (default), "warn" or "off".
+	Mode string `yaml:"mode"`
+	// Allow lists regular expressions of known false positives.
+	Allow []string `yaml:"allow"`
+}
+
+// LargeFilesConfig controls the large staged file warning.
+type LargeFilesConfig struct {
+	// Threshold is a size like "5MB"; "off" disables the warning.
+	Threshold string `yaml:"threshold"`
+}
+
+// SizeConfig sets when a commit is large enough to suggest splitting it.
+type SizeConfig struct {
+	// MaxFiles, MaxLines and MaxPackages are the staged files, changed lines
+	// and changed directories above which a warning is shown, 25, 800 and 5
+	// by default; -1 turns a limit off.
+	MaxFiles    int `yaml:"max_files"`
+	MaxLines    int `yaml:"max_lines"`
+	MaxPackages int `yaml:"max_packages"`
+}
+
+// ProtectedConfig controls the protected branch guard.
+type ProtectedConfig struct {
+	// Branches lists branches that should not get direct commits, as names
+	// or patterns like "release/*".
+	Branches []string `yaml:"branches"`
+	// Mode is "warn" (default) or "block".
+	Mode string `yaml:"mode"`
+}
+
+// TrunkConfig tunes commitz for trunk-based development, where everyone
+// commits small changes to main.
+type TrunkConfig struct {
+	// Enabled takes the scope from the staged paths and always asks for it,
+	// and applies the smaller size limits below.
+	Enabled bool `yaml:"enabled"`
+	// MaxFiles and MaxLines replace size.max_files and size.max_lines, 10
+	// and 300 by default; -1 turns a limit off.
+	MaxFiles int `yaml:"max_files"`
+	MaxLines int `yaml:"max_lines"`
+}
+
+// TemplateConfig lays out generated messages.
+type TemplateConfig struct {
+	// Text is a Go template rendered with the generated message, e.g. to add
+	// a footer from {{ cmd "./scripts/sprint-id.sh" }}.
+	Text string `yaml:"text"`
+	// CacheTTL is how long {{ cmd }} output is reused across runs, as a Go
+	// duration such as "1h". By default it is only reused within a run.
+	CacheTTL string `yaml:"cache_ttl"`
+}
+
+// ScopesConfig normalizes scopes in suggestions and lint.
+type ScopesConfig struct {
+	// Case is "preserve" (default), "lower" or "kebab".
+	Case string `yaml:"case"`
+	// StripTicket drops ticket keys like "PROJ-123-" from scopes taken from
+	// the branch name.
+	StripTicket bool `yaml:"strip_ticket"`
+	// Multiple is "allow" (default) or "deny" for headers like
+	// feat(api,auth): ...
+	Multiple string `yaml:"multiple"`
+	// Delimiter joins multiple scopes, "," by default.
+	Delimiter string `yaml:"delimiter"`
+}
+
+// BranchConfig describes how branches are named.
+type BranchConfig struct {
+	// Patterns are regular expressions tried in order on the branch name.
+	// The named groups "scope" and "ticket" of the first match give the
+	// scope and the ticket key. Without a match the part before the first
+	// "/" is the scope.
+	Patterns []string `yaml:"patterns"`
+}
+
+// LintConfig controls the lint command.
+type LintConfig struct {
+	// Baseline is a revision or date; older commits are not linted.
+	Baseline string `yaml:"baseline"`
+	// Merge, Revert and Fixup choose "skip", "relaxed" or "strict" rules
+	// for merge commits, Revert "..." messages and fixup!/squash!/amend!
+	// commits. By default merges and fixups are skipped and reverts
+	// relaxed.
+	Merge  string `yaml:"merge"`
+	Revert string `yaml:"revert"`
+	Fixup  string `yaml:"fixup"`
+	// Forbidden lists words no message may contain, such as "wip" or
+	// "asdf", matched as whole words in any case.
+	Forbidden []string `yaml:"forbidden"`
+	// Patterns are regular expressions messages must match, or with
+	// forbidden must not, e.g. to keep codenames out before a release.
+	Patterns []MessagePattern `yaml:"patterns"`
+	// SubjectCase "lower" requires subjects to start with a lowercase
+	// letter. Subjects are not checked by default.
+	SubjectCase string `yaml:"subject_case"`
+	// BodyMaxLineLength limits body lines, which are not limited by
+	// default. Lines with URLs, code, tables and footers may run longer.
+	BodyMaxLineLength int `yaml:"body_max_line_length"`
+}
+
+// MessagePattern is one lint.patterns rule.
+type MessagePattern struct {
+	Pattern   string `yaml:"pattern"`
+	Forbidden bool   `yaml:"forbidden"`
+	// Message explains the rule when a message breaks it.
+	Message string `yaml:"message"`
+}
+
+// ChangelogConfig controls the changelog command.
+type ChangelogConfig struct {
+	// Template is a Go template file replacing the default markdown layout.
+	Template string `yaml:"template"`
+	// Sections map commit types to headings, in order. Types without a
+	// section are left out.
+	Sections []ChangelogSection `yaml:"sections"`
+	// Authors adds the author to each entry.
+	Authors bool `yaml:"authors"`
+	// PRLinks adds the pull request number to each entry.
+	PRLinks bool `yaml:"pr_links"`
+	// GroupByScope lists the entries of each section under their scope.
+	GroupByScope bool `yaml:"group_by_scope"`
+}
+
+// ChangelogSection is one heading of the changelog.
+type ChangelogSection struct {
+	Title string   `yaml:"title"`
+	Types []string `yaml:"types"`
+}
+
+// ReleaseConfig controls the release command.
+type ReleaseConfig struct {
+	// Tag is "annotated" (default), with a summary of the changes, or
+	// "lightweight".
+	Tag string `yaml:"tag"`
+}
+
+// SecurityConfig controls how security-sensitive changes are suggested.
+type SecurityConfig struct {
+	// Mode is "suggest" (default) or "off".
+	Mode string `yaml:"mode"`
+	// Type replaces the detected type, e.g. "fix" or a custom "security"
+	// type. By default the type is kept.
+	Type string `yaml:"type"`
+	// Scope is suggested when no other scope is, "security" by default.
+	Scope string `yaml:"scope"`
+	// Paths are extra glob patterns of security-sensitive files.
+	Paths []string `yaml:"paths"`
+}
+
+// BodyConfig controls how commit bodies are formatted.
+type BodyConfig struct {
+	// Wrap is the column generated and entered bodies are wrapped at, 72
+	// by default. A negative value turns wrapping off.
+	Wrap int `yaml:"wrap"`
+}
+
+// EmojiConfig controls where --emoji puts the type's emoji.
+type EmojiConfig struct {
+	// Enabled adds emoji without --emoji; Gitmoji uses the gitmoji
+	// catalogue without --gitmoji.
+	Enabled bool `yaml:"enabled"`
+	Gitmoji bool `yaml:"gitmoji"`
+	// Position is "prefix" (default, before the type), "after-colon",
+	// "suffix" (end of the subject), "body" or "footer".
+	Position string `yaml:"position"`
+	// Shortcode writes ":sparkles:" instead of the emoji itself.
+	Shortcode bool `yaml:"shortcode"`
+}
+
+// KeysConfig rebinds the selector keys. Keys are single characters,
+// "up", "down", "left", "right", "tab" or "ctrl-<letter>".
+type KeysConfig struct {
+	Prev     string `yaml:"prev"`
+	Next     string `yaml:"next"`
+	PageUp   string `yaml:"page_up"`
+	PageDown string `yaml:"page_down"`
+	Search   string `yaml:"search"`
+	// QuickSelect starts selectors in search mode, where typing an item's
+	// number picks it. The numbers are shown and types keep their order.
+	QuickSelect bool `yaml:"quick_select"`
+	// Escape is what Esc does in a selector: "cancel" (default) closes it
+	// without a choice, "default" keeps the pre-selected item. ctrl-c
+	// always stops commitz.
+	Escape string `yaml:"escape"`
+}
+
+// SummaryConfig chooses how summaries are suggested.
+type SummaryConfig struct {
+	// Generators are tried in order until one suggests a summary:
+	// "template", "command" and "heuristic" (always tried last).
+	Generators []string `yaml:"generators"`
+	// Template is a Go template for the "template" generator, with .Type,
+	// .Topic, .Dir, .Files and .Count.
+	Template string `yaml:"template"`
+	// Command is run by the "command" generator with the staged diff on
+	// stdin; its first output line is the summary.
+	Command string `yaml:"command"`
+}
+
+// NotesConfig controls recording how each generated commit came about.
+type NotesConfig struct {
+	// Enabled attaches the suggested and chosen type, scope and summary to
+	// each commit as a JSON git note.
+	Enabled bool `yaml:"enabled"`
+	// Ref is the notes ref, "commitz" (refs/notes/commitz) by default.
+	Ref string `yaml:"ref"`
+}
+
+// SessionLogConfig keeps a local log of each run for
+// commitz stats --suggestions.
+type SessionLogConfig struct {
+	// Enabled records the suggested and the committed message and the size
+	// of the change in .git/commitz-sessions.jsonl.
+	Enabled bool `yaml:"enabled"`
+	// MaxEntries bounds the log, 1000 runs by default.
+	MaxEntries int `yaml:"max_entries"`
+}
+
+// RedactConfig keeps message details out of history, for repositories
+// that are mirrored publicly.
+type RedactConfig struct {
+	// Enabled commits only the header and the footers in KeepFooters and
+	// keeps the full message in this clone.
+	Enabled bool `yaml:"enabled"`
+	// Store is "notes" (default), a git note under refs/notes/commitz-private,
+	// or "file", a file per commit in .git/commitz-private.
+	Store string `yaml:"store"`
+	// KeepFooters lists footers that stay in the commit, e.g.
+	// "BREAKING CHANGE" for release tooling.
+	KeepFooters []string `yaml:"keep_footers"`
+}
+
+// AuditConfig records commits made with the hooks skipped, for teams
+// that review them.
+type AuditConfig struct {
+	// Enabled records who committed with --no-verify, when, and which lint
+	// rules and hooks the commit failed.
+	Enabled bool `yaml:"enabled"`
+	// Store is "file" (default), .git/commitz-audit.jsonl, or "notes", a git
+	// note under refs/notes/commitz-audit.
+	Store string `yaml:"store"`
+}
+
+// HostingConfig names the platform hosting the repository, for links in
+// changelogs. By default it is detected from the upstream or origin
+// remote.
+type HostingConfig struct {
+	// Type is "github", "gitlab", "bitbucket" or "gitea".
+	Type string `yaml:"type"`
+	// URL is the repository's web URL, for self-hosted instances.
+	URL string `yaml:"url"`
+	// Remote names the canonical remote when the repository has several,
+	// as --remote does.
+	Remote string `yaml:"remote"`
+}
+
+// IssuesConfig connects an issue tracker, whose ticket for the branch
+// decides the commit type when the diff alone says little.
+type IssuesConfig struct {
+	// Provider is "github", "gitlab" or "jira". Empty turns lookups off.
+	Provider string `yaml:"provider"`
+	// URL is the Jira site, e.g. https://acme.atlassian.net. GitHub and
+	// GitLab use the hosting URL.
+	URL string `yaml:"url"`
+	// Types maps ticket types and labels to commit types, on top of the
+	// defaults: bug is fix, story and feature are feat, task is chore.
+	Types map[string]string `yaml:"types"`
+}
+
+// TicketsConfig requires commits on some branches to reference a ticket.
+type TicketsConfig struct {
+	// Required lists branches, as names or patterns like "release/*", whose
+	// commits must mention a ticket such as PROJ-123 or #42.
+	Required []string `yaml:"required"`
+	// Footer is the footer a missing ticket is added as, "Refs" by default.
+	Footer string `yaml:"footer"`
+}
+
+// FooterRule validates the footers with one key.
+type FooterRule struct {
+	Key      string `yaml:"key"`
+	Required bool   `yaml:"required"`
+	// Pattern is a regular expression every value must match.
+	Pattern string `yaml:"pattern"`
+	// Example is shown when asking for the value, e.g. "PROJ-123".
+	Example string `yaml:"example"`
+}
+
+// PluginConfig enables one plugin. Version pins it: a plugin reporting
+// another version is not loaded.
+type PluginConfig struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version,omitempty"`
+}
+
+// WipConfig controls the wip command.
+type WipConfig struct {
+	// Message is the subject used for WIP commits.
+	Message string `yaml:"message"`
+}
+
+// SpellingConfig controls spell checking of commit messages.
+type SpellingConfig struct {
+	Disabled bool `yaml:"disabled"`
+	// Words is the project dictionary of jargon that is never flagged.
+	Words []string `yaml:"words"`
+	// Corrections maps additional project-specific typos to their fix.
+	Corrections map[string]string `yaml:"corrections"`
+}
+
+// NetworkConfig adjusts TLS for GitHub, issue tracker and update check
+// requests. Proxies are taken from HTTPS_PROXY, HTTP_PROXY and NO_PROXY.
+type NetworkConfig struct {
+	// CABundle is a PEM file of extra certificates to trust, such as a
+	// company CA that intercepting proxies sign with.
+	CABundle string `yaml:"ca_bundle"`
+	// InsecureSkipVerify turns off certificate checks. Prefer CABundle.
+	InsecureSkipVerify bool `yaml:"insecure_skip_verify"`
+	// Retries is how often a rate limited or failed request is retried,
+	// 3 by default; -1 turns retries off.
+	Retries int `yaml:"retries"`
+	// MaxWait is the longest a retry waits, e.g. "30s". Requests the
+	// server asks to delay longer fail instead.
+	MaxWait string `yaml:"max_wait"`
+}
+
+// TimeoutsConfig bounds external commands. Values are Go durations such
+// as "30s".
+type TimeoutsConfig struct {
+	// Git limits quick git queries; commits and hooks are not limited.
+	Git string `yaml:"git"`
+}
+
+// Read reads the YAML config file at path into cfg, keeping the values
+// the file does not set.
+func Read(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, cfg)
+}
+
+// MatchGlob matches a slash-separated path against a glob pattern where "**"
+// spans directories. Patterns without a slash match the file name at any
+// depth, like .gitignore entries.
+func MatchGlob(pattern, path string) bool {
+	path = filepath.ToSlash(path)
+	if !strings.Contains(pattern, "/") {
+		pattern = "**/" + pattern
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				// "**/" also matches zero directories
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return false
+	}
+	return re.MatchString(path)
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"*.pb.go", "api/v1/service.pb.go", true},
+		{"*.pb.go", "service.go", false},
+		{"docs/**", "docs/guide/intro.md", true},
+		{"docs/**", "src/docs/intro.md", false},
+		{"**/testdata/*", "cmd/testdata/golden.txt", true},
+		{"src/**/*.ts", "src/app.ts", true},
+		{"src/*.ts", "src/app/main.ts", false},
+		{"file?.txt", "file1.txt", true},
+	}
+	for _, tt := range tests {
+		if got := MatchGlob(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestReadKeepsUnsetValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("language: tr\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := Config{Language: "en", MessageLanguage: "en"}
+	if err := Read(path, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Language != "tr" || cfg.MessageLanguage != "en" {
+		t.Errorf("Read() = language %q, message language %q; want tr, en", cfg.Language, cfg.MessageLanguage)
+	}
+}
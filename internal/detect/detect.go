@@ -0,0 +1,197 @@
+// Package detect classifies staged files and suggests a commit type from
+// them.
+package detect
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/git"
+)
+
+// File kinds that are excluded from summary analysis.
+const (
+	KindBinary    = "binary"
+	KindLockfile  = "lockfile"
+	KindVendored  = "vendored"
+	KindGenerated = "generated"
+)
+
+// Lockfiles are the file names package managers write their resolved
+// dependencies to.
+var Lockfiles = []string{
+	"go.sum", "package-lock.json", "yarn.lock", "pnpm-lock.yaml", "Cargo.lock",
+	"poetry.lock", "Pipfile.lock", "Gemfile.lock", "composer.lock", "bun.lockb",
+}
+
+// VendoredDirs are the directory names that hold third-party code.
+var VendoredDirs = []string{"vendor", "node_modules", "third_party", "bower_components"}
+
+var generatedSuffixes = []string{
+	".pb.go", "_pb2.py", "_pb2_grpc.py", ".pb.ts", ".pb.dart", "_grpc.pb.go",
+	"_gen.go", ".gen.go", "_generated.go", "_string.go", ".min.js", ".min.css",
+	"_mock.go", ".generated.ts", ".g.dart", ".freezed.dart",
+}
+
+// Classify marks binary, lockfile, vendored and generated files. attrs maps
+// a path to the kind its linguist-generated or linguist-vendored
+// attribute gives it.
+func Classify(files []git.File, attrs map[string]string) {
+	for i := range files {
+		f := &files[i]
+		switch {
+		case f.Binary:
+			f.Kind = KindBinary
+		case slices.Contains(Lockfiles, filepath.Base(f.Path)):
+			f.Kind = KindLockfile
+		case attrs[f.Path] == KindVendored || InVendoredDir(f.Path):
+			f.Kind = KindVendored
+		case attrs[f.Path] == KindGenerated || LooksGenerated(*f):
+			f.Kind = KindGenerated
+		}
+	}
+}
+
+// InVendoredDir reports whether the path is inside a vendored directory.
+func InVendoredDir(path string) bool {
+	for _, dir := range strings.Split(filepath.ToSlash(path), "/") {
+		if slices.Contains(VendoredDirs, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// LooksGenerated reports whether the file name or a generator banner in
+// the added lines marks the file as generated.
+func LooksGenerated(f git.File) bool {
+	base := filepath.Base(f.Path)
+	for _, suffix := range generatedSuffixes {
+		if strings.HasSuffix(base, suffix) {
+			return true
+		}
+	}
+	if strings.HasPrefix(base, "zz_generated") || strings.HasPrefix(base, "mock_") {
+		return true
+	}
+
+	// Go's standard banner, also used by many other generators, and the
+	// @generated marker
+	for _, line := range f.Added {
+		if strings.Contains(line, "Code generated") && strings.Contains(line, "DO NOT EDIT") {
+			return true
+		}
+		if strings.Contains(line, "@generated") {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitNoise separates hand-written changes from excluded files.
+func SplitNoise(files []git.File) (relevant, noise []git.File) {
+	for _, f := range files {
+		if f.Kind != "" {
+			noise = append(noise, f)
+		} else {
+			relevant = append(relevant, f)
+		}
+	}
+	return relevant, noise
+}
+
+// NoiseType suggests a type when only excluded files changed.
+func NoiseType(noise []git.File) string {
+	for _, f := range noise {
+		if f.Kind != KindLockfile && f.Kind != KindVendored {
+			return "chore"
+		}
+	}
+	return "build"
+}
+
+// DiffText rebuilds a diff-like text from the given files for keyword
+// analysis.
+func DiffText(files []git.File) string {
+	var b strings.Builder
+	for _, f := range files {
+		fmt.Fprintf(&b, "diff --git a/%s b/%s\n", f.OldPath, f.Path)
+		if f.IsNew {
+			b.WriteString("new file mode\n")
+		}
+		if f.Deleted {
+			b.WriteString("deleted file mode\n")
+		}
+		for _, line := range f.Removed {
+			b.WriteString("-" + line + "\n")
+		}
+		for _, line := range f.Added {
+			b.WriteString("+" + line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// SplitTests separates test files from the remaining changed files.
+func SplitTests(files []git.File) (tests, others []git.File) {
+	for _, f := range files {
+		if git.IsTestFile(f.Path) {
+			tests = append(tests, f)
+		} else {
+			others = append(others, f)
+		}
+	}
+	return tests, others
+}
+
+// OnlyDeletions reports whether every changed file was deleted.
+func OnlyDeletions(files []git.File) bool {
+	if len(files) == 0 {
+		return false
+	}
+	for _, f := range files {
+		if !f.Deleted {
+			return false
+		}
+	}
+	return true
+}
+
+// OnlyWhitespace reports whether every changed file only changed whitespace.
+func OnlyWhitespace(files []git.File) bool {
+	if len(files) == 0 {
+		return false
+	}
+	for _, f := range files {
+		if !f.WhitespaceOnly {
+			return false
+		}
+	}
+	return true
+}
+
+// Keyword suggests a type from words in the diff, the weakest signal
+// commitz uses. It returns the keyword that matched, or false when none
+// did. Documentation paths are matched as written, other keywords in any
+// case.
+func Keyword(diff string) (commitType, keyword string, ok bool) {
+	for _, keyword := range []string{"README", ".md", "docs/"} {
+		if strings.Contains(diff, keyword) {
+			return "docs", keyword, true
+		}
+	}
+	diffLower := strings.ToLower(diff)
+	for _, keyword := range []string{"fix", "bug"} {
+		if strings.Contains(diffLower, keyword) {
+			return "fix", keyword, true
+		}
+	}
+	for _, keyword := range []string{"feat", "add ", "new "} {
+		if strings.Contains(diffLower, keyword) {
+			return "feat", strings.TrimSpace(keyword), true
+		}
+	}
+	return "", "", false
+}
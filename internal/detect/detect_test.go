@@ -0,0 +1,83 @@
+package detect
+
+import (
+	"testing"
+
+	"github.com/barisdilekci/commitz/internal/git"
+)
+
+func TestClassify(t *testing.T) {
+	files := []git.File{
+		{Path: "main.go"},
+		{Path: "logo.png", Binary: true},
+		{Path: "web/package-lock.json"},
+		{Path: "vendor/github.com/pkg/errors/errors.go"},
+		{Path: "api/service.pb.go"},
+		{Path: "gen/schema.go", Added: []string{"// Code generated by sqlc. DO NOT EDIT."}},
+		{Path: "assets/bundle.js"},
+	}
+	Classify(files, map[string]string{"assets/bundle.js": KindGenerated})
+
+	want := []string{"", KindBinary, KindLockfile, KindVendored, KindGenerated, KindGenerated, KindGenerated}
+	for i, f := range files {
+		if f.Kind != want[i] {
+			t.Errorf("%s: kind %q, want %q", f.Path, f.Kind, want[i])
+		}
+	}
+}
+
+func TestNoiseType(t *testing.T) {
+	tests := []struct {
+		kinds []string
+		want  string
+	}{
+		{[]string{KindLockfile}, "build"},
+		{[]string{KindLockfile, KindVendored}, "build"},
+		{[]string{KindLockfile, KindGenerated}, "chore"},
+		{[]string{KindBinary}, "chore"},
+	}
+	for _, tt := range tests {
+		var noise []git.File
+		for _, kind := range tt.kinds {
+			noise = append(noise, git.File{Kind: kind})
+		}
+		if got := NoiseType(noise); got != tt.want {
+			t.Errorf("NoiseType(%v) = %q, want %q", tt.kinds, got, tt.want)
+		}
+	}
+}
+
+func TestKeyword(t *testing.T) {
+	tests := []struct {
+		diff     string
+		wantType string
+		keyword  string
+		ok       bool
+	}{
+		{"diff --git a/README b/README", "docs", "README", true},
+		{"+Fix the retry loop", "fix", "fix", true},
+		{"+// handle the bug in paging", "fix", "bug", true},
+		{"+func add (a, b int)", "feat", "add", true},
+		{"+readme wording", "", "", false},
+		{"+x := 1", "", "", false},
+	}
+	for _, tt := range tests {
+		gotType, keyword, ok := Keyword(tt.diff)
+		if gotType != tt.wantType || keyword != tt.keyword || ok != tt.ok {
+			t.Errorf("Keyword(%q) = %q, %q, %v; want %q, %q, %v", tt.diff, gotType, keyword, ok, tt.wantType, tt.keyword, tt.ok)
+		}
+	}
+}
+
+func TestOnlyDeletionsAndWhitespace(t *testing.T) {
+	if OnlyDeletions(nil) || OnlyWhitespace(nil) {
+		t.Error("no files count as only deletions or only whitespace")
+	}
+	files := []git.File{{Path: "a.go", Deleted: true, WhitespaceOnly: true}, {Path: "b.go", Deleted: true}}
+	if !OnlyDeletions(files) {
+		t.Error("OnlyDeletions() = false for deleted files")
+	}
+	if OnlyWhitespace(files) {
+		t.Error("OnlyWhitespace() = true with a non-whitespace change")
+	}
+}
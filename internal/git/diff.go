@@ -0,0 +1,251 @@
+// Package git reads the output of git commands commitz runs.
+package git
+
+import (
+	"bufio"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Limits that keep memory bounded for very large diffs. Lines beyond them
+// are still counted but not kept for analysis.
+const (
+	maxStoredLinesPerFile = 5000
+	maxStoredLines        = 200000
+	maxLineLength         = 4096
+)
+
+// File describes the staged changes of a single file.
+type File struct {
+	Path    string
+	OldPath string
+	IsNew   bool
+	Deleted bool
+	Binary  bool
+	Kind    string
+	Added   []string
+	Removed []string
+	// AddedAt holds the new-file line number of each added line
+	AddedAt []int
+	// Additions and Deletions count all changed lines, including those not
+	// kept in Added and Removed
+	Additions int
+	Deletions int
+	// WhitespaceOnly marks files whose changes are all whitespace
+	WhitespaceOnly bool
+	// Context holds the enclosing lines git shows in hunk headers, such as
+	// the function a hunk is in
+	Context []string
+}
+
+// Options change how a diff is read.
+type Options struct {
+	// WordDiff reads "git diff --word-diff=porcelain" output
+	WordDiff bool
+}
+
+// ParseDiff splits a unified git diff into per-file changes.
+func ParseDiff(diff string, opts Options) []File {
+	files, _ := ParseDiffReader(strings.NewReader(diff), opts)
+	return files
+}
+
+// ParseDiffReader parses a unified diff as it streams in, keeping only a
+// bounded number of changed lines in memory. With --word-diff, changed
+// words take the place of changed lines and "~" ends each line.
+func ParseDiffReader(r io.Reader, opts Options) ([]File, error) {
+	var files []File
+	var current *File
+	lineNo := 0
+	inHunk := false
+	stored := 0
+
+	reader := bufio.NewReaderSize(r, 64*1024)
+	for {
+		line, err := readDiffLine(reader)
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return files, err
+		}
+
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			files = append(files, File{})
+			current = &files[len(files)-1]
+			inHunk = false
+			parts := strings.Fields(line)
+			if len(parts) >= 4 {
+				current.OldPath = strings.TrimPrefix(parts[2], "a/")
+				current.Path = strings.TrimPrefix(parts[3], "b/")
+			}
+		case current == nil:
+			continue
+		case strings.HasPrefix(line, "@@"):
+			lineNo = hunkStart(line)
+			inHunk = true
+			if _, context, ok := strings.Cut(strings.TrimPrefix(line, "@@"), "@@"); ok && strings.TrimSpace(context) != "" {
+				current.Context = append(current.Context, strings.TrimSpace(context))
+			}
+		case !inHunk:
+			switch {
+			case strings.HasPrefix(line, "new file mode"):
+				current.IsNew = true
+			case strings.HasPrefix(line, "deleted file mode"):
+				current.Deleted = true
+			case strings.HasPrefix(line, "Binary files"):
+				current.Binary = true
+			case strings.HasPrefix(line, "rename from "):
+				current.OldPath = strings.TrimPrefix(line, "rename from ")
+			case strings.HasPrefix(line, "rename to "):
+				current.Path = strings.TrimPrefix(line, "rename to ")
+			}
+		case strings.HasPrefix(line, "+"):
+			current.Additions++
+			if stored < maxStoredLines && len(current.Added) < maxStoredLinesPerFile {
+				current.Added = append(current.Added, strings.TrimPrefix(line, "+"))
+				current.AddedAt = append(current.AddedAt, lineNo)
+				stored++
+			}
+			if !opts.WordDiff {
+				lineNo++
+			}
+		case strings.HasPrefix(line, "-"):
+			current.Deletions++
+			if stored < maxStoredLines && len(current.Removed) < maxStoredLinesPerFile {
+				current.Removed = append(current.Removed, strings.TrimPrefix(line, "-"))
+				stored++
+			}
+		case strings.HasPrefix(line, " "):
+			if !opts.WordDiff {
+				lineNo++
+			}
+		case strings.HasPrefix(line, "~"):
+			lineNo++
+		}
+	}
+
+	return files, nil
+}
+
+// readDiffLine reads one line, truncating very long lines such as minified
+// assets instead of buffering them whole.
+func readDiffLine(r *bufio.Reader) (string, error) {
+	var b strings.Builder
+	for {
+		chunk, isPrefix, err := r.ReadLine()
+		if err != nil {
+			if err == io.EOF && b.Len() > 0 {
+				return b.String(), nil
+			}
+			return b.String(), err
+		}
+		if b.Len() < maxLineLength {
+			b.Write(chunk[:min(len(chunk), maxLineLength-b.Len())])
+		}
+		if !isPrefix {
+			return b.String(), nil
+		}
+	}
+}
+
+// ParseNumstat builds file changes from "git diff --numstat --summary"
+// output, without any line content.
+func ParseNumstat(out string) []File {
+	var files []File
+	index := map[string]int{}
+
+	for _, line := range strings.Split(out, "\n") {
+		if fields := strings.Split(line, "\t"); len(fields) == 3 {
+			f := File{Path: fields[2], OldPath: fields[2]}
+			if fields[0] == "-" {
+				f.Binary = true
+			} else {
+				f.Additions, _ = strconv.Atoi(fields[0])
+				f.Deletions, _ = strconv.Atoi(fields[1])
+			}
+			if old, path, ok := SplitRename(fields[2]); ok {
+				f.OldPath, f.Path = old, path
+			}
+			index[f.Path] = len(files)
+			files = append(files, f)
+			continue
+		}
+
+		// Summary lines look like " create mode 100644 path"
+		fields := strings.Fields(line)
+		if len(fields) == 4 && fields[1] == "mode" {
+			if i, ok := index[fields[3]]; ok {
+				files[i].IsNew = fields[0] == "create"
+				files[i].Deleted = fields[0] == "delete"
+			}
+		}
+	}
+
+	return files
+}
+
+// SplitRename expands numstat rename paths like "dir/{old => new}.go".
+func SplitRename(path string) (string, string, bool) {
+	if !strings.Contains(path, " => ") {
+		return "", "", false
+	}
+
+	open, close := strings.Index(path, "{"), strings.Index(path, "}")
+	if open < 0 || close < open {
+		old, newPath, _ := strings.Cut(path, " => ")
+		return old, newPath, true
+	}
+
+	old, newPart, _ := strings.Cut(path[open+1:close], " => ")
+	prefix, suffix := path[:open], path[close+1:]
+	clean := func(p string) string { return strings.ReplaceAll(p, "//", "/") }
+	return clean(prefix + old + suffix), clean(prefix + newPart + suffix), true
+}
+
+// hunkStart returns the first new-file line number of a "@@ -a,b +c,d @@"
+// hunk header.
+func hunkStart(header string) int {
+	for _, field := range strings.Fields(header) {
+		if strings.HasPrefix(field, "+") {
+			start, _, _ := strings.Cut(strings.TrimPrefix(field, "+"), ",")
+			n, err := strconv.Atoi(start)
+			if err == nil {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+// IsTestFile reports whether the path looks like a test file.
+func IsTestFile(path string) bool {
+	base := filepath.Base(path)
+	if strings.HasSuffix(base, "_test.go") ||
+		strings.Contains(base, ".test.") ||
+		strings.Contains(base, ".spec.") ||
+		(strings.HasPrefix(base, "test_") && strings.HasSuffix(base, ".py")) {
+		return true
+	}
+
+	for _, dir := range strings.Split(filepath.ToSlash(filepath.Dir(path)), "/") {
+		if dir == "test" || dir == "tests" || dir == "testdata" || dir == "__tests__" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsSourceFile reports whether the path looks like program source code.
+func IsSourceFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".go", ".js", ".jsx", ".ts", ".tsx", ".py", ".rb", ".java", ".kt",
+		".rs", ".c", ".h", ".cc", ".cpp", ".cs", ".swift", ".php", ".sh":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+package git
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const stagedDiff = `diff --git a/a.go b/a.go
+index af058f6..3a90960 100644
+--- a/a.go
++++ b/a.go
+@@ -1,5 +1,6 @@ package a
+ package a
+ 
+ func A() {
++	println(1)
+ 	return
+ }
+diff --git a/b.bin b/b.bin
+index bdc955b..8835708 100644
+Binary files a/b.bin and b/b.bin differ
+diff --git a/n.txt b/n.txt
+new file mode 100644
+index 0000000..8ba3a16
+--- /dev/null
++++ b/n.txt
+@@ -0,0 +1 @@
++n
+diff --git a/gone.txt b/gone.txt
+deleted file mode 100644
+index 8ba3a16..0000000
+--- a/gone.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-gone
+diff --git a/old.txt b/new.txt
+similarity index 100%
+rename from old.txt
+rename to new.txt
+`
+
+func TestParseDiff(t *testing.T) {
+	files := ParseDiff(stagedDiff, Options{})
+	byPath := map[string]File{}
+	for _, f := range files {
+		byPath[f.Path] = f
+	}
+	if len(files) != 5 {
+		t.Fatalf("got %d files, want 5", len(files))
+	}
+
+	tests := []struct {
+		path string
+		want func(File) bool
+		desc string
+	}{
+		{"a.go", func(f File) bool {
+			return f.Additions == 1 && f.Deletions == 0 && reflect.DeepEqual(f.AddedAt, []int{4})
+		}, "one added line at line 4"},
+		{"a.go", func(f File) bool { return reflect.DeepEqual(f.Context, []string{"package a"}) }, "hunk context"},
+		{"b.bin", func(f File) bool { return f.Binary && len(f.Added) == 0 }, "binary"},
+		{"n.txt", func(f File) bool { return f.IsNew && reflect.DeepEqual(f.Added, []string{"n"}) }, "new file"},
+		{"gone.txt", func(f File) bool { return f.Deleted && reflect.DeepEqual(f.Removed, []string{"gone"}) }, "deleted file"},
+		{"new.txt", func(f File) bool { return f.OldPath == "old.txt" && f.Additions == 0 }, "rename"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path+"/"+tt.desc, func(t *testing.T) {
+			f, ok := byPath[tt.path]
+			if !ok {
+				t.Fatalf("%s not parsed", tt.path)
+			}
+			if !tt.want(f) {
+				t.Errorf("%s: unexpected %+v", tt.desc, f)
+			}
+		})
+	}
+}
+
+func TestParseDiffWordDiff(t *testing.T) {
+	diff := `diff --git a/a.txt b/a.txt
+--- a/a.txt
++++ b/a.txt
+@@ -1,2 +1,2 @@
+ same
+~
+ one 
+-two
++three
+~
+`
+	tests := []struct {
+		name string
+		opts Options
+		want []int
+	}{
+		{"word diff", Options{WordDiff: true}, []int{2}},
+		// Read as a line diff, every context and "~" line counts
+		{"line diff", Options{}, []int{4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := ParseDiff(diff, tt.opts)
+			if len(files) != 1 {
+				t.Fatalf("got %d files, want 1", len(files))
+			}
+			if !reflect.DeepEqual(files[0].AddedAt, tt.want) {
+				t.Errorf("AddedAt = %v, want %v", files[0].AddedAt, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDiffTruncatesLongLines(t *testing.T) {
+	diff := "diff --git a/a.min.js b/a.min.js\n@@ -0,0 +1 @@\n+" + strings.Repeat("x", 3*maxLineLength) + "\n"
+	files := ParseDiff(diff, Options{})
+	if got := len(files[0].Added[0]); got != maxLineLength-1 {
+		t.Errorf("kept %d bytes, want %d", got, maxLineLength-1)
+	}
+}
+
+func TestParseNumstat(t *testing.T) {
+	out := "1\t0\ta.go\n-\t-\tb.bin\n1\t0\tn.txt\n0\t0\tpkg/{old => new}/x.go\n create mode 100644 n.txt\n rename pkg/{old => new}/x.go (100%)\n"
+	want := []File{
+		{Path: "a.go", OldPath: "a.go", Additions: 1},
+		{Path: "b.bin", OldPath: "b.bin", Binary: true},
+		{Path: "n.txt", OldPath: "n.txt", Additions: 1, IsNew: true},
+		{Path: "pkg/new/x.go", OldPath: "pkg/old/x.go"},
+	}
+	if got := ParseNumstat(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseNumstat() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestSplitRename(t *testing.T) {
+	tests := []struct {
+		path     string
+		old, new string
+		ok       bool
+	}{
+		{"a.go", "", "", false},
+		{"old.txt => new.txt", "old.txt", "new.txt", true},
+		{"dir/{old => new}.go", "dir/old.go", "dir/new.go", true},
+		{"dir/{ => sub}/a.go", "dir/a.go", "dir/sub/a.go", true},
+		{"{a => b}/c.go", "a/c.go", "b/c.go", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			old, newPath, ok := SplitRename(tt.path)
+			if old != tt.old || newPath != tt.new || ok != tt.ok {
+				t.Errorf("SplitRename(%q) = %q, %q, %v; want %q, %q, %v", tt.path, old, newPath, ok, tt.old, tt.new, tt.ok)
+			}
+		})
+	}
+}
+
+func TestIsTestFile(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"pkg/a_test.go", true},
+		{"src/app.test.ts", true},
+		{"tests/test_api.py", true},
+		{"pkg/a.go", false},
+		{"README.md", false},
+	}
+	for _, tt := range tests {
+		if got := IsTestFile(tt.path); got != tt.want {
+			t.Errorf("IsTestFile(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func FuzzParseDiff(f *testing.F) {
+	f.Add(stagedDiff, false)
+	f.Add("diff --git a/a b/a\n@@ -1 +1 @@\n one \n-two\n+three\n~\n", true)
+	f.Add("diff --git\n@@ @@ @@\n+\x00\xff", false)
+	f.Fuzz(func(t *testing.T, diff string, wordDiff bool) {
+		files, err := ParseDiffReader(strings.NewReader(diff), Options{WordDiff: wordDiff})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range files {
+			if len(file.Added) != len(file.AddedAt) {
+				t.Fatalf("%d added lines but %d line numbers", len(file.Added), len(file.AddedAt))
+			}
+			if file.Additions < len(file.Added) || file.Deletions < len(file.Removed) {
+				t.Fatalf("counts below kept lines: %+v", file)
+			}
+		}
+	})
+}
+
+func FuzzParseNumstat(f *testing.F) {
+	f.Add("1\t0\ta.go\n-\t-\tb.bin\n0\t0\tpkg/{old => new}/x.go\n create mode 100644 a.go\n")
+	f.Add("x\ty\t} => {\n")
+	f.Fuzz(func(t *testing.T, out string) {
+		ParseNumstat(out)
+	})
+}
@@ -0,0 +1,91 @@
+package message
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Where an emoji goes in a message, the values of emoji.position.
+const (
+	EmojiPrefix     = "prefix"
+	EmojiAfterColon = "after-colon"
+	EmojiSuffix     = "suffix"
+	EmojiBody       = "body"
+	EmojiFooter     = "footer"
+)
+
+// EmojiFooterKey is the footer holding the emoji with EmojiFooter.
+const EmojiFooterKey = "Emoji"
+
+// Emoji is an emoji, with its trailing space, and where it goes.
+type Emoji struct {
+	Text     string
+	Position string
+}
+
+// Build renders a header like "✨ feat(api): add paging", with the emoji
+// where its position puts it. With EmojiBody or EmojiFooter the header has
+// none; see AddEmoji.
+func Build(h Header, emoji Emoji) string {
+	text, summary := emoji.Text, h.Subject
+	switch emoji.Position {
+	case EmojiAfterColon:
+		summary = text + summary
+		text = ""
+	case EmojiSuffix:
+		if text != "" {
+			summary += " " + strings.TrimSpace(text)
+		}
+		text = ""
+	case EmojiBody, EmojiFooter:
+		text = ""
+	}
+
+	breaking := ""
+	if h.Breaking {
+		breaking = "!"
+	}
+	if h.Scope != "" {
+		return fmt.Sprintf("%s%s(%s)%s: %s", text, h.Type, h.Scope, breaking, summary)
+	}
+	return fmt.Sprintf("%s%s%s: %s", text, h.Type, breaking, summary)
+}
+
+// AddEmoji puts the emoji in the body or a footer when its position says
+// so, keeping the header plain for commitlint, semantic-release and other
+// tools that parse it.
+func AddEmoji(msg string, emoji Emoji) string {
+	text := strings.TrimSpace(emoji.Text)
+	if text == "" {
+		return msg
+	}
+
+	switch emoji.Position {
+	case EmojiBody:
+		header, body, _ := strings.Cut(msg, "\n\n")
+		if body == "" {
+			return header + "\n\n" + text
+		}
+		return header + "\n\n" + text + "\n\n" + body
+	case EmojiFooter:
+		return SetFooter(msg, EmojiFooterKey, text)
+	}
+	return msg
+}
+
+// MarkBreaking adds "!" to the header of a message with a BREAKING CHANGE
+// footer.
+func MarkBreaking(msg string) string {
+	header, rest, _ := strings.Cut(msg, "\n")
+	if !strings.Contains(rest, "BREAKING CHANGE: ") {
+		return msg
+	}
+	if h, ok := ParseHeader(header); !ok || h.Breaking {
+		return msg
+	}
+	header = strings.Replace(header, ": ", "!: ", 1)
+	if rest == "" {
+		return header
+	}
+	return header + "\n" + rest
+}
@@ -0,0 +1,68 @@
+package message
+
+import "testing"
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name   string
+		header Header
+		emoji  Emoji
+		want   string
+	}{
+		{"plain", Header{Type: "feat", Subject: "add paging"}, Emoji{}, "feat: add paging"},
+		{"scope", Header{Type: "feat", Scope: "api", Subject: "add paging"}, Emoji{}, "feat(api): add paging"},
+		{"breaking", Header{Type: "feat", Scope: "api", Breaking: true, Subject: "drop v1"}, Emoji{}, "feat(api)!: drop v1"},
+		{"prefix", Header{Type: "feat", Scope: "api", Subject: "add paging"}, Emoji{"✨ ", EmojiPrefix}, "✨ feat(api): add paging"},
+		{"after colon", Header{Type: "feat", Subject: "add paging"}, Emoji{"✨ ", EmojiAfterColon}, "feat: ✨ add paging"},
+		{"suffix", Header{Type: "feat", Subject: "add paging"}, Emoji{"✨ ", EmojiSuffix}, "feat: add paging ✨"},
+		{"body", Header{Type: "feat", Subject: "add paging"}, Emoji{"✨ ", EmojiBody}, "feat: add paging"},
+		{"footer", Header{Type: "feat", Subject: "add paging"}, Emoji{"✨ ", EmojiFooter}, "feat: add paging"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Build(tt.header, tt.emoji); got != tt.want {
+				t.Errorf("Build() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddEmoji(t *testing.T) {
+	tests := []struct {
+		name  string
+		msg   string
+		emoji Emoji
+		want  string
+	}{
+		{"prefix", "feat: add", Emoji{"✨ ", EmojiPrefix}, "feat: add"},
+		{"body", "feat: add", Emoji{"✨ ", EmojiBody}, "feat: add\n\n✨"},
+		{"body before text", "feat: add\n\nDetails.", Emoji{"✨ ", EmojiBody}, "feat: add\n\n✨\n\nDetails."},
+		{"footer", "feat: add", Emoji{"✨ ", EmojiFooter}, "feat: add\n\nEmoji: ✨"},
+		{"footer with others", "feat: add\n\nRefs: #12", Emoji{"✨ ", EmojiFooter}, "feat: add\n\nRefs: #12\nEmoji: ✨"},
+		{"no emoji", "feat: add", Emoji{"", EmojiFooter}, "feat: add"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AddEmoji(tt.msg, tt.emoji); got != tt.want {
+				t.Errorf("AddEmoji() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkBreaking(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want string
+	}{
+		{"feat: add", "feat: add"},
+		{"feat(api): drop v1\n\nBREAKING CHANGE: v1 is gone", "feat(api)!: drop v1\n\nBREAKING CHANGE: v1 is gone"},
+		{"feat!: drop v1\n\nBREAKING CHANGE: v1 is gone", "feat!: drop v1\n\nBREAKING CHANGE: v1 is gone"},
+		{"drop v1\n\nBREAKING CHANGE: v1 is gone", "drop v1\n\nBREAKING CHANGE: v1 is gone"},
+	}
+	for _, tt := range tests {
+		if got := MarkBreaking(tt.msg); got != tt.want {
+			t.Errorf("MarkBreaking(%q) = %q, want %q", tt.msg, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package message
+
+import (
+	"regexp"
+	"strings"
+)
+
+// FooterPattern matches a git trailer style footer such as "Refs: #12" or
+// "Closes #12".
+var FooterPattern = regexp.MustCompile(`^([A-Za-z][\w-]*|BREAKING CHANGE)(?:: | #)(.*)$`)
+
+// Footer is a key and value from the footer paragraph of a message.
+type Footer struct {
+	Key   string
+	Value string
+}
+
+// ParseFooters returns the footers in the last paragraph of a message. The
+// paragraph only counts as footers when every line is one.
+func ParseFooters(msg string) []Footer {
+	paragraphs := strings.Split(strings.TrimSpace(msg), "\n\n")
+	if len(paragraphs) < 2 {
+		return nil
+	}
+
+	var footers []Footer
+	for _, line := range strings.Split(paragraphs[len(paragraphs)-1], "\n") {
+		m := FooterPattern.FindStringSubmatch(line)
+		if m == nil {
+			return nil
+		}
+		footers = append(footers, Footer{Key: m[1], Value: strings.TrimSpace(m[2])})
+	}
+	return footers
+}
+
+// FooterValues returns the values of all footers with key. Keys are
+// compared case-insensitively, as git does for trailers.
+func FooterValues(footers []Footer, key string) []string {
+	var values []string
+	for _, f := range footers {
+		if strings.EqualFold(f.Key, key) {
+			values = append(values, f.Value)
+		}
+	}
+	return values
+}
+
+// SetFooter replaces the footers with key, or appends the footer to the
+// message's footer paragraph.
+func SetFooter(msg, key, value string) string {
+	line := key + ": " + value
+	msg = strings.TrimSpace(msg)
+	if ParseFooters(msg) == nil {
+		return msg + "\n\n" + line
+	}
+
+	cut := strings.LastIndex(msg, "\n\n")
+	var kept []string
+	for _, l := range strings.Split(msg[cut+2:], "\n") {
+		m := FooterPattern.FindStringSubmatch(l)
+		if !strings.EqualFold(m[1], key) {
+			kept = append(kept, l)
+		}
+	}
+	return msg[:cut+2] + strings.Join(append(kept, line), "\n")
+}
@@ -0,0 +1,45 @@
+package message
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFooters(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  string
+		want []Footer
+	}{
+		{"header only", "feat: add", nil},
+		{"footers", "feat: add\n\nRefs: #12\nCloses #3", []Footer{{"Refs", "#12"}, {"Closes", "3"}}},
+		{"breaking", "feat!: drop\n\nBREAKING CHANGE: v1 is gone", []Footer{{"BREAKING CHANGE", "v1 is gone"}}},
+		{"prose", "feat: add\n\nRefs: #12\nand some text", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseFooters(tt.msg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFooters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetFooter(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  string
+		want string
+	}{
+		{"new paragraph", "feat: add\n\nDetails.", "feat: add\n\nDetails.\n\nRefs: #7"},
+		{"append", "feat: add\n\nCloses #3", "feat: add\n\nCloses #3\nRefs: #7"},
+		{"replace", "feat: add\n\nrefs: #1\nCloses #3", "feat: add\n\nCloses #3\nRefs: #7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SetFooter(tt.msg, "Refs", "#7"); got != tt.want {
+				t.Errorf("SetFooter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
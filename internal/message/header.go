@@ -0,0 +1,26 @@
+// Package message parses and formats conventional commit messages.
+package message
+
+import "regexp"
+
+// HeaderPattern splits a conventional header into an optional emoji, type,
+// scope, breaking marker and subject.
+var HeaderPattern = regexp.MustCompile(`^(?:(?::\w+:|[^\w\s(]+)\s*)?(\w+)(?:\(([^)]*)\))?(!)?: (.*)$`)
+
+// Header is the parsed first line of a conventional commit message.
+type Header struct {
+	Type     string
+	Scope    string
+	Breaking bool
+	Subject  string
+}
+
+// ParseHeader splits a header line like "feat(api)!: add paging". It
+// reports false when the line is not a conventional header.
+func ParseHeader(line string) (Header, bool) {
+	m := HeaderPattern.FindStringSubmatch(line)
+	if m == nil {
+		return Header{}, false
+	}
+	return Header{Type: m[1], Scope: m[2], Breaking: m[3] == "!", Subject: m[4]}, true
+}
@@ -0,0 +1,49 @@
+package message
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseHeader(t *testing.T) {
+	tests := []struct {
+		line string
+		want Header
+		ok   bool
+	}{
+		{"feat: add paging", Header{Type: "feat", Subject: "add paging"}, true},
+		{"fix(api): handle nil", Header{Type: "fix", Scope: "api", Subject: "handle nil"}, true},
+		{"feat(api)!: drop v1", Header{Type: "feat", Scope: "api", Breaking: true, Subject: "drop v1"}, true},
+		{"✨ feat(ui): add menu", Header{Type: "feat", Scope: "ui", Subject: "add menu"}, true},
+		{":bug: fix: handle nil", Header{Type: "fix", Subject: "handle nil"}, true},
+		{"fix(a,b): two scopes", Header{Type: "fix", Scope: "a,b", Subject: "two scopes"}, true},
+		{"Add paging", Header{}, false},
+		{"feat:missing space", Header{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, ok := ParseHeader(tt.line)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("ParseHeader(%q) = %+v, %v; want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func FuzzParseHeader(f *testing.F) {
+	for _, seed := range []string{"feat(api)!: drop v1", ":bug: fix: handle nil", "✨ feat: add", "feat(: x", "\xff: \x00"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, line string) {
+		h, ok := ParseHeader(line)
+		if !ok {
+			return
+		}
+		if h.Type == "" {
+			t.Fatalf("ParseHeader(%q) accepted an empty type", line)
+		}
+		if !strings.HasSuffix(line, h.Subject) {
+			t.Fatalf("ParseHeader(%q) subject %q is not the end of the line", line, h.Subject)
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package message
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// irregularVerbs maps past tense and third person forms that suffix rules
+// get wrong to their base form.
+var irregularVerbs = map[string]string{
+	"made": "make", "wrote": "write", "rewrote": "rewrite", "built": "build",
+	"rebuilt": "rebuild", "ran": "run", "got": "get", "began": "begin",
+	"broke": "break", "chose": "choose", "drew": "draw", "fed": "feed",
+	"found": "find", "froze": "freeze", "gave": "give", "hid": "hide",
+	"kept": "keep", "led": "lead", "lost": "lose", "met": "meet", "paid": "pay",
+	"sent": "send", "spun": "spin", "stood": "stand", "took": "take",
+	"threw": "throw", "undid": "undo", "did": "do", "does": "do", "went": "go",
+	"goes": "go",
+}
+
+// baseVerbs are verbs commit subjects start with. Only their inflections
+// are rewritten, and they settle which of the possible base forms of
+// "removed" or "fixes" is meant.
+var baseVerbs = []string{
+	"add", "adjust", "allow", "align", "apply", "avoid", "bump", "cache", "change",
+	"clarify", "clean", "close", "configure", "convert", "copy", "correct", "create",
+	"deduplicate", "define", "delete", "deprecate", "detect", "disable", "document",
+	"drop", "enable", "enforce", "ensure", "expose", "extend", "extract", "fix",
+	"format", "generate", "handle", "harden", "ignore", "implement", "improve",
+	"include", "increase", "initialize", "inline", "introduce", "limit", "log",
+	"make", "merge", "migrate", "move", "normalize", "optimize", "parse", "pass",
+	"pin", "prevent", "reduce", "refactor", "regenerate", "release",
+	"reformat", "remove", "rename", "reorder", "replace", "resolve", "restore",
+	"retry", "return", "revert", "rewrite", "run", "sanitize", "save", "set",
+	"simplify", "skip", "sort", "split", "stop", "store", "support", "switch",
+	"tidy", "trim", "tweak", "update", "upgrade", "use", "validate", "verify", "wrap",
+}
+
+// Imperative rewrites a summary starting with "added", "adds" or
+// "adding" to start with "add", as conventional subjects do. Summaries
+// already in the imperative are returned unchanged.
+func Imperative(summary string) string {
+	word, rest, _ := strings.Cut(summary, " ")
+	if word == "" || IsImperative(word) && irregularVerbs[strings.ToLower(word)] == "" {
+		return summary
+	}
+
+	base := BaseVerb(strings.ToLower(word))
+	if base == "" {
+		return summary
+	}
+	if first, _ := utf8.DecodeRuneInString(word); unicode.IsUpper(first) {
+		base = strings.ToUpper(base[:1]) + base[1:]
+	}
+	if rest == "" {
+		return base
+	}
+	return base + " " + rest
+}
+
+// BaseVerb returns the base form of an inflected verb, or "" when the word
+// is not an inflection of a known verb.
+func BaseVerb(word string) string {
+	if base, ok := irregularVerbs[word]; ok {
+		return base
+	}
+
+	var candidates []string
+	switch {
+	case strings.HasSuffix(word, "ied"), strings.HasSuffix(word, "ies"):
+		candidates = append(candidates, word[:len(word)-3]+"y")
+	case strings.HasSuffix(word, "ed"):
+		stem := word[:len(word)-2]
+		candidates = append(candidates, stem+"e", stem, undouble(stem))
+	case strings.HasSuffix(word, "ing"):
+		stem := word[:len(word)-3]
+		candidates = append(candidates, stem+"e", stem, undouble(stem))
+	case strings.HasSuffix(word, "es"):
+		candidates = append(candidates, word[:len(word)-1], word[:len(word)-2])
+	case strings.HasSuffix(word, "s"):
+		candidates = append(candidates, word[:len(word)-1])
+	default:
+		return ""
+	}
+
+	// Unknown words are left alone: "saved" has no rule that gives "save"
+	// without also turning "embed" into "emb"
+	for _, c := range candidates {
+		if slices.Contains(baseVerbs, c) {
+			return c
+		}
+	}
+	return ""
+}
+
+// undouble drops a doubled final consonant: "stopp" becomes "stop".
+func undouble(stem string) string {
+	n := len(stem)
+	if n < 3 || stem[n-1] != stem[n-2] || strings.ContainsRune("aeiousl", rune(stem[n-1])) {
+		return stem
+	}
+	return stem[:n-1]
+}
+
+// IsImperative is a cheap check that a verb is not past tense, a gerund
+// or third person ("added", "adding", "adds").
+func IsImperative(word string) bool {
+	word = strings.ToLower(word)
+	if len(word) < 4 {
+		return true
+	}
+	switch {
+	case strings.HasSuffix(word, "ed") && !strings.HasSuffix(word, "eed"):
+		return false
+	case strings.HasSuffix(word, "ing"):
+		return false
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us"):
+		return false
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package message
+
+import "testing"
+
+func TestImperative(t *testing.T) {
+	tests := []struct {
+		summary string
+		want    string
+	}{
+		{"add paging", "add paging"},
+		{"added paging", "add paging"},
+		{"adds paging", "add paging"},
+		{"adding paging", "add paging"},
+		{"Fixed crash on exit", "Fix crash on exit"},
+		{"fixes crash", "fix crash"},
+		{"removed dead code", "remove dead code"},
+		{"stopped retrying", "stop retrying"},
+		{"wrote docs", "write docs"},
+		{"copied files", "copy files"},
+		{"tweaked settings", "tweak settings"},
+		{"saved drafts", "save drafts"},
+		{"declared constants", "declared constants"},
+		{"embed assets", "embed assets"},
+		{"varied timeouts", "varied timeouts"},
+		{"tests for parser", "tests for parser"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.summary, func(t *testing.T) {
+			if got := Imperative(tt.summary); got != tt.want {
+				t.Errorf("Imperative(%q) = %q, want %q", tt.summary, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsImperative(t *testing.T) {
+	tests := []struct {
+		word string
+		want bool
+	}{
+		{"add", true},
+		{"added", false},
+		{"adding", false},
+		{"adds", false},
+		{"proceed", true},
+		{"address", true},
+		{"focus", true},
+	}
+	for _, tt := range tests {
+		if got := IsImperative(tt.word); got != tt.want {
+			t.Errorf("IsImperative(%q) = %v, want %v", tt.word, got, tt.want)
+		}
+	}
+}
+
+func FuzzImperative(f *testing.F) {
+	for _, seed := range []string{"added paging", "Stopped", "ies", "ed x", "ÉTÉ fixed"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, summary string) {
+		Imperative(summary)
+	})
+}
@@ -0,0 +1,85 @@
+package message
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultLanguage is used for missing text and unknown languages.
+const defaultLanguage = "en"
+
+//go:embed locales/*.yaml
+var localeFiles embed.FS
+
+var (
+	// language is the language of generated message text, independent of
+	// the UI locale. The catalogs are only parsed once text is needed.
+	language = defaultLanguage
+	catalogs = sync.OnceValue(loadCatalogs)
+)
+
+func loadCatalogs() map[string]map[string]string {
+	entries, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+
+	catalogs := map[string]map[string]string{}
+	for _, entry := range entries {
+		data, err := localeFiles.ReadFile("locales/" + entry.Name())
+		if err != nil {
+			panic(err)
+		}
+
+		catalog := map[string]string{}
+		if err := yaml.Unmarshal(data, &catalog); err != nil {
+			panic(fmt.Sprintf("invalid message locale %s: %v", entry.Name(), err))
+		}
+		catalogs[strings.TrimSuffix(entry.Name(), ".yaml")] = catalog
+	}
+	return catalogs
+}
+
+// SetLanguage makes lang, like "tr", the language of generated text. It
+// reports false, leaving the language alone, when there is no text in
+// lang.
+func SetLanguage(lang string) bool {
+	if _, ok := catalogs()[lang]; !ok {
+		return false
+	}
+	language = lang
+	return true
+}
+
+// Language returns the language of generated text.
+func Language() string {
+	return language
+}
+
+// Text returns generated message text for key, formatted with args. Keys
+// missing in the language fall back to English, and unknown keys are
+// returned as they are.
+func Text(key string, args ...any) string {
+	text, ok := catalogs()[language][key]
+	if !ok {
+		text, ok = catalogs()[defaultLanguage][key]
+	}
+	if !ok {
+		text = key
+	}
+
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// HasText reports whether there is generated text for key.
+func HasText(key string) bool {
+	_, ok := catalogs()[defaultLanguage][key]
+	return ok
+}
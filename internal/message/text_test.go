@@ -0,0 +1,23 @@
+package message
+
+import "testing"
+
+func TestText(t *testing.T) {
+	t.Cleanup(func() { language = defaultLanguage })
+
+	if got := Text("summary.add_topic", "paging"); got != "add paging functionality" {
+		t.Errorf("Text() = %q, want the English text", got)
+	}
+	if SetLanguage("xx") || Language() != defaultLanguage {
+		t.Error("SetLanguage() accepted a language without text")
+	}
+	if !SetLanguage("tr") || Text("summary.add_topic", "paging") == "add paging functionality" {
+		t.Error("SetLanguage(tr) did not switch the text")
+	}
+	if got := Text("no.such.key"); got != "no.such.key" {
+		t.Errorf("Text() of an unknown key = %q, want the key", got)
+	}
+	if HasText("no.such.key") || !HasText("summary.default") {
+		t.Error("HasText() does not match the catalog")
+	}
+}
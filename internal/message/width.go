@@ -0,0 +1,24 @@
+package message
+
+import (
+	"github.com/mattn/go-runewidth"
+)
+
+// emojiPresentation is the variation selector that renders a symbol such
+// as "♻" as a wide emoji.
+const emojiPresentation = '\ufe0f'
+
+// Width is the number of terminal columns s takes. CJK characters
+// and emoji take two, combining marks none, so limits hold for what git
+// log and web UIs actually show.
+func Width(s string) int {
+	width := runewidth.StringWidth(s)
+	var previous rune
+	for _, r := range s {
+		if r == emojiPresentation && runewidth.RuneWidth(previous) == 1 {
+			width++
+		}
+		previous = r
+	}
+	return width
+}
@@ -0,0 +1,87 @@
+package message
+
+import (
+	"regexp"
+	"strings"
+)
+
+// listItemPattern matches the marker of a markdown list item, such as
+// "- ", "  * " or "1. ".
+var listItemPattern = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+`)
+
+// codeFencePattern matches the line opening or closing a fenced code block.
+var codeFencePattern = regexp.MustCompile("^\\s*(```|~~~)")
+
+// WrapLines wraps the wrappable lines longer than limit at width,
+// skipping fenced code blocks. It reports whether any line was wrapped.
+func WrapLines(lines []string, limit, width int) ([]string, bool) {
+	overlong := Overlong(lines, limit)
+	wrapped := len(overlong) > 0
+	var out []string
+	for i, line := range lines {
+		if len(overlong) > 0 && overlong[0] == i {
+			out = append(out, WrapLine(line, width)...)
+			overlong = overlong[1:]
+			continue
+		}
+		out = append(out, line)
+	}
+	return out, wrapped
+}
+
+// Overlong returns the indexes of the wrappable lines longer than limit,
+// outside fenced code blocks: the lines WrapLines wraps.
+func Overlong(lines []string, limit int) []int {
+	var overlong []int
+	fenced := false
+	for i, line := range lines {
+		if codeFencePattern.MatchString(line) {
+			fenced = !fenced
+		}
+		if !fenced && Width(line) > limit && Wrappable(line) {
+			overlong = append(overlong, i)
+		}
+	}
+	return overlong
+}
+
+// Wrappable reports whether a body line is prose or a list item. Indented
+// code, headings, tables and lines with URLs are left alone.
+func Wrappable(line string) bool {
+	if strings.Contains(line, "://") {
+		return false
+	}
+	if listItemPattern.MatchString(line) {
+		return true
+	}
+	return !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") &&
+		!strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "|")
+}
+
+// WrapLine breaks a line at word boundaries. Continuation lines of list
+// items are indented to the item's text.
+func WrapLine(line string, width int) []string {
+	lead := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+	indent := ""
+	if m := listItemPattern.FindString(line); m != "" {
+		indent = strings.Repeat(" ", len(m))
+	}
+
+	var out []string
+	current := ""
+	for _, word := range strings.Fields(line) {
+		switch {
+		case current == "":
+			current = lead + word
+			if len(out) > 0 {
+				current = indent + word
+			}
+		case Width(current)+1+Width(word) > width:
+			out = append(out, current)
+			current = indent + word
+		default:
+			current += " " + word
+		}
+	}
+	return append(out, current)
+}
@@ -0,0 +1,87 @@
+package message
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"feat: add", 9},
+		{"修正", 4},
+		{"✨", 2},
+		{"♻️", 2},
+		{"é", 1},
+	}
+	for _, tt := range tests {
+		if got := Width(tt.s); got != tt.want {
+			t.Errorf("Width(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestWrapLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		width int
+		want  []string
+	}{
+		{"short", "fits on one line", 20, []string{"fits on one line"}},
+		{"prose", "one two three four", 9, []string{"one two", "three", "four"}},
+		{"list item", "- one two three", 9, []string{"- one two", "  three"}},
+		{"numbered item", "10. one two three", 11, []string{"10. one two", "    three"}},
+		{"indented item", "  * one two", 8, []string{"  * one", "    two"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WrapLine(tt.line, tt.width); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WrapLine(%q, %d) = %q, want %q", tt.line, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapLines(t *testing.T) {
+	long := strings.Repeat("word ", 20)
+	tests := []struct {
+		name    string
+		lines   []string
+		wrapped bool
+	}{
+		{"prose", []string{long}, true},
+		{"short", []string{"short"}, false},
+		{"url", []string{long + "https://example.com"}, false},
+		{"code", []string{"    " + long}, false},
+		{"fenced", []string{"```", long, "```"}, false},
+		{"table", []string{"| " + long + " |"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, wrapped := WrapLines(tt.lines, 72, 72)
+			if wrapped != tt.wrapped {
+				t.Errorf("wrapped = %v, want %v: %q", wrapped, tt.wrapped, out)
+			}
+			for _, line := range out {
+				if tt.wrapped && Width(line) > 72 {
+					t.Errorf("line longer than 72 columns: %q", line)
+				}
+			}
+		})
+	}
+}
+
+func FuzzWrapLine(f *testing.F) {
+	f.Add("- one two three four", 9)
+	f.Add("\t\t\xff\xfe 修正 ♻️", 1)
+	f.Fuzz(func(t *testing.T, line string, width int) {
+		out := WrapLine(line, width)
+		if strings.Join(strings.Fields(strings.Join(out, " ")), " ") != strings.Join(strings.Fields(line), " ") {
+			t.Fatalf("WrapLine(%q, %d) = %q changed the words", line, width, out)
+		}
+	})
+}
@@ -0,0 +1,218 @@
+package summary
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/detect"
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/barisdilekci/commitz/internal/message"
+)
+
+// Kinds of exported API change.
+const (
+	apiRemoved = "removed"
+	apiChanged = "changed"
+)
+
+// apiChange is an exported Go identifier that was removed or changed.
+type apiChange struct {
+	Kind string
+	// Symbol is qualified by package, e.g. store.Store.Get
+	Symbol string
+}
+
+// apiBreakingChanges compares the exported API of the Go packages with
+// staged changes between HEAD and the index. Only packages where lines were
+// removed are compared, since additions cannot break callers. Commands,
+// internal packages and tests are skipped.
+func apiBreakingChanges(files []git.File, gitOutput Git) []apiChange {
+	dirs := map[string]bool{}
+	for _, f := range files {
+		for _, p := range []string{f.OldPath, f.Path} {
+			if p == "" || !strings.HasSuffix(p, ".go") || strings.HasSuffix(p, "_test.go") {
+				continue
+			}
+			if f.Deletions > 0 || f.Deleted || f.OldPath != f.Path {
+				dirs[path.Dir(p)] = true
+			}
+		}
+	}
+
+	var changes []apiChange
+	for dir := range dirs {
+		if !publicPackageDir(dir) {
+			continue
+		}
+		before, name := packageAPI(dir, "HEAD", gitOutput)
+		if name == "" || name == "main" {
+			continue
+		}
+		after, _ := packageAPI(dir, "", gitOutput)
+		for symbol, old := range before {
+			qualified := name + "." + strings.SplitN(symbol, " ", 2)[1]
+			value, ok := after[symbol]
+			switch {
+			case !ok:
+				changes = append(changes, apiChange{Kind: apiRemoved, Symbol: qualified})
+			case value != old:
+				changes = append(changes, apiChange{Kind: apiChanged, Symbol: qualified})
+			}
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Symbol < changes[j].Symbol })
+	return changes
+}
+
+func publicPackageDir(dir string) bool {
+	for _, part := range strings.Split(dir, "/") {
+		if part == "internal" || part == "testdata" || slices.Contains(detect.VendoredDirs, part) {
+			return false
+		}
+	}
+	return true
+}
+
+// packageAPI maps the exported declarations of a package at HEAD, or in
+// the index when rev is empty, to their printed signatures. It also
+// returns the package name.
+func packageAPI(dir, rev string, gitOutput Git) (map[string]string, string) {
+	var paths []string
+	if rev != "" {
+		tree := dir
+		if tree == "." {
+			tree = ""
+		}
+		out, err := gitOutput("ls-tree", "--name-only", rev+":"+tree)
+		if err != nil {
+			return nil, ""
+		}
+		for _, name := range strings.Fields(string(out)) {
+			paths = append(paths, path.Join(dir, name))
+		}
+	} else {
+		out, err := gitOutput("ls-files", "--full-name", "--", ":(top,glob)"+path.Join(dir, "*.go"))
+		if err != nil {
+			return nil, ""
+		}
+		paths = strings.Fields(string(out))
+	}
+
+	api := map[string]string{}
+	pkgName := ""
+	fset := token.NewFileSet()
+	for _, p := range paths {
+		if !strings.HasSuffix(p, ".go") || strings.HasSuffix(p, "_test.go") {
+			continue
+		}
+		src, err := gitOutput("show", rev+":"+p)
+		if err != nil {
+			continue
+		}
+		file, err := parser.ParseFile(fset, p, src, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		pkgName = file.Name.Name
+		collectAPI(api, fset, file)
+	}
+	return api, pkgName
+}
+
+// collectAPI adds a file's exported functions, methods, types, struct
+// fields, constants and variables. Struct fields are listed one by one so
+// that adding a field does not count as a change.
+func collectAPI(api map[string]string, fset *token.FileSet, file *ast.File) {
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if !d.Name.IsExported() {
+				continue
+			}
+			if d.Recv == nil {
+				api["func "+d.Name.Name] = printNode(fset, d.Type)
+				continue
+			}
+			if recv := receiverName(d.Recv.List[0].Type); ast.IsExported(recv) {
+				api["method "+recv+"."+d.Name.Name] = printNode(fset, d.Type)
+			}
+
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					if !s.Name.IsExported() {
+						continue
+					}
+					params := ""
+					if s.TypeParams != nil {
+						params = printNode(fset, s.TypeParams)
+					}
+					st, ok := s.Type.(*ast.StructType)
+					if !ok {
+						api["type "+s.Name.Name] = params + " " + printNode(fset, s.Type)
+						continue
+					}
+					api["type "+s.Name.Name] = params + " struct"
+					for _, field := range st.Fields.List {
+						for _, name := range field.Names {
+							if name.IsExported() {
+								api["field "+s.Name.Name+"."+name.Name] = printNode(fset, field.Type)
+							}
+						}
+					}
+				case *ast.ValueSpec:
+					for _, name := range s.Names {
+						if name.IsExported() {
+							api[d.Tok.String()+" "+name.Name] = printNode(fset, s.Type)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// receiverName returns T for receivers like T, *T and *T[K].
+func receiverName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(e.X)
+	case *ast.IndexExpr:
+		return receiverName(e.X)
+	case *ast.IndexListExpr:
+		return receiverName(e.X)
+	case *ast.Ident:
+		return e.Name
+	}
+	return ""
+}
+
+// printNode prints a node on one line, without comments.
+func printNode(fset *token.FileSet, node ast.Node) string {
+	if node == nil {
+		return ""
+	}
+	var b bytes.Buffer
+	printer.Fprint(&b, fset, node)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// apiBreakingFooter drafts the footer for removed or changed identifiers.
+func apiBreakingFooter(changes []apiChange) string {
+	if len(changes) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(changes))
+	for _, c := range changes {
+		parts = append(parts, message.Text("api."+c.Kind, c.Symbol))
+	}
+	return "BREAKING CHANGE: " + message.Text("api.breaking", strings.Join(parts, ", "))
+}
@@ -0,0 +1,76 @@
+package summary
+
+import (
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/detect"
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/barisdilekci/commitz/internal/message"
+)
+
+// Body drafts the body for a change summarized by subject.
+func Body(files []git.File, commitType, subject string, gitOutput Git) string {
+	relevant, noise := detect.SplitNoise(files)
+
+	var sections []string
+	if body := changeBody(relevant, commitType, subject); body != "" {
+		sections = append(sections, body)
+	}
+
+	// Mention excluded files separately when there is a real change too
+	if len(relevant) > 0 && len(noise) > 0 && !onlyGeneratorInputs(relevant, noise) {
+		lines := []string{message.Text("body.also")}
+		for _, phrase := range noiseSummaries(noise, relevant) {
+			lines = append(lines, "- "+phrase)
+		}
+		sections = append(sections, strings.Join(lines, "\n"))
+	}
+
+	if OnlyConfigFiles(relevant) {
+		if _, changes, ok := ConfigSummary(relevant, gitOutput); ok {
+			if body := configBody(changes); body != "" {
+				sections = append(sections, body)
+			}
+		}
+	}
+
+	// Schema changes that break deployed code are called out as breaking
+	if footer := migrationBreakingFooter(ParseMigrations(relevant)); footer != "" {
+		sections = append(sections, footer)
+	}
+	if footer := apiBreakingFooter(apiBreakingChanges(relevant, gitOutput)); footer != "" {
+		sections = append(sections, footer)
+	}
+
+	return strings.Join(sections, "\n\n")
+}
+
+func changeBody(files []git.File, commitType, subject string) string {
+	tests, others := detect.SplitTests(files)
+
+	// Many files, or more than the subject can name: list every change so
+	// the subject can stay short
+	if len(files) >= manyFilesThreshold || (!OnlyConfigFiles(files) && !subjectCovers(others, subject)) {
+		lines := make([]string, 0, len(files))
+		for _, f := range files {
+			lines = append(lines, "- "+describeFileChange(f))
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	if commitType == "test" || len(others) == 0 {
+		return ""
+	}
+
+	lines := []string{message.Text("body.test_coverage")}
+	for _, f := range tests {
+		if !f.Deleted {
+			lines = append(lines, "- "+f.Path)
+		}
+	}
+	if len(lines) == 1 {
+		return ""
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,46 @@
+package summary
+
+import "testing"
+
+func TestBody(t *testing.T) {
+	tests := []struct {
+		name       string
+		diff       string
+		commitType string
+		subject    string
+		objects    map[string]string
+		want       string
+	}{
+		{"single file", stagedFile("auth/login.go", false, "return nil"), "feat", "add login functionality", nil, ""},
+		{"tests", stagedFile("auth/login.go", false, "return nil") + stagedFile("auth/login_test.go", true, "func TestLogin() {}"),
+			"feat", "add login functionality", nil, "Add test coverage in:\n- auth/login_test.go"},
+		{"test type", stagedFile("auth/login.go", false, "return nil") + stagedFile("auth/login_test.go", true, "func TestLogin() {}"),
+			"test", "add login functionality", nil, ""},
+		{"subject misses a file", stagedFile("auth/login.go", false, "return nil") + stagedFile("store/users.go", false, "x := 1"),
+			"feat", "add login functionality", nil, "- update auth/login.go (+1/-0)\n- update store/users.go (+1/-0)"},
+		{"many files", stagedFile("a.go", true, "func A() {}") + deletedFile("b.go") + stagedFile("c_test.go", false, "x := 1"),
+			"refactor", "refactor code structure", nil, "- add a.go\n- remove b.go\n- update tests in c_test.go"},
+		{"noise", stagedFile("main.go", false, "x := 1") + stagedFile("go.sum", false, "h1:abc="),
+			"feat", "add main functionality", nil, "Also:\n- update dependencies"},
+		{"breaking migration", stagedFile("db/migrations/0003_drop.up.sql", true, "ALTER TABLE users DROP COLUMN email;"),
+			"feat", "drop users.email column", nil,
+			"BREAKING CHANGE: the migration is not backwards compatible: drop users.email column"},
+		{"removed API", "diff --git a/store/store.go b/store/store.go\n--- a/store/store.go\n+++ b/store/store.go\n@@ -1,3 +1,2 @@\n package store\n-func Get() {}\n",
+			"refactor", "refactor store", map[string]string{
+				"HEAD:store/store.go": "package store\n\nfunc Get() {}\n\nfunc Put() {}\n",
+				":store/store.go":     "package store\n\nfunc Put() {}\n",
+			}, "BREAKING CHANGE: exported API changed: store.Get was removed"},
+		{"internal API", "diff --git a/internal/store/store.go b/internal/store/store.go\n--- a/internal/store/store.go\n+++ b/internal/store/store.go\n@@ -1,3 +1,2 @@\n package store\n-func Get() {}\n",
+			"refactor", "refactor store", map[string]string{
+				"HEAD:internal/store/store.go": "package store\n\nfunc Get() {}\n",
+				":internal/store/store.go":     "package store\n",
+			}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Body(parseFiles(tt.diff), tt.commitType, tt.subject, fakeGit(tt.objects)); got != tt.want {
+				t.Errorf("Body() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package summary
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/barisdilekci/commitz/internal/message"
+)
+
+// Kinds of config change.
+const (
+	configAdded   = "added"
+	configRemoved = "removed"
+	configChanged = "changed"
+)
+
+// maxConfigValue is the longest value quoted in a summary.
+const maxConfigValue = 20
+
+// ConfigChange is one key that differs between HEAD and the index.
+type ConfigChange struct {
+	Kind string
+	// Key is the dotted path, e.g. client.retries.enabled
+	Key string
+	Old string
+	New string
+}
+
+// isConfigFile reports whether the path is a YAML, JSON or TOML file that
+// configures the project rather than CI or dependencies.
+func isConfigFile(path string) bool {
+	slashed := filepath.ToSlash(path)
+	base := filepath.Base(slashed)
+	switch {
+	case strings.HasPrefix(slashed, ".github/"), base == ".gitlab-ci.yml",
+		strings.HasPrefix(slashed, ".circleci/"), base == "package.json",
+		base == "composer.json", base == "Cargo.toml", base == "pyproject.toml":
+		return false
+	}
+	switch strings.ToLower(filepath.Ext(base)) {
+	case ".yaml", ".yml", ".json", ".toml":
+		return true
+	}
+	return false
+}
+
+// OnlyConfigFiles reports whether every file is a config file.
+func OnlyConfigFiles(files []git.File) bool {
+	if len(files) == 0 {
+		return false
+	}
+	for _, f := range files {
+		if !isConfigFile(f.Path) {
+			return false
+		}
+	}
+	return true
+}
+
+// configChanges compares the keys of a config file at HEAD and in the
+// index. It reports false when either version cannot be parsed.
+func configChanges(f git.File, gitOutput Git) ([]ConfigChange, bool) {
+	var before, after map[string]string
+	var err error
+	if !f.IsNew {
+		content, _ := gitOutput("show", "HEAD:"+f.OldPath)
+		if before, err = flattenConfig(f.OldPath, content); err != nil {
+			return nil, false
+		}
+	}
+	if !f.Deleted {
+		content, _ := gitOutput("show", ":"+f.Path)
+		if after, err = flattenConfig(f.Path, content); err != nil {
+			return nil, false
+		}
+	}
+
+	var changes []ConfigChange
+	for key, old := range before {
+		value, ok := after[key]
+		switch {
+		case !ok:
+			changes = append(changes, ConfigChange{Kind: configRemoved, Key: key, Old: old})
+		case value != old:
+			changes = append(changes, ConfigChange{Kind: configChanged, Key: key, Old: old, New: value})
+		}
+	}
+	for key, value := range after {
+		if _, ok := before[key]; !ok {
+			changes = append(changes, ConfigChange{Kind: configAdded, Key: key, New: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes, true
+}
+
+// flattenConfig parses a config file into dotted keys and their values.
+// Lists are compared as a whole.
+func flattenConfig(path string, content []byte) (map[string]string, error) {
+	flat := map[string]string{}
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		return parseTOMLKeys(string(content)), nil
+	}
+
+	var doc any
+	var err error
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(content, &doc)
+	} else {
+		err = yaml.Unmarshal(content, &doc)
+	}
+	if err != nil {
+		return nil, err
+	}
+	flattenValue(flat, "", doc)
+	return flat, nil
+}
+
+func flattenValue(flat map[string]string, prefix string, value any) {
+	m, ok := value.(map[string]any)
+	if !ok || len(m) == 0 {
+		if prefix != "" {
+			flat[prefix] = fmt.Sprint(value)
+		}
+		return
+	}
+	for key, v := range m {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		flattenValue(flat, key, v)
+	}
+}
+
+// parseTOMLKeys reads the keys of a TOML file well enough to compare
+// versions: tables, dotted keys and values, with multi-line arrays joined.
+func parseTOMLKeys(content string) map[string]string {
+	flat := map[string]string{}
+	table := ""
+	var pendingKey, pendingValue string
+	depth := 0
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if depth > 0 {
+			pendingValue += " " + line
+			depth += strings.Count(line, "[") - strings.Count(line, "]")
+			if depth <= 0 {
+				flat[pendingKey] = pendingValue
+				depth = 0
+			}
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			table = strings.Trim(line, "[] ")
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.Trim(strings.TrimSpace(key), `"'`)
+		if table != "" {
+			key = table + "." + key
+		}
+		value = strings.TrimSpace(value)
+		if !strings.HasPrefix(value, `"`) && !strings.HasPrefix(value, "'") {
+			value, _, _ = strings.Cut(value, " #")
+		}
+		value = strings.Trim(value, `"'`)
+
+		if depth = strings.Count(value, "[") - strings.Count(value, "]"); depth > 0 {
+			pendingKey, pendingValue = key, value
+			continue
+		}
+		flat[key] = value
+	}
+	return flat
+}
+
+// configName is how a summary refers to a config file: "client config" for
+// config/client.yaml.
+func configName(path string) string {
+	base := filepath.Base(path)
+	name := strings.TrimSuffix(base, filepath.Ext(base))
+	name = strings.TrimPrefix(name, ".")
+	for _, generic := range []string{"config", "settings", "values", "application", "app"} {
+		if strings.EqualFold(name, generic) {
+			if dir := filepath.Base(filepath.Dir(path)); dir != "." && dir != "config" {
+				return dir
+			}
+			return name
+		}
+	}
+	return name
+}
+
+// settingName shortens a dotted key for a summary, keeping the part before
+// flags like "enabled": client.retries.enabled becomes "retries".
+func settingName(key string) string {
+	parts := strings.Split(key, ".")
+	last := parts[len(parts)-1]
+	if len(parts) > 1 && slices.Contains([]string{"enabled", "enable", "disabled", "disable", "on", "active"}, strings.ToLower(last)) {
+		return parts[len(parts)-2]
+	}
+	return last
+}
+
+// phrase describes one change, e.g. "enable retries" or "set timeout to
+// 30s".
+func (c ConfigChange) phrase() string {
+	name := settingName(c.Key)
+	switch c.Kind {
+	case configAdded:
+		return message.Text("config.add", name)
+	case configRemoved:
+		return message.Text("config.remove", name)
+	}
+
+	disabling := strings.HasSuffix(strings.ToLower(c.Key), "disabled") || strings.HasSuffix(strings.ToLower(c.Key), "disable")
+	switch {
+	case c.New == "true" && !disabling, c.New == "false" && disabling:
+		return message.Text("config.enable", name)
+	case c.New == "false" && !disabling, c.New == "true" && disabling:
+		return message.Text("config.disable", name)
+	case c.New != "" && len(c.New) <= maxConfigValue && !strings.ContainsAny(c.New, "\n[]{}"):
+		return message.Text("config.set", name, c.New)
+	}
+	return message.Text("config.change", name)
+}
+
+// ConfigSummary describes the changes of config-only commits. It reports
+// false when the files cannot be compared or nothing changed.
+func ConfigSummary(files []git.File, gitOutput Git) (string, []ConfigChange, bool) {
+	var all []ConfigChange
+	var name string
+	for _, f := range files {
+		changes, ok := configChanges(f, gitOutput)
+		if !ok {
+			return "", nil, false
+		}
+		if len(changes) > 0 {
+			all = append(all, changes...)
+			name = configName(f.Path)
+		}
+	}
+	if len(all) == 0 {
+		return "", nil, false
+	}
+
+	target := message.Text("config.target", name)
+	if len(files) > 1 {
+		target = message.Text("config.target_many")
+	}
+	switch len(all) {
+	case 1:
+		return message.Text("config.summary", all[0].phrase(), target), all, true
+	case 2:
+		return message.Text("config.summary", message.Text("config.two", all[0].phrase(), all[1].phrase()), target), all, true
+	}
+	return message.Text("config.many", len(all), target), all, true
+}
+
+// configBody lists each key change when the summary could not name them.
+func configBody(changes []ConfigChange) string {
+	if len(changes) <= 2 {
+		return ""
+	}
+	lines := make([]string, 0, len(changes))
+	for _, c := range changes {
+		switch c.Kind {
+		case configAdded:
+			lines = append(lines, "- "+message.Text("config.body_add", c.Key))
+		case configRemoved:
+			lines = append(lines, "- "+message.Text("config.body_remove", c.Key))
+		default:
+			lines = append(lines, "- "+message.Text("config.body_change", c.Key, truncateValue(c.Old), truncateValue(c.New)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func truncateValue(value string) string {
+	if len(value) > maxConfigValue {
+		return value[:maxConfigValue] + "…"
+	}
+	return value
+}
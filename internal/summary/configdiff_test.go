@@ -0,0 +1,51 @@
+package summary
+
+import (
+	"testing"
+
+	"github.com/barisdilekci/commitz/internal/git"
+)
+
+func TestConfigSummary(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		before  string
+		after   string
+		summary string
+		body    string
+	}{
+		{"enable", "config/client.yaml", "retries:\n  enabled: false\n", "retries:\n  enabled: true\n", "enable retries in client config", ""},
+		{"disable flag", "config/client.yaml", "cache:\n  disabled: false\n", "cache:\n  disabled: true\n", "disable cache in client config", ""},
+		{"set value", "deploy/values.yaml", "timeout: 10s\n", "timeout: 30s\n", "set timeout to 30s in deploy config", ""},
+		{"two keys", "settings.json", `{"a": 1}`, `{"b": 2}`, "remove a and add b in settings config", ""},
+		{"toml", "conf/app.toml", "[server]\nport = 80\n", "[server]\nport = 8080 # public\n", "set port to 8080 in conf config", ""},
+		{"many keys", "config/client.yaml", "a: 1\nb: 2\n", "a: 3\nc: 4\nd: 5\n", "update 4 settings in client config",
+			"- change a from 1 to 3\n- remove b\n- add c\n- add d"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := []git.File{{Path: tt.path, OldPath: tt.path}}
+			gitOutput := fakeGit(map[string]string{"HEAD:" + tt.path: tt.before, ":" + tt.path: tt.after})
+			summary, changes, ok := ConfigSummary(files, gitOutput)
+			if !ok || summary != tt.summary {
+				t.Errorf("ConfigSummary() = %q, %v; want %q", summary, ok, tt.summary)
+			}
+			if body := configBody(changes); body != tt.body {
+				t.Errorf("configBody() = %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
+
+func TestConfigSummaryUnchanged(t *testing.T) {
+	files := []git.File{{Path: "config.yaml", OldPath: "config.yaml"}}
+	same := fakeGit(map[string]string{"HEAD:config.yaml": "a: 1\n", ":config.yaml": "a: 1 # comment\n"})
+	if _, _, ok := ConfigSummary(files, same); ok {
+		t.Error("ConfigSummary() reported a change for the same keys")
+	}
+	broken := fakeGit(map[string]string{"HEAD:config.yaml": "a: 1\n", ":config.yaml": "a: [\n"})
+	if _, _, ok := ConfigSummary(files, broken); ok {
+		t.Error("ConfigSummary() compared a file that does not parse")
+	}
+}
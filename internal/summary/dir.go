@@ -0,0 +1,31 @@
+package summary
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/git"
+)
+
+// CommonDir returns the deepest directory shared by all files, if any.
+func CommonDir(files []git.File) string {
+	if len(files) == 0 {
+		return ""
+	}
+
+	common := strings.Split(filepath.ToSlash(filepath.Dir(files[0].Path)), "/")
+	for _, f := range files[1:] {
+		parts := strings.Split(filepath.ToSlash(filepath.Dir(f.Path)), "/")
+		n := 0
+		for n < len(common) && n < len(parts) && common[n] == parts[n] {
+			n++
+		}
+		common = common[:n]
+	}
+
+	dir := strings.Join(common, "/")
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
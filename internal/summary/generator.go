@@ -0,0 +1,71 @@
+package summary
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/barisdilekci/commitz/internal/detect"
+	"github.com/barisdilekci/commitz/internal/git"
+)
+
+// Request is what a summary generator gets to work with.
+type Request struct {
+	Diff  string
+	Files []git.File
+	Type  string
+}
+
+// Generator suggests the summary of a commit. An empty summary without an
+// error passes the request on to the next generator.
+type Generator interface {
+	Name() string
+	Summary(req Request) (string, error)
+}
+
+// Heuristic is the built-in, diff-based summary.
+type Heuristic struct {
+	Git Git
+}
+
+func (Heuristic) Name() string { return "heuristic" }
+
+func (g Heuristic) Summary(req Request) (string, error) {
+	return Smart(req.Diff, req.Files, req.Type, g.Git), nil
+}
+
+// Template renders summary.template with the type, topic, common directory
+// and file paths of the change.
+type Template struct {
+	Text string
+}
+
+func (Template) Name() string { return "template" }
+
+func (g Template) Summary(req Request) (string, error) {
+	if g.Text == "" {
+		return "", nil
+	}
+	tmpl, err := template.New("summary.template").Option("missingkey=error").Parse(g.Text)
+	if err != nil {
+		return "", err
+	}
+
+	relevant, _ := detect.SplitNoise(req.Files)
+	paths := make([]string, len(relevant))
+	for i, f := range relevant {
+		paths[i] = f.Path
+	}
+	data := map[string]any{
+		"Type":  req.Type,
+		"Topic": Topic(relevant),
+		"Dir":   CommonDir(relevant),
+		"Files": paths,
+		"Count": len(paths),
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
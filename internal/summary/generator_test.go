@@ -0,0 +1,33 @@
+package summary
+
+import "testing"
+
+func TestTemplate(t *testing.T) {
+	diff := stagedFile("auth/login.go", false, "return nil") + stagedFile("auth/session.go", false, "x := 1") + stagedFile("go.sum", false, "h1:abc=")
+
+	tests := []struct {
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"{{.Type}}: {{.Count}} files in {{.Dir}}", "feat: 2 files in auth", false},
+		{"{{range .Files}}{{.}} {{end}}", "auth/login.go auth/session.go ", false},
+		{"{{.Ticket}}", "", true},
+		{"{{.Type", "", true},
+	}
+	for _, tt := range tests {
+		got, err := Template{Text: tt.text}.Summary(Request{Diff: diff, Files: parseFiles(diff), Type: "feat"})
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("Template{%q}.Summary() = %q, %v; want %q, error %v", tt.text, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestHeuristic(t *testing.T) {
+	diff := stagedFile("store/cache.go", false, "x := 1")
+	got, err := Heuristic{Git: fakeGit(nil)}.Summary(Request{Diff: diff, Files: parseFiles(diff), Type: "refactor"})
+	if err != nil || got != "refactor cache" {
+		t.Errorf("Heuristic.Summary() = %q, %v; want %q", got, err, "refactor cache")
+	}
+}
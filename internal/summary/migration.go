@@ -0,0 +1,268 @@
+package summary
+
+import (
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/barisdilekci/commitz/internal/message"
+)
+
+// Kinds of schema change, also the message keys under migration.
+const (
+	ddlCreateTable  = "create_table"
+	ddlDropTable    = "drop_table"
+	ddlRenameTable  = "rename_table"
+	ddlAddColumn    = "add_column"
+	ddlDropColumn   = "drop_column"
+	ddlRenameColumn = "rename_column"
+	ddlChangeType   = "change_type"
+	ddlNotNull      = "not_null"
+	ddlAddIndex     = "add_index"
+	ddlDropIndex    = "drop_index"
+)
+
+// SchemaChange is one schema change read from a migration.
+type SchemaChange struct {
+	Kind   string
+	Target string
+	// To is the new name of a renamed table or column
+	To string
+}
+
+// ddlPatterns read SQL statements and Rails and Django migration calls.
+// The submatches are the target parts and, for renames, the new name.
+var ddlPatterns = []struct {
+	kind    string
+	pattern *regexp.Regexp
+}{
+	{ddlAddIndex, regexp.MustCompile(`(?i)^create\s+(?:unique\s+)?index\s+(?:concurrently\s+)?(?:if\s+not\s+exists\s+)?\S+\s+on\s+(?:only\s+)?([\w."]+)\s*(?:using\s+\w+\s*)?\(\s*([\w"]+)`)},
+	{ddlCreateTable, regexp.MustCompile(`(?i)^create\s+table\s+(?:if\s+not\s+exists\s+)?([\w."]+)`)},
+	{ddlDropTable, regexp.MustCompile(`(?i)^drop\s+table\s+(?:if\s+exists\s+)?([\w."]+)`)},
+	{ddlDropIndex, regexp.MustCompile(`(?i)^drop\s+index\s+(?:concurrently\s+)?(?:if\s+exists\s+)?([\w."]+)`)},
+	{ddlRenameColumn, regexp.MustCompile(`(?i)^alter\s+table\s+(?:if\s+exists\s+)?([\w."]+)\s+rename\s+(?:column\s+)?([\w"]+)\s+to\s+([\w"]+)`)},
+	{ddlRenameTable, regexp.MustCompile(`(?i)^alter\s+table\s+(?:if\s+exists\s+)?([\w."]+)\s+rename\s+to\s+([\w."]+)`)},
+	{ddlAddColumn, regexp.MustCompile(`(?i)^alter\s+table\s+(?:if\s+exists\s+)?([\w."]+)\s+add\s+(?:column\s+)?(?:if\s+not\s+exists\s+)?([\w"]+)`)},
+	{ddlDropColumn, regexp.MustCompile(`(?i)^alter\s+table\s+(?:if\s+exists\s+)?([\w."]+)\s+drop\s+(?:column\s+)?(?:if\s+exists\s+)?([\w"]+)`)},
+	{ddlNotNull, regexp.MustCompile(`(?i)^alter\s+table\s+(?:if\s+exists\s+)?([\w."]+)\s+alter\s+(?:column\s+)?([\w"]+)\s+set\s+not\s+null`)},
+	{ddlChangeType, regexp.MustCompile(`(?i)^alter\s+table\s+(?:if\s+exists\s+)?([\w."]+)\s+(?:alter|modify)\s+(?:column\s+)?([\w"]+)\s+(?:set\s+data\s+)?type\b`)},
+
+	{ddlAddIndex, regexp.MustCompile(`^add_index\s*\(?\s*:(\w+),\s*\[?:(\w+)`)},
+	{ddlCreateTable, regexp.MustCompile(`^create_table\s*\(?\s*:(\w+)`)},
+	{ddlDropTable, regexp.MustCompile(`^drop_table\s*\(?\s*:(\w+)`)},
+	{ddlAddColumn, regexp.MustCompile(`^add_column\s*\(?\s*:(\w+),\s*:(\w+)`)},
+	{ddlDropColumn, regexp.MustCompile(`^remove_column\s*\(?\s*:(\w+),\s*:(\w+)`)},
+	{ddlRenameColumn, regexp.MustCompile(`^rename_column\s*\(?\s*:(\w+),\s*:(\w+),\s*:(\w+)`)},
+	{ddlChangeType, regexp.MustCompile(`^change_column\s*\(?\s*:(\w+),\s*:(\w+)`)},
+
+	{ddlAddIndex, regexp.MustCompile(`^migrations\.AddIndex\(\s*model_name=['"](\w+)['"],\s*index=models\.Index\(\s*fields=\[\s*['"](\w+)`)},
+	{ddlCreateTable, regexp.MustCompile(`^migrations\.CreateModel\(\s*name=['"](\w+)`)},
+	{ddlDropTable, regexp.MustCompile(`^migrations\.DeleteModel\(\s*name=['"](\w+)`)},
+	{ddlAddColumn, regexp.MustCompile(`^migrations\.AddField\(\s*model_name=['"](\w+)['"],\s*name=['"](\w+)`)},
+	{ddlDropColumn, regexp.MustCompile(`^migrations\.RemoveField\(\s*model_name=['"](\w+)['"],\s*name=['"](\w+)`)},
+	{ddlRenameColumn, regexp.MustCompile(`^migrations\.RenameField\(\s*model_name=['"](\w+)['"],\s*old_name=['"](\w+)['"],\s*new_name=['"](\w+)`)},
+	{ddlChangeType, regexp.MustCompile(`^migrations\.AlterField\(\s*model_name=['"](\w+)['"],\s*name=['"](\w+)`)},
+}
+
+// constraintKeywords follow ADD or DROP when a constraint, not a column, is
+// changed.
+var constraintKeywords = []string{"constraint", "primary", "foreign", "unique", "check", "index", "key"}
+
+// migrationCallStart begins a Rails or Django migration call, which ends
+// the statement before it.
+var migrationCallStart = regexp.MustCompile(`^(?:add_|remove_|rename_|change_|create_table|drop_table|migrations\.)`)
+
+// isMigrationFile reports whether the path looks like a schema migration.
+func isMigrationFile(path string) bool {
+	slashed := strings.ToLower(filepath.ToSlash(path))
+	base := filepath.Base(slashed)
+	if flywayPattern.MatchString(base) {
+		return true
+	}
+
+	inMigrations := false
+	for _, dir := range strings.Split(filepath.Dir(slashed), "/") {
+		if dir == "migrations" || dir == "migrate" || dir == "migration" {
+			inMigrations = true
+		}
+	}
+	switch filepath.Ext(base) {
+	case ".sql":
+		return inMigrations || strings.Contains(base, ".up.")
+	case ".rb", ".py":
+		return inMigrations
+	}
+	return false
+}
+
+// flywayPattern matches Flyway names like V2__add_email.sql.
+var flywayPattern = regexp.MustCompile(`^[vu]\d+(?:[._]\d+)*__\w+\.sql$`)
+
+// isDownMigration reports whether a migration undoes another one, like
+// 0002_idx.down.sql or Flyway's U2__add_email.sql.
+func isDownMigration(path string) bool {
+	base := strings.ToLower(filepath.Base(path))
+	return strings.Contains(base, ".down.") || strings.HasSuffix(base, "_down.sql") ||
+		(flywayPattern.MatchString(base) && base[0] == 'u')
+}
+
+// ParseMigrations reads the schema changes added in migration files. Down
+// migrations are left out: they undo the change being committed.
+func ParseMigrations(files []git.File) []SchemaChange {
+	var changes []SchemaChange
+	for _, f := range files {
+		if f.Deleted || !isMigrationFile(f.Path) || isDownMigration(f.Path) {
+			continue
+		}
+
+		for _, statement := range migrationStatements(f.Added) {
+			if change, ok := parseDDL(statement); ok {
+				changes = append(changes, change)
+			}
+		}
+	}
+	return changes
+}
+
+// migrationStatements joins added lines into statements on one line each,
+// split at semicolons and at the start of migration calls.
+func migrationStatements(lines []string) []string {
+	var statements []string
+	var current strings.Builder
+	flush := func() {
+		if s := strings.Join(strings.Fields(current.String()), " "); s != "" {
+			statements = append(statements, s)
+		}
+		current.Reset()
+	}
+
+	for _, line := range stripSQLComments(lines) {
+		if migrationCallStart.MatchString(strings.TrimSpace(line)) {
+			flush()
+		}
+		parts := strings.Split(line, ";")
+		for i, part := range parts {
+			current.WriteString(" " + part)
+			if i < len(parts)-1 {
+				flush()
+			}
+		}
+	}
+	flush()
+	return statements
+}
+
+func stripSQLComments(lines []string) []string {
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if i := strings.Index(line, "--"); i >= 0 {
+			line = line[:i]
+		}
+		kept = append(kept, line)
+	}
+	return kept
+}
+
+func parseDDL(statement string) (SchemaChange, bool) {
+	for _, p := range ddlPatterns {
+		m := p.pattern.FindStringSubmatch(statement)
+		if m == nil {
+			continue
+		}
+		parts := m[1:]
+		for i := range parts {
+			parts[i] = strings.Trim(parts[i], `"`)
+		}
+
+		// ADD CONSTRAINT and the like do not change columns
+		if (p.kind == ddlAddColumn || p.kind == ddlDropColumn) && slices.Contains(constraintKeywords, strings.ToLower(parts[1])) {
+			continue
+		}
+
+		change := SchemaChange{Kind: p.kind, Target: parts[0]}
+		switch {
+		case p.kind == ddlRenameColumn:
+			change.Target, change.To = parts[0]+"."+parts[1], parts[2]
+		case p.kind == ddlRenameTable:
+			change.To = parts[1]
+		case len(parts) > 1:
+			change.Target = parts[0] + "." + parts[1]
+		}
+		return change, true
+	}
+	return SchemaChange{}, false
+}
+
+// Breaking reports whether deployed code may fail against the new schema.
+func (c SchemaChange) Breaking() bool {
+	switch c.Kind {
+	case ddlDropTable, ddlRenameTable, ddlDropColumn, ddlRenameColumn, ddlChangeType, ddlNotNull:
+		return true
+	}
+	return false
+}
+
+func (c SchemaChange) String() string {
+	if c.To != "" {
+		return message.Text("migration."+c.Kind, c.Target, c.To)
+	}
+	return message.Text("migration."+c.Kind, c.Target)
+}
+
+// OnlyMigrations reports whether every file is a migration.
+func OnlyMigrations(files []git.File) bool {
+	if len(files) == 0 {
+		return false
+	}
+	for _, f := range files {
+		if !isMigrationFile(f.Path) {
+			return false
+		}
+	}
+	return true
+}
+
+// MigrationType is perf for index-only migrations and feat for other
+// schema changes.
+func MigrationType(changes []SchemaChange) string {
+	for _, c := range changes {
+		if c.Kind != ddlAddIndex {
+			return "feat"
+		}
+	}
+	return "perf"
+}
+
+// migrationSummary names one or two changes, or the table they share.
+func migrationSummary(changes []SchemaChange) string {
+	switch len(changes) {
+	case 1:
+		return changes[0].String()
+	case 2:
+		return message.Text("migration.two", changes[0], changes[1])
+	}
+
+	table, _, _ := strings.Cut(changes[0].Target, ".")
+	for _, c := range changes[1:] {
+		if t, _, _ := strings.Cut(c.Target, "."); t != table {
+			return message.Text("migration.many", len(changes))
+		}
+	}
+	return message.Text("migration.table", table)
+}
+
+// migrationBreakingFooter calls out changes that break deployed code.
+func migrationBreakingFooter(changes []SchemaChange) string {
+	var breaking []string
+	for _, c := range changes {
+		if c.Breaking() {
+			breaking = append(breaking, c.String())
+		}
+	}
+	if len(breaking) == 0 {
+		return ""
+	}
+	return "BREAKING CHANGE: " + message.Text("migration.breaking", strings.Join(breaking, ", "))
+}
@@ -0,0 +1,87 @@
+package summary
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/barisdilekci/commitz/internal/git"
+)
+
+func TestIsMigrationFile(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"db/migrations/0002_add_email.up.sql", true},
+		{"db/migrate/20240101_add_email.rb", true},
+		{"app/migrations/0002_user_email.py", true},
+		{"sql/V2__add_email.sql", true},
+		{"schema/0002_add_email.up.sql", true},
+		{"sql/queries.sql", false},
+		{"app/models.py", false},
+	}
+	for _, tt := range tests {
+		if got := isMigrationFile(tt.path); got != tt.want {
+			t.Errorf("isMigrationFile(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParseMigrations(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		added []string
+		want  []SchemaChange
+	}{
+		{"create table", "db/migrations/0001_users.up.sql", []string{"CREATE TABLE IF NOT EXISTS users (", "  id bigint", ");"},
+			[]SchemaChange{{Kind: ddlCreateTable, Target: "users"}}},
+		{"statements on one line", "db/migrations/0002.up.sql", []string{"ALTER TABLE users ADD COLUMN email text; CREATE INDEX users_email ON users (email);"},
+			[]SchemaChange{{Kind: ddlAddColumn, Target: "users.email"}, {Kind: ddlAddIndex, Target: "users.email"}}},
+		{"rename column", "db/migrations/0003.up.sql", []string{`ALTER TABLE "users" RENAME COLUMN "mail" TO "email";`},
+			[]SchemaChange{{Kind: ddlRenameColumn, Target: "users.mail", To: "email"}}},
+		{"constraint", "db/migrations/0004.up.sql", []string{"ALTER TABLE users ADD CONSTRAINT users_email UNIQUE (email); -- ADD COLUMN x"}, nil},
+		{"down migration", "db/migrations/0002.down.sql", []string{"ALTER TABLE users DROP COLUMN email;"}, nil},
+		{"rails", "db/migrate/20240101_add_email.rb", []string{"add_column :users, :email, :string", "add_index :users, :email"},
+			[]SchemaChange{{Kind: ddlAddColumn, Target: "users.email"}, {Kind: ddlAddIndex, Target: "users.email"}}},
+		{"django", "app/migrations/0002_user_email.py", []string{"migrations.RemoveField(", "    model_name='user',", "    name='email',", "),"},
+			[]SchemaChange{{Kind: ddlDropColumn, Target: "user.email"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseMigrations([]git.File{{Path: tt.path, Added: tt.added}})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMigrations() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrationSummary(t *testing.T) {
+	addEmail := SchemaChange{Kind: ddlAddColumn, Target: "users.email"}
+	addIndex := SchemaChange{Kind: ddlAddIndex, Target: "users.email"}
+	dropOrders := SchemaChange{Kind: ddlDropTable, Target: "orders"}
+
+	tests := []struct {
+		changes  []SchemaChange
+		summary  string
+		typ      string
+		breaking bool
+	}{
+		{[]SchemaChange{addIndex}, "add index on users.email", "perf", false},
+		{[]SchemaChange{addEmail, addIndex}, "add users.email column and add index on users.email", "feat", false},
+		{[]SchemaChange{addEmail, addIndex, {Kind: ddlNotNull, Target: "users.email"}}, "update users schema", "feat", true},
+		{[]SchemaChange{addEmail, addIndex, dropOrders}, "update database schema (3 changes)", "feat", true},
+	}
+	for _, tt := range tests {
+		if got := migrationSummary(tt.changes); got != tt.summary {
+			t.Errorf("migrationSummary(%v) = %q, want %q", tt.changes, got, tt.summary)
+		}
+		if got := MigrationType(tt.changes); got != tt.typ {
+			t.Errorf("MigrationType(%v) = %q, want %q", tt.changes, got, tt.typ)
+		}
+		if got := migrationBreakingFooter(tt.changes) != ""; got != tt.breaking {
+			t.Errorf("migrationBreakingFooter(%v) breaking = %v, want %v", tt.changes, got, tt.breaking)
+		}
+	}
+}
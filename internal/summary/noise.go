@@ -0,0 +1,35 @@
+package summary
+
+import (
+	"slices"
+
+	"github.com/barisdilekci/commitz/internal/detect"
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/barisdilekci/commitz/internal/message"
+)
+
+// noiseSummaries describes excluded files, one phrase per kind. Relevant
+// files explain why generated code was regenerated.
+func noiseSummaries(noise, relevant []git.File) []string {
+	var phrases []string
+	add := func(phrase string) {
+		if !slices.Contains(phrases, phrase) {
+			phrases = append(phrases, phrase)
+		}
+	}
+
+	for _, f := range noise {
+		switch f.Kind {
+		case detect.KindLockfile:
+			add(message.Text("noise.dependencies"))
+		case detect.KindVendored:
+			add(message.Text("noise.vendored"))
+		case detect.KindBinary:
+			add(message.Text("noise.binary"))
+		case detect.KindGenerated:
+			add(regenerationSummary(f, relevant))
+		}
+	}
+
+	return phrases
+}
@@ -0,0 +1,46 @@
+package summary
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/barisdilekci/commitz/internal/message"
+)
+
+// formatters names the usual formatter for a file extension, for summaries
+// like "reformat code (gofmt)".
+var formatters = map[string]string{
+	".go":    "gofmt",
+	".rs":    "rustfmt",
+	".py":    "black",
+	".js":    "prettier",
+	".jsx":   "prettier",
+	".ts":    "prettier",
+	".tsx":   "prettier",
+	".css":   "prettier",
+	".scss":  "prettier",
+	".c":     "clang-format",
+	".h":     "clang-format",
+	".cc":    "clang-format",
+	".cpp":   "clang-format",
+	".swift": "swift-format",
+	".kt":    "ktlint",
+	".rb":    "rubocop",
+	".tf":    "terraform fmt",
+	".dart":  "dart format",
+}
+
+// reformatSummary describes a whitespace-only change, naming the formatter
+// when all files share one.
+func reformatSummary(files []git.File) string {
+	formatter := ""
+	for _, f := range files {
+		name := formatters[strings.ToLower(filepath.Ext(f.Path))]
+		if name == "" || (formatter != "" && name != formatter) {
+			return message.Text("summary.reformat_default")
+		}
+		formatter = name
+	}
+	return message.Text("summary.reformat", formatter)
+}
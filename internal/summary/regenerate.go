@@ -0,0 +1,151 @@
+package summary
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/detect"
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/barisdilekci/commitz/internal/message"
+)
+
+// Generators with their own regeneration summaries. The names are part of
+// the message keys, e.g. noise.mocks.
+const (
+	genProtobuf = "protobuf"
+	genMocks    = "mocks"
+	genSwagger  = "swagger"
+	genSQLC     = "sqlc"
+	genGraphQL  = "graphql"
+	genMinified = "minified"
+)
+
+// generatorBanners map words in generated file headers to the generator.
+var generatorBanners = []struct {
+	marker    string
+	generator string
+}{
+	{"protoc-gen-", genProtobuf},
+	{"MockGen", genMocks},
+	{"mockery", genMocks},
+	{"counterfeiter", genMocks},
+	{"swaggo/swag", genSwagger},
+	{"go-swagger", genSwagger},
+	{"oapi-codegen", genSwagger},
+	{"openapi-generator", genSwagger},
+	{"sqlc", genSQLC},
+	{"gqlgen", genGraphQL},
+	{"graphql-codegen", genGraphQL},
+}
+
+// Changed lines that explain regenerated code: Go interface declarations
+// and methods, and swag annotations.
+var (
+	interfaceLinePattern = regexp.MustCompile(`\binterface\s*\{|^\s+[A-Z]\w*\([^)]*\)\s*\S`)
+	swagAnnotationLine   = regexp.MustCompile(`^\s*//\s*@(Router|Param|Success|Failure|Summary|Accept|Produce)\b`)
+)
+
+// generatorOf names the generator of a generated file from its path or
+// banner, or returns "" when it is unknown.
+func generatorOf(f git.File) string {
+	base := filepath.Base(f.Path)
+	slashed := filepath.ToSlash(f.Path)
+	switch {
+	case strings.Contains(base, ".pb.") || strings.Contains(base, "_pb2"):
+		return genProtobuf
+	case strings.HasPrefix(base, "mock_") || strings.HasSuffix(base, "_mock.go") || strings.Contains(slashed, "mocks/"):
+		return genMocks
+	case strings.Contains(base, ".min."):
+		return genMinified
+	}
+
+	for _, line := range f.Added {
+		if !strings.Contains(line, "Code generated") && !strings.Contains(line, "@generated") {
+			continue
+		}
+		for _, b := range generatorBanners {
+			if strings.Contains(line, b.marker) {
+				return b.generator
+			}
+		}
+	}
+	return ""
+}
+
+// generatorInput reports whether a hand-written file is an input of the
+// generator, such as a .proto file for protobuf code.
+func generatorInput(generator string, f git.File) bool {
+	ext := strings.ToLower(filepath.Ext(f.Path))
+	base := strings.ToLower(filepath.Base(f.Path))
+	switch generator {
+	case genProtobuf:
+		return ext == ".proto"
+	case genSQLC:
+		return ext == ".sql"
+	case genGraphQL:
+		return ext == ".graphql" || ext == ".graphqls" || ext == ".gql"
+	case genSwagger:
+		if strings.HasPrefix(base, "openapi.") || strings.HasPrefix(base, "swagger.") {
+			return true
+		}
+		return ext == ".go" && changedLineMatches(f, swagAnnotationLine)
+	case genMocks:
+		return ext == ".go" && changedLineMatches(f, interfaceLinePattern)
+	}
+	return false
+}
+
+func changedLineMatches(f git.File, pattern *regexp.Regexp) bool {
+	for _, lines := range [][]string{f.Added, f.Removed} {
+		for _, line := range lines {
+			if pattern.MatchString(line) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// regenerationSummary describes a regenerated file, naming the hand-written
+// change that caused it when one is staged too.
+func regenerationSummary(f git.File, relevant []git.File) string {
+	generator := generatorOf(f)
+	if generator == "" {
+		return message.Text("noise.generated")
+	}
+
+	key := "noise." + generator
+	for _, r := range relevant {
+		if generatorInput(generator, r) && message.HasText(key+"_after") {
+			return message.Text(key + "_after")
+		}
+	}
+	return message.Text(key)
+}
+
+// onlyGeneratorInputs reports whether every hand-written file is an input
+// of the regenerated files, so the change is best described as the
+// regeneration. Go source is never only an input: a changed interface is
+// the change, its mocks follow from it.
+func onlyGeneratorInputs(relevant, noise []git.File) bool {
+	if len(relevant) == 0 {
+		return false
+	}
+	for _, r := range relevant {
+		if strings.EqualFold(filepath.Ext(r.Path), ".go") {
+			return false
+		}
+		input := false
+		for _, f := range noise {
+			if f.Kind == detect.KindGenerated && generatorInput(generatorOf(f), r) {
+				input = true
+				break
+			}
+		}
+		if !input {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,141 @@
+package summary
+
+import (
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/detect"
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/barisdilekci/commitz/internal/message"
+)
+
+// Smart suggests a summary from the diff, the files and the commit type:
+// what was regenerated, migrated, reconfigured or removed, or a phrase for
+// the type about the change's topic.
+func Smart(diff string, files []git.File, commitType string, gitOutput Git) string {
+	if commitType == "style" && detect.OnlyWhitespace(files) {
+		return reformatSummary(files)
+	}
+
+	relevant, noise := detect.SplitNoise(files)
+	if len(relevant) == 0 && len(noise) > 0 {
+		return noiseSummaries(noise, nil)[0]
+	}
+	// A changed .proto file with its regenerated code is a regeneration
+	if onlyGeneratorInputs(relevant, noise) {
+		for _, f := range noise {
+			if f.Kind == detect.KindGenerated {
+				return regenerationSummary(f, relevant)
+			}
+		}
+	}
+	if len(noise) > 0 {
+		diff, files = detect.DiffText(relevant), relevant
+	}
+
+	if OnlyMigrations(files) {
+		if changes := ParseMigrations(files); len(changes) > 0 {
+			return migrationSummary(changes)
+		}
+	}
+	if OnlyConfigFiles(files) {
+		if summary, _, ok := ConfigSummary(files, gitOutput); ok {
+			return summary
+		}
+	}
+
+	diffLower := strings.ToLower(diff)
+
+	// Pure deletions get a removal summary regardless of type
+	if detect.OnlyDeletions(files) {
+		return removalSummary(files)
+	}
+
+	// Generate smart summary based on commit type and changes
+	switch commitType {
+	case "feat":
+		if strings.Contains(diffLower, "interactive") {
+			return message.Text("summary.add_interactive")
+		}
+		if strings.Contains(diffLower, "api") {
+			return message.Text("summary.add_api")
+		}
+		if topic := Topic(files); topic != "" {
+			return message.Text("summary.add_topic", topic)
+		}
+		return message.Text("summary.add_default")
+
+	case "fix":
+		if strings.Contains(diffLower, "bug") || strings.Contains(diffLower, "error") {
+			return message.Text("summary.fix_error_handling")
+		}
+		if topic := Topic(files); topic != "" {
+			return message.Text("summary.fix_topic", topic)
+		}
+		return message.Text("summary.fix_default")
+
+	case "docs":
+		if strings.Contains(diffLower, "readme") {
+			return message.Text("summary.docs_readme")
+		}
+		return message.Text("summary.docs_default")
+
+	case "refactor":
+		if topic := Topic(files); topic != "" {
+			return message.Text("summary.refactor_topic", topic)
+		}
+		return message.Text("summary.refactor_default")
+
+	case "test":
+		return message.Text("summary.test")
+
+	case "style":
+		return message.Text("summary.style")
+
+	case "perf":
+		return message.Text("summary.perf")
+
+	case "build":
+		if strings.Contains(diffLower, "go.mod") || strings.Contains(diffLower, "go.sum") {
+			return message.Text("summary.build_deps")
+		}
+		return message.Text("summary.build_default")
+
+	case "ci":
+		return message.Text("summary.ci")
+
+	case "chore":
+		if strings.Contains(diffLower, "cleanup") {
+			return message.Text("summary.chore_cleanup")
+		}
+		return message.Text("summary.chore_default")
+	}
+
+	return message.Text("summary.default")
+}
+
+// removalSummary names one or two removed files, or counts them.
+func removalSummary(files []git.File) string {
+	switch len(files) {
+	case 1:
+		return message.Text("summary.remove_one", baseName(files[0].Path))
+	case 2:
+		return message.Text("summary.remove_two", baseName(files[0].Path), baseName(files[1].Path))
+	}
+
+	if dir := CommonDir(files); dir != "" {
+		return message.Text("summary.remove_dir", len(files), dir)
+	}
+	return message.Text("summary.remove_many", len(files))
+}
+
+// baseName is a file's name without its directory and source extension.
+func baseName(filePath string) string {
+	// Remove extension and get base name
+	parts := strings.Split(filePath, "/")
+	fileName := parts[len(parts)-1]
+	fileName = strings.TrimSuffix(fileName, ".go")
+	fileName = strings.TrimSuffix(fileName, ".js")
+	fileName = strings.TrimSuffix(fileName, ".ts")
+	fileName = strings.TrimSuffix(fileName, ".md")
+	return fileName
+}
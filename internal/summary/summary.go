@@ -0,0 +1,268 @@
+// Package summary drafts the subject and body of a commit message from the
+// staged files: their names, declared symbols, schema migrations, config
+// keys and exported Go API.
+package summary
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/barisdilekci/commitz/internal/detect"
+	"github.com/barisdilekci/commitz/internal/git"
+	"github.com/barisdilekci/commitz/internal/message"
+)
+
+// manyFilesThreshold is the file count from which summaries are synthesized
+// from the overall change instead of a single file.
+const manyFilesThreshold = 3
+
+// dominantRatio is the share of changed lines a single file needs to be
+// considered the main subject of a multi-file change.
+const dominantRatio = 0.6
+
+var symbolDeclPattern = regexp.MustCompile(
+	`(?:func\s+(?:\([^)]*\)\s*)?|type\s+|class\s+|def\s+|function\s+|interface\s+|struct\s+)([A-Za-z_][A-Za-z0-9_]*)`,
+)
+
+// maxBulletSymbols caps how many symbols a body bullet names.
+const maxBulletSymbols = 3
+
+// maxSummaryCandidates caps how many alternative summaries are offered.
+const maxSummaryCandidates = 4
+
+// Git runs a read-only git command in the repository and returns its
+// output. Config and API changes are read through it from HEAD and the
+// index.
+type Git func(args ...string) ([]byte, error)
+
+// Candidates offers alternative file, symbol and directory based phrasings
+// next to the primary suggestion.
+func Candidates(primary string, files []git.File, commitType string) []string {
+	candidates := []string{primary}
+
+	relevant, _ := detect.SplitNoise(files)
+	if len(relevant) == 0 || detect.OnlyDeletions(relevant) {
+		return candidates
+	}
+
+	key := "candidate." + commitType
+	if !message.HasText(key) {
+		key = "candidate.default"
+	}
+
+	var topics []string
+	if f, ok := dominantFile(relevant); ok || len(relevant) == 1 {
+		topics = append(topics, baseName(f.Path))
+	} else {
+		topics = append(topics, baseName(relevant[0].Path))
+	}
+	if symbol := sharedSymbol(relevant); symbol != "" {
+		topics = append(topics, symbol)
+	} else if symbol := firstSymbol(relevant); symbol != "" {
+		topics = append(topics, symbol)
+	}
+	if dir := CommonDir(relevant); dir != "" {
+		topics = append(topics, dir)
+	}
+
+	for _, topic := range topics {
+		candidate := message.Text(key, topic)
+		if topic != "" && !slices.Contains(candidates, candidate) && len(candidates) < maxSummaryCandidates {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	return candidates
+}
+
+// firstSymbol returns the first symbol declared in the added lines.
+func firstSymbol(files []git.File) string {
+	for _, f := range files {
+		for _, line := range f.Added {
+			if m := symbolDeclPattern.FindStringSubmatch(line); m != nil {
+				return m[1]
+			}
+		}
+	}
+	return ""
+}
+
+// Topic picks the noun a summary should talk about: a symbol most files
+// share, the file holding most of the change or their common directory.
+func Topic(files []git.File) string {
+	if len(files) == 0 {
+		return ""
+	}
+	// A symbol the tests exercise too still names the change
+	if len(files) > 1 {
+		if symbol := sharedSymbol(files); symbol != "" {
+			return symbol
+		}
+	}
+
+	files = withoutTests(files)
+	if len(files) == 1 {
+		return baseName(files[0].Path)
+	}
+	if f, ok := dominantFile(files); ok {
+		return baseName(f.Path)
+	}
+	return CommonDir(files)
+}
+
+// sharedSymbol finds a declared symbol that is touched in most changed files.
+func sharedSymbol(files []git.File) string {
+	var symbols []string
+	for _, f := range files {
+		for _, line := range f.Added {
+			for _, m := range symbolDeclPattern.FindAllStringSubmatch(line, -1) {
+				if !slices.Contains(symbols, m[1]) {
+					symbols = append(symbols, m[1])
+				}
+			}
+		}
+	}
+
+	best, bestCount := "", 0
+	for _, symbol := range symbols {
+		word := regexp.MustCompile(`\b` + regexp.QuoteMeta(symbol) + `\b`)
+		count := 0
+		for _, f := range files {
+			if linesMatch(f.Added, word) || linesMatch(f.Removed, word) {
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = symbol, count
+		}
+	}
+
+	if bestCount >= 2 && bestCount*2 >= len(files) {
+		return best
+	}
+	return ""
+}
+
+// dominantFile returns the file holding most of the changed lines, if any.
+func dominantFile(files []git.File) (git.File, bool) {
+	files = withoutTests(files)
+	total := 0
+	var top git.File
+	for _, f := range files {
+		changed := f.Additions + f.Deletions
+		total += changed
+		if changed > top.Additions+top.Deletions {
+			top = f
+		}
+	}
+
+	if total == 0 {
+		return git.File{}, false
+	}
+	return top, float64(top.Additions+top.Deletions)/float64(total) >= dominantRatio
+}
+
+// withoutTests drops the test files when code changed too, so the tests
+// that come with a change do not name it.
+func withoutTests(files []git.File) []git.File {
+	if _, others := detect.SplitTests(files); len(others) > 0 {
+		return others
+	}
+	return files
+}
+
+// describeFileChange renders a single body bullet for a changed file.
+func describeFileChange(f git.File) string {
+	switch {
+	case f.Deleted:
+		return message.Text("change.remove", f.Path)
+	case f.OldPath != "" && f.OldPath != f.Path:
+		return message.Text("change.rename", f.OldPath, f.Path)
+	case git.IsTestFile(f.Path) && f.IsNew:
+		return message.Text("change.add_tests", f.Path)
+	case git.IsTestFile(f.Path):
+		return message.Text("change.update_tests", f.Path)
+	case f.IsNew:
+		return message.Text("change.add", f.Path)
+	case f.Binary:
+		return message.Text("change.binary", f.Path)
+	}
+	if symbols := fileSymbols(f); len(symbols) > 0 {
+		return message.Text("change.update_symbols", f.Path, strings.Join(symbols, ", "), f.Additions, f.Deletions)
+	}
+	return message.Text("change.update", f.Path, f.Additions, f.Deletions)
+}
+
+// fileSymbols names the declarations a change is in or touches, from hunk
+// headers and changed lines.
+func fileSymbols(f git.File) []string {
+	var symbols []string
+	for _, lines := range [][]string{f.Context, f.Added, f.Removed} {
+		for _, line := range lines {
+			m := symbolDeclPattern.FindStringSubmatch(line)
+			if m == nil || slices.Contains(symbols, m[1]) {
+				continue
+			}
+			if len(symbols) == maxBulletSymbols {
+				return symbols
+			}
+			symbols = append(symbols, m[1])
+		}
+	}
+	return symbols
+}
+
+// subjectCovers reports whether the subject speaks for every file, either
+// by naming each of them or a symbol they share. A single file always is.
+func subjectCovers(files []git.File, subject string) bool {
+	if len(files) < 2 {
+		return true
+	}
+	names := func(word string) bool {
+		return regexp.MustCompile(`\b` + regexp.QuoteMeta(word) + `\b`).MatchString(subject)
+	}
+	if symbol := sharedSymbol(files); symbol != "" && names(symbol) {
+		return true
+	}
+	for _, f := range files {
+		if !names(baseName(f.Path)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Shorten replaces a suggested summary that is too long for the header
+// with a shorter one about the files' common directory. The body then
+// lists the changes the subject no longer names.
+func Shorten(summary, commitType string, files []git.File) string {
+	relevant, _ := detect.SplitNoise(files)
+	dir := CommonDir(relevant)
+	if detect.OnlyDeletions(relevant) {
+		if dir != "" {
+			return message.Text("summary.remove_dir", len(relevant), dir)
+		}
+		return message.Text("summary.remove_many", len(relevant))
+	}
+
+	key := "candidate." + commitType
+	if !message.HasText(key) {
+		key = "candidate.default"
+	}
+	if dir != "" {
+		if short := message.Text(key, dir); short != summary {
+			return short
+		}
+	}
+	return message.Text("summary.default")
+}
+
+func linesMatch(lines []string, pattern *regexp.Regexp) bool {
+	for _, line := range lines {
+		if pattern.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
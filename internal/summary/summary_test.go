@@ -0,0 +1,198 @@
+package summary
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/barisdilekci/commitz/internal/detect"
+	"github.com/barisdilekci/commitz/internal/git"
+)
+
+// stagedFile renders a minimal diff adding lines to path.
+func stagedFile(path string, isNew bool, added ...string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "diff --git a/%s b/%s\n", path, path)
+	if isNew {
+		b.WriteString("new file mode 100644\n")
+	}
+	fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n@@ -1 +1,%d @@\n", path, path, len(added))
+	for _, line := range added {
+		b.WriteString("+" + line + "\n")
+	}
+	return b.String()
+}
+
+// deletedFile renders a minimal diff deleting path.
+func deletedFile(path string) string {
+	return fmt.Sprintf("diff --git a/%s b/%s\ndeleted file mode 100644\n--- a/%s\n+++ /dev/null\n@@ -1 +0,0 @@\n-gone\n", path, path, path)
+}
+
+// parseFiles parses a diff and marks the files detect excludes, as the
+// staged files are before they are summarized.
+func parseFiles(diff string) []git.File {
+	files := git.ParseDiff(diff, git.Options{})
+	detect.Classify(files, nil)
+	return files
+}
+
+// fakeGit answers git show, ls-tree and ls-files from objects, which maps
+// names like "HEAD:store/store.go" and ":store/store.go" to contents.
+func fakeGit(objects map[string]string) Git {
+	return func(args ...string) ([]byte, error) {
+		switch args[0] {
+		case "show":
+			if content, ok := objects[args[1]]; ok {
+				return []byte(content), nil
+			}
+		case "ls-tree":
+			tree := strings.TrimPrefix(args[len(args)-1], "HEAD:")
+			var names []string
+			for name := range objects {
+				if p, ok := strings.CutPrefix(name, "HEAD:"); ok && path.Dir(p) == path.Clean("./"+tree) {
+					names = append(names, path.Base(p))
+				}
+			}
+			return []byte(strings.Join(names, "\n")), nil
+		case "ls-files":
+			pattern := strings.TrimPrefix(args[len(args)-1], ":(top,glob)")
+			var paths []string
+			for name := range objects {
+				if p, ok := strings.CutPrefix(name, ":"); ok {
+					if match, _ := path.Match(pattern, p); match {
+						paths = append(paths, p)
+					}
+				}
+			}
+			return []byte(strings.Join(paths, "\n")), nil
+		}
+		return nil, errors.New("fatal: not found")
+	}
+}
+
+func TestSmart(t *testing.T) {
+	tests := []struct {
+		name       string
+		diff       string
+		commitType string
+		objects    map[string]string
+		want       string
+	}{
+		{"interactive feature", stagedFile("cmd/root.go", false, "interactive = true"), "feat", nil, "add interactive mode"},
+		{"error handling", stagedFile("pkg/a.go", false, "return err // error"), "fix", nil, "fix bug in error handling"},
+		{"topic", stagedFile("store/cache.go", false, "x := 1"), "refactor", nil, "refactor cache"},
+		{"deleted file", deletedFile("pkg/old.go"), "refactor", nil, "remove old"},
+		{"deleted files", deletedFile("pkg/a.go") + deletedFile("pkg/b.go") + deletedFile("pkg/c.go"), "chore", nil, "remove 3 files from pkg"},
+		{"code with larger test", stagedFile("auth/login.go", false, "return nil") +
+			stagedFile("auth/login_test.go", false, "a := 1", "b := 2", "c := 3", "d := 4"), "feat", nil, "add login functionality"},
+		{"shared symbol", stagedFile("store/get.go", false, "func Lookup() {}") +
+			stagedFile("web/handler.go", false, "v := Lookup()"), "feat", nil, "add Lookup functionality"},
+		{"lockfile only", stagedFile("go.sum", false, "example.com/x v1.0.0 h1:abc="), "build", nil, "update dependencies"},
+		{"regenerated protobuf", stagedFile("api/service.proto", false, "message Page {}") +
+			stagedFile("api/service.pb.go", false, "// Code generated by protoc-gen-go. DO NOT EDIT."), "chore", nil,
+			"regenerate protobuf code after .proto changes"},
+		{"noise left out", stagedFile("store/cache.go", false, "x := 1") + stagedFile("go.sum", false, "h1:abc="), "refactor", nil, "refactor cache"},
+		{"migration", stagedFile("db/migrations/0002_email.up.sql", true, "ALTER TABLE users ADD COLUMN email text;"), "feat", nil, "add users.email column"},
+		{"config", stagedFile("config/client.yaml", false, "  enabled: true"), "chore",
+			map[string]string{"HEAD:config/client.yaml": "retries:\n  enabled: false\n", ":config/client.yaml": "retries:\n  enabled: true\n"},
+			"enable retries in client config"},
+		{"config not readable", stagedFile("config/client.yaml", false, "  enabled: true"), "chore", nil, "update project files"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Smart(tt.diff, parseFiles(tt.diff), tt.commitType, fakeGit(tt.objects))
+			if got != tt.want {
+				t.Errorf("Smart() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReformatSummary(t *testing.T) {
+	tests := []struct {
+		paths []string
+		want  string
+	}{
+		{[]string{"main.go", "cmd/root.go"}, "reformat code (gofmt)"},
+		{[]string{"web/app.tsx", "web/style.css"}, "reformat code (prettier)"},
+		{[]string{"main.go", "web/app.ts"}, "reformat code"},
+		{[]string{"notes.txt"}, "reformat code"},
+	}
+	for _, tt := range tests {
+		var files []git.File
+		for _, p := range tt.paths {
+			files = append(files, git.File{Path: p, OldPath: p, WhitespaceOnly: true})
+		}
+		if got := Smart("", files, "style", fakeGit(nil)); got != tt.want {
+			t.Errorf("Smart(%v) = %q, want %q", tt.paths, got, tt.want)
+		}
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	tests := []struct {
+		name       string
+		diff       string
+		commitType string
+		want       []string
+	}{
+		{"one file", stagedFile("store/cache.go", false, "func Evict() {}"), "feat",
+			[]string{"primary", "add cache", "add Evict", "add store"}},
+		{"unknown type", stagedFile("cache.go", false, "x := 1"), "wip", []string{"primary", "update cache"}},
+		{"deletions", deletedFile("store/cache.go"), "refactor", []string{"primary"}},
+		{"noise only", stagedFile("go.sum", false, "h1:abc="), "build", []string{"primary"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Candidates("primary", parseFiles(tt.diff), tt.commitType); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Candidates() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommonDir(t *testing.T) {
+	tests := []struct {
+		paths []string
+		want  string
+	}{
+		{nil, ""},
+		{[]string{"store/cache.go"}, "store"},
+		{[]string{"store/cache/lru.go", "store/cache/ttl.go"}, "store/cache"},
+		{[]string{"store/cache/lru.go", "store/db.go"}, "store"},
+		{[]string{"main.go", "store/db.go"}, ""},
+	}
+	for _, tt := range tests {
+		var files []git.File
+		for _, p := range tt.paths {
+			files = append(files, git.File{Path: p})
+		}
+		if got := CommonDir(files); got != tt.want {
+			t.Errorf("CommonDir(%v) = %q, want %q", tt.paths, got, tt.want)
+		}
+	}
+}
+
+func TestShorten(t *testing.T) {
+	tests := []struct {
+		name       string
+		summary    string
+		diff       string
+		commitType string
+		want       string
+	}{
+		{"common directory", "add cache and store and index", stagedFile("store/cache.go", false, "x") + stagedFile("store/index.go", false, "y"), "feat", "add store"},
+		{"no directory", "add cache and main", stagedFile("cache.go", false, "x") + stagedFile("main.go", false, "y"), "feat", "update changes"},
+		{"deletions", "remove cache and index", deletedFile("store/cache.go") + deletedFile("store/index.go"), "refactor", "remove 2 files from store"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Shorten(tt.summary, tt.commitType, parseFiles(tt.diff)); got != tt.want {
+				t.Errorf("Shorten() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
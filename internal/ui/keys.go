@@ -0,0 +1,178 @@
+// Package ui holds the terminal prompt pieces that do not depend on the
+// commitz config or locale: key bindings, quick select and Esc handling.
+package ui
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/barisdilekci/commitz/internal/config"
+	"github.com/chzyer/readline"
+	"github.com/manifoldco/promptui"
+)
+
+// What keys.escape makes Esc do in a selector.
+const (
+	EscapeCancel  = "cancel"
+	EscapeDefault = "default"
+)
+
+// namedKeys are the key names keys.* accept besides single characters and
+// ctrl-<letter>.
+var namedKeys = map[string]promptui.Key{
+	"up":    {Code: readline.CharPrev, Display: "↑"},
+	"down":  {Code: readline.CharNext, Display: "↓"},
+	"left":  {Code: readline.CharBackward, Display: "←"},
+	"right": {Code: readline.CharForward, Display: "→"},
+	"tab":   {Code: readline.CharTab, Display: "tab"},
+}
+
+// ColorCodePattern matches the ANSI color codes in colored labels.
+var ColorCodePattern = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// ParseKey reads a key name like "k", "down" or "ctrl-n".
+func ParseKey(name string) (promptui.Key, bool) {
+	lower := strings.ToLower(name)
+	if key, ok := namedKeys[lower]; ok {
+		return key, true
+	}
+	if letter, ok := strings.CutPrefix(lower, "ctrl-"); ok && len(letter) == 1 && letter[0] >= 'a' && letter[0] <= 'z' {
+		return promptui.Key{Code: rune(letter[0]-'a') + 1, Display: "ctrl-" + letter}, true
+	}
+	if utf8.RuneCountInString(name) == 1 {
+		r, _ := utf8.DecodeRuneInString(name)
+		return promptui.Key{Code: r, Display: name}, true
+	}
+	return promptui.Key{}, false
+}
+
+// SelectKeys builds the selector key bindings from settings, starting from
+// promptui's arrows and "/" for search. Key names it cannot read keep the
+// default binding and are returned.
+func SelectKeys(settings config.KeysConfig) (*promptui.SelectKeys, []string) {
+	keys := &promptui.SelectKeys{
+		Prev:     namedKeys["up"],
+		Next:     namedKeys["down"],
+		PageUp:   namedKeys["left"],
+		PageDown: namedKeys["right"],
+		Search:   promptui.Key{Code: '/', Display: "/"},
+	}
+
+	var invalid []string
+	for _, binding := range []struct {
+		name string
+		key  *promptui.Key
+	}{
+		{settings.Prev, &keys.Prev},
+		{settings.Next, &keys.Next},
+		{settings.PageUp, &keys.PageUp},
+		{settings.PageDown, &keys.PageDown},
+		{settings.Search, &keys.Search},
+	} {
+		if binding.name == "" {
+			continue
+		}
+		key, ok := ParseKey(binding.name)
+		if !ok {
+			invalid = append(invalid, binding.name)
+			continue
+		}
+		*binding.key = key
+	}
+	return keys, invalid
+}
+
+// QuickSelect starts a selector in search mode, where typing the number
+// shown next to an item picks it. Selectors with their own searcher are
+// left alone.
+func QuickSelect(prompt *promptui.Select) {
+	if prompt.Searcher != nil {
+		return
+	}
+	items := reflect.ValueOf(prompt.Items)
+	if items.Kind() != reflect.Slice {
+		return
+	}
+	prompt.Searcher = func(input string, index int) bool {
+		input = strings.TrimSpace(input)
+		if n, err := strconv.Atoi(input); err == nil {
+			return n == index+1
+		}
+		label := fmt.Sprint(items.Index(index).Interface())
+		return strings.Contains(strings.ToLower(label), strings.ToLower(input))
+	}
+	prompt.StartInSearchMode = true
+	numberItems(prompt, items)
+}
+
+// numberItems shows each item's number in front of it. Search hides items,
+// so the number is looked up in the full list rather than counted.
+func numberItems(prompt *promptui.Select, items reflect.Value) {
+	templates := promptui.SelectTemplates{}
+	if prompt.Templates != nil {
+		templates = *prompt.Templates
+	}
+	if templates.Active == "" {
+		templates.Active = promptui.IconSelect + " {{ . | underline }}"
+	}
+	if templates.Inactive == "" {
+		templates.Inactive = "  {{ . }}"
+	}
+	funcs := template.FuncMap{}
+	maps.Copy(funcs, promptui.FuncMap)
+	maps.Copy(funcs, templates.FuncMap)
+	width := len(strconv.Itoa(items.Len()))
+	funcs["itemNumber"] = func(item any) string {
+		for i := range items.Len() {
+			if reflect.DeepEqual(items.Index(i).Interface(), item) {
+				return fmt.Sprintf("%*d", width, i+1)
+			}
+		}
+		return strings.Repeat(" ", width)
+	}
+	templates.FuncMap = funcs
+	templates.Active = "{{ itemNumber . | faint }} " + templates.Active
+	templates.Inactive = "{{ itemNumber . | faint }} " + templates.Inactive
+	prompt.Templates = &templates
+}
+
+// EscReader hands a selector the keyboard with a lone Esc passed on as
+// ctrl-c, which ends the selector, and remembers that it was Esc. Escape
+// sequences such as the arrow keys arrive in one read and pass through.
+type EscReader struct {
+	r       io.Reader
+	pressed atomic.Bool
+}
+
+// NewEscReader reads keys from r.
+func NewEscReader(r io.Reader) *EscReader {
+	return &EscReader{r: r}
+}
+
+func (e *EscReader) Read(p []byte) (int, error) {
+	n, err := e.r.Read(p)
+	if n == 1 && p[0] == readline.CharEsc {
+		p[0] = readline.CharInterrupt
+		e.pressed.Store(true)
+	}
+	return n, err
+}
+
+// Close leaves the terminal open for the next prompt.
+func (e *EscReader) Close() error { return nil }
+
+// Pressed reports whether Esc, rather than ctrl-c, ended the selector.
+func (e *EscReader) Pressed() bool { return e.pressed.Load() }
+
+// PlainLabel renders a prompt label as text without colors.
+func PlainLabel(label any) string {
+	return strings.TrimSuffix(ColorCodePattern.ReplaceAllString(fmt.Sprint(label), ""), "?")
+}
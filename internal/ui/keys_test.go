@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/barisdilekci/commitz/internal/config"
+	"github.com/chzyer/readline"
+)
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		name string
+		code rune
+		ok   bool
+	}{
+		{"k", 'k', true},
+		{"Down", readline.CharNext, true},
+		{"ctrl-n", readline.CharNext, true},
+		{"ctrl-P", readline.CharPrev, true},
+		{"ctrl-1", 0, false},
+		{"pagedown", 0, false},
+	}
+	for _, tt := range tests {
+		key, ok := ParseKey(tt.name)
+		if key.Code != tt.code || ok != tt.ok {
+			t.Errorf("ParseKey(%q) = %d, %v; want %d, %v", tt.name, key.Code, ok, tt.code, tt.ok)
+		}
+	}
+}
+
+func TestSelectKeys(t *testing.T) {
+	keys, invalid := SelectKeys(config.KeysConfig{Next: "j", Prev: "k", Search: "pagedown"})
+	if keys.Next.Code != 'j' || keys.Prev.Code != 'k' {
+		t.Errorf("next, prev = %q, %q; want j, k", keys.Next.Code, keys.Prev.Code)
+	}
+	if keys.Search.Code != '/' {
+		t.Errorf("search = %q, want the default /", keys.Search.Code)
+	}
+	if len(invalid) != 1 || invalid[0] != "pagedown" {
+		t.Errorf("invalid = %q, want [pagedown]", invalid)
+	}
+}
+
+func TestEscReader(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		pressed bool
+	}{
+		{"\x1b", string(rune(readline.CharInterrupt)), true},
+		// Arrow keys start with Esc too
+		{"\x1b[A", "\x1b[A", false},
+		{"j", "j", false},
+	}
+	for _, tt := range tests {
+		esc := NewEscReader(strings.NewReader(tt.input))
+		got, err := io.ReadAll(esc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want || esc.Pressed() != tt.pressed {
+			t.Errorf("read %q = %q, pressed %v; want %q, %v", tt.input, got, esc.Pressed(), tt.want, tt.pressed)
+		}
+	}
+}
+
+func TestPlainLabel(t *testing.T) {
+	if got := PlainLabel("\x1b[36mCommit type\x1b[0m?"); got != "Commit type" {
+		t.Errorf("PlainLabel() = %q, want %q", got, "Commit type")
+	}
+}